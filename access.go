@@ -0,0 +1,104 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Regras de IP de um grupo de endpoints
+type accessRule struct {
+	Allow []*net.IPNet // Se preenchida, apenas estas redes podem acessar
+	Deny  []*net.IPNet // Redes sempre recusadas (avaliadas antes da lista de permitidas)
+}
+
+// Configurações da seção [access], por grupo de endpoints
+type AccessConfig struct {
+	UI    accessRule // Dashboard, WebSocket e login
+	API   accessRule // API de consulta, métricas, feeds, badges, GraphQL e Grafana
+	Admin accessRule // Rotas que exigem o papel admin (tokens, configurações)
+}
+
+// Função para ler a seção [access] do config.ini
+func loadAccessConfig(cfg *ini.File) AccessConfig {
+	section := cfg.Section("access")
+	rule := func(prefix string) accessRule {
+		return accessRule{
+			Allow: parseCIDRs(section.Key(prefix + "_allow").Strings(",")),
+			Deny:  parseCIDRs(section.Key(prefix + "_deny").Strings(",")),
+		}
+	}
+	return AccessConfig{UI: rule("ui"), API: rule("api"), Admin: rule("admin")}
+}
+
+// Função para interpretar uma lista de redes CIDR (IPs sem máscara valem como /32 ou /128)
+func parseCIDRs(values []string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			log.Printf(tr("Rede %q ignorada na seção [access]: %v\n"), value, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// Função para verificar se o IP pertence a alguma das redes
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Função para verificar se o IP pode acessar os endpoints da regra
+func (rule accessRule) allows(ip net.IP) bool {
+	if ip == nil {
+		return len(rule.Allow) == 0 && len(rule.Deny) == 0
+	}
+	if containsIP(rule.Deny, ip) {
+		return false
+	}
+	return len(rule.Allow) == 0 || containsIP(rule.Allow, ip)
+}
+
+// Função para classificar a requisição no grupo de endpoints correspondente
+func accessGroup(r *http.Request, config AccessConfig) (string, accessRule) {
+	if requiredRole(r) == roleAdmin {
+		return "admin", config.Admin
+	}
+	path := r.URL.Path
+	for _, prefix := range []string{"/api/", "/status.json", "/metrics", "/graphql", "/grafana/", "/feed.xml", "/badge/", "/healthz", "/readyz"} {
+		if strings.HasPrefix(path, prefix) {
+			return "api", config.API
+		}
+	}
+	return "ui", config.UI
+}
+
+// Middleware que aplica as listas de IPs permitidos/recusados por grupo de endpoints
+func accessMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group, rule := accessGroup(r, getConfig().Access)
+		if !rule.allows(net.ParseIP(clientIP(r))) {
+			log.Printf(tr("Acesso de %s a %s (%s) recusado pela seção [access]\n"), clientIP(r), r.URL.Path, group)
+			http.Error(w, "Acesso não permitido a partir deste endereço", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Reconhecimento de uma queda por um operador; é descartado quando o serviço volta
+type acknowledgment struct {
+	User    string    `json:"User"`
+	Comment string    `json:"Comment,omitempty"`
+	Time    time.Time `json:"Time"`
+}
+
+// Função para obter o reconhecimento da queda atual do serviço (nil se não houver)
+func acknowledgmentOf(description string) *acknowledgment {
+	alertMu.Lock()
+	defer alertMu.Unlock()
+	if state, ok := alertStates[description]; ok {
+		return state.Ack
+	}
+	return nil
+}
+
+// Handler para reconhecer a queda de um serviço vermelho: {"comment": "..."} (opcional)
+func ackServiceHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Comment string `json:"comment"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	i, ok := findServiceByID(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+	service := &latestServicesState[i]
+	if service.Status != "red" {
+		http.Error(w, "Apenas serviços vermelhos podem ser reconhecidos", http.StatusConflict)
+		return
+	}
+
+	ack := &acknowledgment{User: currentUser(r), Comment: request.Comment, Time: time.Now()}
+	alertMu.Lock()
+	state, ok := alertStates[service.Description]
+	if !ok {
+		state = &alertState{}
+		alertStates[service.Description] = state
+	}
+	previous := state.Ack
+	state.Ack = ack
+	saved := *state
+	alertMu.Unlock()
+	saveAlertState(service.Description, saved)
+	service.Acknowledged = ack
+	hub.update(*service)
+
+	log.Printf(tr("Queda do serviço [%s] reconhecida por %s\n"), service.Description, ack.User)
+	auditRequest(r, "service.ack", service.Description, previous, ack)
+	writeJSON(w, http.StatusOK, service)
+}
+
+// Handler para desfazer o reconhecimento, voltando a notificar a queda
+func unackServiceHandler(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	defer mu.Unlock()
+	i, ok := findServiceByID(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+	service := &latestServicesState[i]
+
+	alertMu.Lock()
+	var previous *acknowledgment
+	var saved alertState
+	if state, ok := alertStates[service.Description]; ok {
+		previous, state.Ack = state.Ack, nil
+		saved = *state
+	}
+	alertMu.Unlock()
+	if previous == nil {
+		http.Error(w, "O serviço não está reconhecido", http.StatusNotFound)
+		return
+	}
+	saveAlertState(service.Description, saved)
+	service.Acknowledged = nil
+	hub.update(*service)
+
+	log.Printf(tr("Reconhecimento do serviço [%s] desfeito por %s\n"), service.Description, currentUser(r))
+	auditRequest(r, "service.unack", service.Description, previous, nil)
+	writeJSON(w, http.StatusOK, service)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const acmeRenewBefore = 30 * 24 * time.Hour // Antecedência da renovação em relação ao vencimento
+
+// Função para montar a configuração TLS com certificados obtidos via ACME e o handler
+// da porta HTTP (que também responde aos desafios HTTP-01)
+func acmeTLSConfig(config TLSConfig) (*tls.Config, http.Handler, error) {
+	if len(config.ACMEDomains) == 0 {
+		return nil, nil, fmt.Errorf("informe acme_domains na seção [tls]")
+	}
+	if err := os.MkdirAll(config.ACMECacheDir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("diretório de certificados: %w", err)
+	}
+	redirect := http.HandlerFunc(httpsRedirectHandler)
+
+	switch config.ACMEChallenge {
+	case "http-01":
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(config.ACMEDomains...),
+			Cache:      autocert.DirCache(config.ACMECacheDir),
+			Email:      config.ACMEEmail,
+			Client:     &acme.Client{DirectoryURL: config.ACMEDirectory},
+		}
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		return tlsConfig, manager.HTTPHandler(redirect), nil
+	case "dns-01":
+		if config.ACMEDNSHook == "" {
+			return nil, nil, fmt.Errorf("o desafio dns-01 exige acme_dns_hook")
+		}
+		manager := &dnsACMEManager{config: config}
+		manager.loadCached()
+		go manager.renewLoop()
+		return &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: manager.getCertificate}, redirect, nil
+	}
+	return nil, nil, fmt.Errorf("acme_challenge inválido %q (use http-01 ou dns-01)", config.ACMEChallenge)
+}
+
+// Gerenciador de certificados ACME validados por DNS-01; os registros TXT são criados e removidos
+// pelo script acme_dns_hook, chamado como: <script> present|cleanup <nome> <valor>
+type dnsACMEManager struct {
+	config TLSConfig
+	mu     sync.Mutex
+	cert   *tls.Certificate
+}
+
+// Função para obter o certificado atual
+func (m *dnsACMEManager) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cert == nil {
+		return nil, errors.New("certificado ACME ainda não emitido")
+	}
+	return m.cert, nil
+}
+
+// Função para obter o caminho do arquivo com a chave e a cadeia do certificado
+func (m *dnsACMEManager) certPath() string {
+	return filepath.Join(m.config.ACMECacheDir, m.config.ACMEDomains[0]+".dns-01.pem")
+}
+
+// Função para carregar o certificado salvo em uma execução anterior
+func (m *dnsACMEManager) loadCached() {
+	data, err := os.ReadFile(m.certPath())
+	if err != nil {
+		return
+	}
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		log.Println(tr("Erro ao carregar o certificado ACME salvo:"), err)
+		return
+	}
+	m.mu.Lock()
+	m.cert = &cert
+	m.mu.Unlock()
+}
+
+// Função para verificar se o certificado precisa ser emitido ou renovado
+func (m *dnsACMEManager) needsRenewal() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cert == nil || len(m.cert.Certificate) == 0 {
+		return true
+	}
+	leaf, err := x509.ParseCertificate(m.cert.Certificate[0])
+	return err != nil || time.Until(leaf.NotAfter) < acmeRenewBefore
+}
+
+// Rotina que emite o certificado e o renova antes do vencimento
+func (m *dnsACMEManager) renewLoop() {
+	for {
+		wait := 12 * time.Hour
+		if m.needsRenewal() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			if err := m.obtain(ctx); err != nil {
+				log.Println(tr("Erro ao emitir o certificado ACME:"), err)
+				wait = 15 * time.Minute
+			} else {
+				log.Println(tr("Certificado ACME emitido para"), m.config.ACMEDomains)
+			}
+			cancel()
+		}
+		time.Sleep(wait)
+	}
+}
+
+// Função para emitir o certificado validando os domínios via registros TXT
+func (m *dnsACMEManager) obtain(ctx context.Context) error {
+	accountKey, err := loadOrCreateKey(filepath.Join(m.config.ACMECacheDir, "acme_account.key"))
+	if err != nil {
+		return fmt.Errorf("chave da conta ACME: %w", err)
+	}
+	client := &acme.Client{Key: accountKey, DirectoryURL: m.config.ACMEDirectory}
+	account := &acme.Account{}
+	if m.config.ACMEEmail != "" {
+		account.Contact = []string{"mailto:" + m.config.ACMEEmail}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("registro da conta ACME: %w", err)
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(m.config.ACMEDomains...))
+	if err != nil {
+		return fmt.Errorf("pedido: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := m.authorize(ctx, client, authzURL); err != nil {
+			return err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("pedido: %w", err)
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: m.config.ACMEDomains[0]},
+		DNSNames: m.config.ACMEDomains,
+	}, certKey)
+	if err != nil {
+		return err
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("emissão: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(certKey)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	for _, der := range chain {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(m.certPath(), data, 0600); err != nil {
+		log.Println(tr("Erro ao salvar o certificado ACME:"), err)
+	}
+	m.mu.Lock()
+	m.cert = &cert
+	m.mu.Unlock()
+	return nil
+}
+
+// Função para concluir o desafio DNS-01 de uma autorização
+func (m *dnsACMEManager) authorize(ctx context.Context, client *acme.Client, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("autorização: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			challenge = c
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("o servidor ACME não ofereceu dns-01 para %s", authz.Identifier.Value)
+	}
+	record, err := client.DNS01ChallengeRecord(challenge.Token)
+	if err != nil {
+		return err
+	}
+
+	name := "_acme-challenge." + authz.Identifier.Value
+	if err := m.runHook(ctx, "present", name, record); err != nil {
+		return fmt.Errorf("acme_dns_hook present %s: %w", name, err)
+	}
+	defer func() {
+		if err := m.runHook(context.Background(), "cleanup", name, record); err != nil {
+			log.Printf(tr("Erro no acme_dns_hook cleanup %s: %v\n"), name, err)
+		}
+	}()
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("desafio: %w", err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("validação de %s: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// Função para chamar o script que cria/remove o registro TXT no provedor de DNS
+// (o script deve retornar apenas quando o registro estiver propagado)
+func (m *dnsACMEManager) runHook(ctx context.Context, action, name, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, m.config.ACMEDNSHook, action, name, value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
+
+// Função para ler uma chave ECDSA em PEM ou criar uma nova, caso o arquivo não exista
+func loadOrCreateKey(path string) (crypto.Signer, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("arquivo %s inválido", path)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	agentPath           = "/api/agent/ws"  // Caminho do WebSocket das sondas na porta com TLS mútuo
+	agentRetryDelay     = 10 * time.Second // Espera antes de a sonda reconectar à central
+	agentResultBuffer   = 1000             // Resultados guardados enquanto a conexão com a central está lenta
+	agentMaxMessageSize = 64 * 1024        // Tamanho máximo dos resultados recebidos pela central
+)
+
+// Mensagem trocada entre a central e as sondas: a lista de serviços da sonda (central → sonda) ou o resultado
+// de uma verificação (sonda → central)
+type agentMessage struct {
+	Type      string         `json:"type"` // "services" ou "result"
+	Services  []agentService `json:"services,omitempty"`
+	Service   string         `json:"service,omitempty"`
+	Status    string         `json:"status,omitempty"`
+	LatencyMs int64          `json:"latency_ms,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// Serviço verificado por uma sonda, com o intervalo efetivo e as opções da linha do config.ini da central
+type agentService struct {
+	Description string            `json:"description"`
+	Type        string            `json:"type"`
+	Host        string            `json:"host"`
+	Port        string            `json:"port,omitempty"`
+	IntervalMs  int64             `json:"interval_ms"`
+	Options     map[string]string `json:"options,omitempty"`
+}
+
+// Serviço e sonda de um resultado (um serviço pode ser verificado por várias sondas)
+type agentResultKey struct {
+	Service string
+	Agent   string
+}
+
+// Último resultado recebido de uma sonda para um serviço
+type agentResult struct {
+	Status    string
+	LatencyMs int64
+	Reason    string
+	Time      time.Time
+}
+
+// Conexão de uma sonda com a central
+type agentState struct {
+	Name      string    `json:"name"`
+	Connected bool      `json:"connected"`
+	Since     time.Time `json:"since"` // Início da conexão atual ou momento da desconexão
+	Address   string    `json:"address"`
+	Services  int       `json:"services"`
+	LastSeen  time.Time `json:"last_result"` // Último resultado recebido
+	conn      *websocket.Conn
+}
+
+var agentResults = map[agentResultKey]agentResult{} // Último resultado de cada sonda para cada serviço (protegido por mu)
+var agentStates = map[string]*agentState{}          // Sondas que já se conectaram, indexadas pelo nome (protegido por mu)
+
+// Função para avaliar o status atual de um serviço visto por uma das sondas que o verificam; fica "unknown"
+// enquanto a sonda estiver desconectada ou não tiver enviado nenhum resultado
+func evaluateAgent(service Service, agent string) (string, int64, string) {
+	mu.Lock()
+	state := agentStates[agent]
+	result, ok := agentResults[agentResultKey{service.Description, agent}]
+	mu.Unlock()
+
+	if state == nil || !state.Connected {
+		if state == nil {
+			return "unknown", 0, fmt.Sprintf(tr("agent %s has never connected"), agent)
+		}
+		return "unknown", 0, fmt.Sprintf(tr("agent %s disconnected since %s"), agent, state.Since.Format("2006-01-02 15:04:05"))
+	}
+	if !ok {
+		return "unknown", 0, fmt.Sprintf(tr("waiting for the first result from agent %s"), agent)
+	}
+	return result.Status, result.LatencyMs, result.Reason
+}
+
+// Função para exibir no dashboard um serviço de sonda sem resultado disponível, sem registrar histórico nem
+// alertar: a queda da conexão de uma filial não é a queda dos serviços verificados por ela
+func showAgentWaiting(services []Service, i int, message string, locations []locationStatus) {
+	if services[i].Status == "unknown" && services[i].Message == message && slices.Equal(services[i].Locations, locations) {
+		return
+	}
+	services[i].Locations = locations
+	services[i].Status = "unknown"
+	services[i].ResponseTime = ""
+	services[i].Message = message
+	mu.Lock()
+	latestServicesState[i] = services[i]
+	mu.Unlock()
+	hub.update(services[i])
+}
+
+// Função para montar a lista de serviços de uma sonda (opção agent= do serviço com o nome dela)
+func agentServices(config *Config, agent string) []agentService {
+	list := []agentService{}
+	for _, service := range config.Services {
+		if !slices.Contains(serviceLocations(service), agent) {
+			continue
+		}
+		list = append(list, agentService{
+			Description: service.Description,
+			Type:        service.Type,
+			Host:        service.IP,
+			Port:        service.Port,
+			IntervalMs:  serviceInterval(service).Milliseconds(),
+			Options:     service.Options,
+		})
+	}
+	return list
+}
+
+// Handler do WebSocket das sondas, disponível apenas na porta com TLS mútuo: envia à sonda a lista dos seus
+// serviços (e a reenvia quando o config.ini muda) e recebe os resultados das verificações
+func agentWSHandler(w http.ResponseWriter, r *http.Request) {
+	agent, ok := strings.CutPrefix(currentUser(r), "agent:")
+	if !ok || r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		http.Error(w, "Disponível apenas na porta das sondas (certificado de cliente)", http.StatusForbidden)
+		return
+	}
+	var agentUpgrader websocket.Upgrader // Sem verificação de origem: a sonda é identificada pelo certificado
+	conn, err := agentUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println(tr("Erro ao abrir WebSocket:"), err)
+		return
+	}
+	defer conn.Close()
+
+	state := &agentState{Name: agent, Connected: true, Since: time.Now(), Address: r.RemoteAddr, conn: conn}
+	mu.Lock()
+	if previous := agentStates[agent]; previous != nil && previous.Connected {
+		previous.conn.Close() // Conexão antiga da mesma sonda (reconexão antes do timeout)
+	}
+	agentStates[agent] = state
+	mu.Unlock()
+	log.Printf(tr("Sonda [%s] conectada de %s\n"), agent, r.RemoteAddr)
+	defer func() {
+		mu.Lock()
+		if agentStates[agent] == state {
+			state.Connected = false
+			state.Since = time.Now()
+		}
+		mu.Unlock()
+		log.Printf(tr("Sonda [%s] desconectada\n"), agent)
+	}()
+
+	closed := make(chan struct{})
+	go agentReadPump(conn, agent, closed)
+
+	// Envia a lista de serviços, que é reenviada quando a configuração muda, e os pings
+	var config *Config
+	configTicker := time.NewTicker(configWatchInterval)
+	defer configTicker.Stop()
+	pingTicker := time.NewTicker(wsPingPeriod)
+	defer pingTicker.Stop()
+	for {
+		var err error
+		if current := getConfig(); current != config {
+			config = current
+			list := agentServices(config, agent)
+			mu.Lock()
+			state.Services = len(list)
+			mu.Unlock()
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err = conn.WriteJSON(agentMessage{Type: "services", Services: list}); err != nil {
+				return
+			}
+		}
+		select {
+		case <-configTicker.C:
+		case <-pingTicker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+		case <-closed:
+			return
+		case <-hub.closing:
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(wsWriteWait))
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Função para ler os resultados enviados por uma sonda; a conexão é encerrada se a sonda parar de responder
+// aos pings por wsPongWait
+func agentReadPump(conn *websocket.Conn, agent string, closed chan struct{}) {
+	defer close(closed)
+	conn.SetReadLimit(agentMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		var message agentMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+				log.Printf(tr("Conexão da sonda [%s] encerrada: %v\n"), agent, err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		if message.Type != "result" || !recordAgentResult(agent, message) {
+			log.Printf(tr("Mensagem da sonda [%s] ignorada: %s %q\n"), agent, message.Type, message.Service)
+		}
+	}
+}
+
+// Função para registrar o resultado de uma verificação feita por uma sonda; só são aceitos resultados dos
+// serviços atribuídos a ela. O dashboard é atualizado imediatamente (nos serviços verificados de vários locais,
+// apenas o status do local) e o histórico e os alertas são registrados no próximo ciclo do serviço, como nos
+// serviços push.
+func recordAgentResult(agent string, message agentMessage) bool {
+	if message.Status != "green" && message.Status != "red" {
+		return false
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range latestServicesState {
+		service := &latestServicesState[i]
+		locations := serviceLocations(*service)
+		if service.Description != message.Service || !slices.Contains(locations, agent) {
+			continue
+		}
+
+		now := time.Now() // Horário da central, imune ao relógio da sonda
+		agentResults[agentResultKey{service.Description, agent}] = agentResult{Status: message.Status, LatencyMs: message.LatencyMs, Reason: message.Reason, Time: now}
+		if state := agentStates[agent]; state != nil {
+			state.LastSeen = now
+		}
+		if len(locations) > 1 {
+			updateLocation(service, agent, message)
+		} else if service.Status != "paused" && (service.Status != message.Status || service.LatencyMs != message.LatencyMs || service.Message != "") {
+			service.Status = message.Status
+			service.LatencyMs = message.LatencyMs
+			service.ResponseTime = formatResponseTime(message.LatencyMs)
+			service.Message = ""
+			hub.update(*service)
+		}
+		return true
+	}
+	return false
+}
+
+// Função para atualizar no dashboard o status de um dos locais de um serviço verificado de vários locais
+func updateLocation(service *Service, agent string, message agentMessage) {
+	if service.Status == "paused" {
+		return
+	}
+	entry := locationStatus{Location: agent, Status: message.Status, ResponseTime: formatResponseTime(message.LatencyMs)}
+	if message.Status == "red" {
+		entry.Message = message.Reason
+	}
+	i := slices.IndexFunc(service.Locations, func(location locationStatus) bool { return location.Location == agent })
+	if i < 0 || service.Locations[i] == entry {
+		return
+	}
+	service.Locations = slices.Clone(service.Locations) // Os locais são compartilhados com a cópia do agendamento
+	service.Locations[i] = entry
+	hub.update(*service)
+}
+
+// Handler que lista as sondas que já se conectaram desde o início do processo, com o estado da conexão
+func listAgentsHandler(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	list := make([]agentState, 0, len(agentStates))
+	for _, state := range agentStates {
+		list = append(list, *state)
+	}
+	mu.Unlock()
+	slices.SortFunc(list, func(a, b agentState) int { return strings.Compare(a.Name, b.Name) })
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Função para executar o processo como sonda remota (opção -agent): conecta à central com o certificado da
+// sonda, recebe a lista de serviços e envia o resultado de cada verificação, reconectando quando a conexão cai.
+// O config.ini local é opcional e só fornece as configurações das verificações ([general] timeout, workers e
+// jitter, [dns], [network] e [backoff]); os serviços vêm da central.
+func runAgent(server, certFile, keyFile, caFile string) {
+	config, err := loadAgentConfig()
+	if err != nil {
+		log.Fatal("Erro ao carregar arquivo de configuração:", err)
+	}
+	applyConfig(config)
+	configLoaded.Store(true)
+
+	target, err := agentURL(server)
+	if err != nil {
+		log.Fatal(tr("Endereço da central inválido (-server):"), err)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		log.Fatal(tr("Erro ao carregar o certificado da sonda:"), err)
+	}
+	pool, err := loadCAPool(caFile)
+	if err != nil {
+		log.Fatal(tr("Erro ao carregar a CA:"), err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}, RootCAs: pool}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		err := runAgentSession(ctx, target, tlsConfig)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf(tr("Conexão com a central perdida (%v), reconectando em %s\n"), err, agentRetryDelay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(agentRetryDelay):
+		}
+	}
+}
+
+// Função para carregar o config.ini da sonda, usando as configurações padrão quando ele não existe
+func loadAgentConfig() (*Config, error) {
+	config, err := loadConfig(configFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return parseConfig(ini.Empty())
+	}
+	return config, err
+}
+
+// Função para montar o endereço do WebSocket das sondas a partir de -server (wss://central:8443)
+func agentURL(server string) (string, error) {
+	target, err := url.Parse(server)
+	if err != nil {
+		return "", err
+	}
+	if target.Scheme == "https" {
+		target.Scheme = "wss"
+	}
+	if target.Scheme != "wss" || target.Host == "" {
+		return "", fmt.Errorf("use wss://<host>:<porta> da seção [agents] da central")
+	}
+	if target.Path == "" || target.Path == "/" {
+		target.Path = agentPath
+	}
+	return target.String(), nil
+}
+
+// Função para manter uma conexão com a central: agenda os serviços recebidos (reiniciando o agendamento a cada
+// nova lista) e envia os resultados; retorna quando a conexão cai ou o processo é encerrado
+func runAgentSession(ctx context.Context, target string, tlsConfig *tls.Config) error {
+	dialer := websocket.Dialer{TLSClientConfig: tlsConfig, HandshakeTimeout: wsWriteWait}
+	conn, _, err := dialer.DialContext(ctx, target, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	log.Printf(tr("Sonda conectada à central %s\n"), target)
+
+	// A central envia pings periódicos: sem eles (central travada ou conexão meio aberta) a sonda reconecta
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(wsWriteWait))
+	})
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lists := make(chan []Service)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var message agentMessage
+			if err := conn.ReadJSON(&message); err != nil {
+				readErr <- err
+				return
+			}
+			conn.SetReadDeadline(time.Now().Add(wsPongWait))
+			if message.Type != "services" {
+				continue
+			}
+			select {
+			case lists <- servicesFromAgent(message.Services):
+			case <-sessionCtx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan agentMessage, agentResultBuffer)
+	stop := func() {}
+	defer func() { stop() }()
+	for {
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(wsWriteWait))
+			return ctx.Err()
+		case err := <-readErr:
+			return err
+		case services := <-lists:
+			stop()
+			log.Printf(tr("Sonda recebeu %d serviço(s) da central\n"), len(services))
+			stop = startScheduler(sessionCtx, services, nil, agentCheck(results))
+		case result := <-results:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteJSON(result); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Função para converter a lista recebida da central nos serviços agendados pela sonda
+func servicesFromAgent(list []agentService) []Service {
+	services := make([]Service, len(list))
+	for i, item := range list {
+		services[i] = Service{
+			ID:          i + 1,
+			Description: item.Description,
+			Type:        item.Type,
+			IP:          item.Host,
+			Port:        item.Port,
+			Status:      "unknown",
+			Interval:    time.Duration(item.IntervalMs) * time.Millisecond,
+			Options:     item.Options,
+		}
+	}
+	return services
+}
+
+// Função que verifica um serviço na sonda e enfileira o resultado para a central; se a fila estiver cheia
+// (central lenta), o resultado é descartado e o próximo ciclo envia um novo
+func agentCheck(results chan agentMessage) checkFunc {
+	return func(ctx context.Context, services []Service, i int) {
+		status, latency, reason, ok := probeService(ctx, services[i])
+		if !ok {
+			return
+		}
+		// Status e início da queda locais, usados pelo espaçamento das verificações ([backoff])
+		if status == "red" && services[i].DownSince == nil {
+			now := time.Now()
+			services[i].DownSince = &now
+		} else if status == "green" {
+			services[i].DownSince = nil
+		}
+		services[i].Status = status
+
+		select {
+		case results <- agentMessage{Type: "result", Service: services[i].Description, Status: status, LatencyMs: latency, Reason: reason}:
+		default:
+			log.Printf(tr("Resultado do serviço [%s] descartado: fila de envio à central cheia\n"), services[i].Description)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [alerts]
+type AlertsConfig struct {
+	AfterFailures int           // Falhas consecutivas antes de notificar a queda (a opção alert_after_failures= do serviço tem prioridade)
+	RenotifyEvery time.Duration // Intervalo para repetir a notificação de quedas não reconhecidas (0 desabilita)
+}
+
+// Função para ler a seção [alerts] do config.ini
+func loadAlertsConfig(cfg *ini.File) AlertsConfig {
+	section := cfg.Section("alerts")
+	config := AlertsConfig{
+		AfterFailures: section.Key("alert_after_failures").MustInt(1),
+	}
+	if config.AfterFailures < 1 {
+		config.AfterFailures = 1
+	}
+	if value := section.Key("renotify_every").String(); value != "" && value != "0" {
+		every, err := parseRange(value, 0)
+		if err != nil {
+			log.Printf(tr("renotify_every inválido %q, repetição desabilitada\n"), value)
+		}
+		config.RenotifyEvery = every
+	}
+	return config
+}
+
+// Estado de alerta de um serviço, independente do status exibido no dashboard
+type alertState struct {
+	Status       string          // Último status notificado (green ou red)
+	Since        time.Time       // Início do status notificado (na queda, o momento da primeira falha)
+	Failures     int             // Falhas consecutivas
+	FirstFailure time.Time       // Momento da primeira falha da sequência atual
+	Notified     bool            // A queda atual foi notificada (não estava silenciada)
+	LastNotified time.Time       // Última notificação da queda atual (usada para repeti-la)
+	Escalation   int             // Etapas da política de escalonamento já acionadas na queda atual
+	Ack          *acknowledgment // Reconhecimento da queda atual (suspende a repetição da notificação)
+}
+
+var alertMu sync.Mutex                     // Mutex para proteger o estado de alerta
+var alertStates = map[string]*alertState{} // Estado de alerta por serviço, indexado pela descrição
+
+// Função para obter o número de falhas consecutivas exigido antes de notificar a queda do serviço
+func alertAfterFailures(service Service) int {
+	if value, ok := service.Options["alert_after_failures"]; ok {
+		if n, err := strconv.Atoi(value); err == nil && n >= 1 {
+			return n
+		}
+	}
+	return getConfig().Alerts.AfterFailures
+}
+
+// Função para acompanhar o resultado de cada verificação e notificar a queda apenas após
+// alert_after_failures falhas consecutivas (e a recuperação apenas de quedas notificadas), seguindo
+// a política de escalonamento do grupo. Um serviço já fora do ar no primeiro status conhecido (ao iniciar, ao
+// recarregar ou ao retomar um serviço pausado) também é notificado ao atingir o limite de falhas, com o status
+// anterior unknown; o primeiro status verde não gera notificação.
+func trackAlert(service Service, status string, at time.Time) {
+	if status != "green" && status != "red" {
+		return
+	}
+
+	alertMu.Lock()
+	state, ok := alertStates[service.Description]
+	if !ok {
+		state = &alertState{}
+		alertStates[service.Description] = state
+	}
+
+	previous := *state
+	steps := escalationPolicy(service)
+	var change *stateChange
+	if status == "red" {
+		if state.Failures == 0 {
+			state.FirstFailure = at
+		}
+		state.Failures++
+		if state.Status != "red" && state.Failures >= alertAfterFailures(service) {
+			change = &stateChange{Service: service, From: "unknown", To: "red", Time: at, FirstFailure: state.FirstFailure}
+			if state.Status == "green" {
+				change.From, change.Duration = "green", state.FirstFailure.Sub(state.Since)
+			}
+			if steps != nil {
+				state.Escalation = escalationLevel(steps, at.Sub(state.FirstFailure))
+				change.Channels = escalationChannels(steps, 0, state.Escalation)
+			}
+			state.Status, state.Since = "red", state.FirstFailure
+		} else if state.Status == "red" && state.Notified && state.Ack == nil {
+			if level := escalationLevel(steps, at.Sub(state.Since)); level > state.Escalation {
+				// Queda ainda não reconhecida: aciona os canais das próximas etapas de escalonamento
+				change = &stateChange{Service: service, From: "red", To: "red", Time: at, Duration: at.Sub(state.Since), FirstFailure: state.Since, Repeat: true,
+					Channels: escalationChannels(steps, state.Escalation, level)}
+				state.Escalation = level
+			} else if every := getConfig().Alerts.RenotifyEvery; every > 0 && at.Sub(state.LastNotified) >= every {
+				// Queda ainda não reconhecida: repete a notificação aos canais já acionados
+				change = &stateChange{Service: service, From: "red", To: "red", Time: at, Duration: at.Sub(state.Since), FirstFailure: state.Since, Repeat: true}
+				if steps != nil {
+					change.Channels = escalationChannels(steps, 0, state.Escalation)
+				}
+			}
+		}
+	} else {
+		state.Failures = 0
+		if state.Status != "green" {
+			if state.Status == "red" && state.Notified {
+				change = &stateChange{Service: service, From: "red", To: "green", Time: at, Duration: at.Sub(state.Since), FirstFailure: state.Since}
+				if steps != nil {
+					// A recuperação vai para todos os canais acionados durante a queda
+					change.Channels = escalationChannels(steps, 0, state.Escalation)
+				}
+			}
+			state.Status, state.Since, state.Notified, state.Escalation = "green", at, false, 0
+		}
+		state.Ack = nil // O reconhecimento vale apenas para a queda atual
+	}
+	alertMu.Unlock()
+
+	if change != nil && notifyStateChange(*change) && change.To == "red" {
+		alertMu.Lock()
+		state.Notified, state.LastNotified = true, at
+		alertMu.Unlock()
+	}
+
+	// Grava o estado quando o status notificado, a notificação ou o reconhecimento mudam (não a cada falha)
+	if change != nil || previous.Status != state.Status || previous.Ack != state.Ack {
+		alertMu.Lock()
+		saved := *state
+		alertMu.Unlock()
+		saveAlertState(service.Description, saved)
+	}
+}
+
+// Função para descartar o estado de alerta de um serviço pausado; ao ser retomado, o serviço volta a ser
+// acompanhado como no primeiro status conhecido
+func resetAlert(description string) {
+	alertMu.Lock()
+	delete(alertStates, description)
+	alertMu.Unlock()
+	deleteAlertState(description)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Anotação de um evento externo (deploy, manutenção, mudança de configuração) para correlacionar com as quedas
+type annotation struct {
+	ID        string     `json:"id"`
+	Time      time.Time  `json:"time"`
+	End       *time.Time `json:"end,omitempty"` // Fim de eventos com duração, como janelas de manutenção
+	Text      string     `json:"text"`
+	Service   string     `json:"service,omitempty"` // Descrição do serviço; vazio = todos os serviços (do grupo, se informado)
+	Group     string     `json:"group,omitempty"`
+	Tags      []string   `json:"tags,omitempty"` // Ex.: deploy, maintenance
+	CreatedBy string     `json:"created_by"`
+}
+
+const maxAnnotations = 10000 // Anotações mantidas em memória
+
+var annotationsMu sync.Mutex     // Mutex para proteger as anotações
+var annotations = []annotation{} // Anotações em ordem de horário
+
+// Função para verificar se a anotação se aplica ao serviço
+func (a annotation) appliesTo(service Service) bool {
+	return (a.Service == "" || a.Service == service.Description) && (a.Group == "" || a.Group == service.Group)
+}
+
+// Função para verificar se a anotação acontece (ou se sobrepõe) à janela [from, to]
+func (a annotation) within(from, to time.Time) bool {
+	end := a.Time
+	if a.End != nil {
+		end = *a.End
+	}
+	return !a.Time.After(to) && !end.Before(from)
+}
+
+// Função para incluir uma anotação na memória, em ordem de horário e descartando as mais antigas além do limite
+// (chamada com annotationsMu travado)
+func insertAnnotation(a annotation) {
+	i := sort.Search(len(annotations), func(i int) bool { return annotations[i].Time.After(a.Time) })
+	annotations = slices.Insert(annotations, i, a)
+	if len(annotations) > maxAnnotations {
+		annotations = annotations[len(annotations)-maxAnnotations:]
+	}
+}
+
+// Função para listar as anotações do serviço na janela [from, to], incluídas no histórico e nas séries
+func serviceAnnotations(service Service, from, to time.Time) []annotation {
+	annotationsMu.Lock()
+	defer annotationsMu.Unlock()
+	result := []annotation{}
+	for _, a := range annotations {
+		if a.appliesTo(service) && a.within(from, to) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// Função para listar todas as anotações na janela [from, to]
+func annotationsInRange(from, to time.Time) []annotation {
+	annotationsMu.Lock()
+	defer annotationsMu.Unlock()
+	result := []annotation{}
+	for _, a := range annotations {
+		if a.within(from, to) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// Handler para listar as anotações (mais recentes primeiro), com os filtros service, group, tag, from, to e limit
+func listAnnotationsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	to, err := parseTime(query.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, "Parâmetro to inválido", http.StatusBadRequest)
+		return
+	}
+	from, err := parseTime(query.Get("from"), time.Time{})
+	if err != nil {
+		http.Error(w, "Parâmetro from inválido", http.StatusBadRequest)
+		return
+	}
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "Parâmetro limit inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result := []annotation{}
+	annotationsMu.Lock()
+	for i := len(annotations) - 1; i >= 0 && len(result) < limit; i-- {
+		a := annotations[i]
+		if !a.within(from, to) {
+			continue
+		}
+		if service := query.Get("service"); service != "" && a.Service != service {
+			continue
+		}
+		if group := query.Get("group"); group != "" && a.Group != group {
+			continue
+		}
+		if tag := query.Get("tag"); tag != "" && !slices.Contains(a.Tags, tag) {
+			continue
+		}
+		result = append(result, a)
+	}
+	annotationsMu.Unlock()
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Handler para registrar uma anotação:
+// {"text": "deployed v2.3 of billing", "service": ..., "group": ..., "tags": ["deploy"], "time": ..., "end": ...}
+func createAnnotationHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Text    string   `json:"text"`
+		Service string   `json:"service"`
+		Group   string   `json:"group"`
+		Tags    []string `json:"tags"`
+		Time    string   `json:"time"`
+		End     string   `json:"end"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Text == "" {
+		http.Error(w, "Informe text", http.StatusBadRequest)
+		return
+	}
+	if request.Service != "" && unknownService([]string{request.Service}) != "" {
+		http.Error(w, "Serviço não encontrado: "+request.Service, http.StatusBadRequest)
+		return
+	}
+	at, err := parseTime(request.Time, time.Now())
+	if err != nil {
+		http.Error(w, "Parâmetro time inválido", http.StatusBadRequest)
+		return
+	}
+	a := annotation{
+		ID:        generateToken()[:16],
+		Time:      at,
+		Text:      request.Text,
+		Service:   request.Service,
+		Group:     request.Group,
+		Tags:      request.Tags,
+		CreatedBy: currentUser(r),
+	}
+	if request.End != "" {
+		end, err := parseTime(request.End, time.Time{})
+		if err != nil || end.Before(at) {
+			http.Error(w, "Parâmetro end inválido ou anterior a time", http.StatusBadRequest)
+			return
+		}
+		a.End = &end
+	}
+
+	annotationsMu.Lock()
+	insertAnnotation(a)
+	annotationsMu.Unlock()
+	saveAnnotation(a)
+
+	log.Printf(tr("Anotação %s registrada por %s: %s\n"), a.ID, a.CreatedBy, a.Text)
+	writeJSON(w, http.StatusCreated, a)
+}
+
+// Handler para apagar uma anotação
+func deleteAnnotationHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	annotationsMu.Lock()
+	index := slices.IndexFunc(annotations, func(a annotation) bool { return a.ID == id })
+	var removed annotation
+	if index >= 0 {
+		removed = annotations[index]
+		annotations = slices.Delete(annotations, index, index+1)
+	}
+	annotationsMu.Unlock()
+	if index < 0 {
+		http.Error(w, "Anotação não encontrada", http.StatusNotFound)
+		return
+	}
+	deleteAnnotation(id)
+
+	auditRequest(r, "annotation.delete", id, removed, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+var pausedServices = map[string]bool{} // Serviços pausados, indexados pela descrição
+var pausedGroups = map[string]bool{}   // Grupos pausados, indexados pelo nome do grupo
+
+// Função para verificar se o serviço (ou o grupo dele) está pausado
+func isPaused(service Service) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return servicePaused(service)
+}
+
+// Função auxiliar de isPaused (deve ser chamada com mu bloqueado)
+func servicePaused(service Service) bool {
+	return pausedServices[service.Description] || (service.Group != "" && pausedGroups[service.Group])
+}
+
+// Função para escrever uma resposta JSON
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Println(tr("Erro ao enviar resposta JSON:"), err)
+	}
+}
+
+// Função para obter uma cópia do último estado dos serviços
+func snapshotServices() []Service {
+	mu.Lock()
+	defer mu.Unlock()
+	snapshot := make([]Service, len(latestServicesState))
+	copy(snapshot, latestServicesState)
+	return snapshot
+}
+
+// Handler para o snapshot do status em JSON (o mesmo enviado pelo WebSocket), útil para curl/cron
+func statusJSONHandler(w http.ResponseWriter, r *http.Request) {
+	mode := r.URL.Query().Get("sort")
+	if mode != "" && !slices.Contains(sortModes, mode) {
+		http.Error(w, "Parâmetro sort inválido (use config, name, group, status ou manual)", http.StatusBadRequest)
+		return
+	}
+	fields, err := parseFields(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	// Sem parâmetros, a lista já serializada pelo hub é enviada como está (painéis consultando com frequência)
+	if mode == "" && fields == nil && !r.URL.Query().Has("pretty") && len(visibleGroups(r)) == 0 {
+		if _, err := fmt.Fprintf(w, "%s\n", hub.statusJSON()); err != nil {
+			log.Println(tr("Erro ao enviar status JSON:"), err)
+		}
+		return
+	}
+
+	snapshot := filterVisible(r, snapshotServices())
+	if mode != "" {
+		sortServices(snapshot, mode)
+	}
+
+	// Com ?fields=, apenas os campos selecionados (payload reduzido para clientes móveis)
+	var data interface{} = snapshot
+	if fields != nil {
+		if data, err = selectFields(snapshot, fields); err != nil {
+			log.Println(tr("Erro ao enviar status JSON:"), err)
+			http.Error(w, "Erro ao gerar o status", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	if r.URL.Query().Has("pretty") {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		log.Println(tr("Erro ao enviar status JSON:"), err)
+	}
+}
+
+// Função para localizar um serviço pelo ID informado na URL, entre os visíveis para a identidade autenticada
+// (deve ser chamada com mu bloqueado)
+func findServiceByID(r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, false
+	}
+	for i := range latestServicesState {
+		if latestServicesState[i].ID == id {
+			return i, serviceVisible(r, latestServicesState[i])
+		}
+	}
+	return 0, false
+}
+
+// Função para obter uma cópia do serviço identificado pelo ID informado na URL
+func lookupService(r *http.Request) (Service, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	i, ok := findServiceByID(r)
+	if !ok {
+		return Service{}, false
+	}
+	return latestServicesState[i], true
+}
+
+// Função para aplicar imediatamente o estado de pausa ao último estado dos serviços (deve ser chamada com mu bloqueado)
+func applyPauseState() {
+	for i := range latestServicesState {
+		service := &latestServicesState[i]
+		paused := servicePaused(*service)
+		if paused && service.Status != "paused" {
+			service.Status = "paused"
+			service.ResponseTime = ""
+		} else if !paused && service.Status == "paused" {
+			service.Status = "unknown" // Volta a ser verificado no próximo ciclo
+		}
+	}
+	hub.update(latestServicesState...)
+}
+
+// Handler para pausar o monitoramento de um serviço
+func pauseServiceHandler(w http.ResponseWriter, r *http.Request) {
+	setServicePaused(w, r, true)
+}
+
+// Handler para retomar o monitoramento de um serviço
+func resumeServiceHandler(w http.ResponseWriter, r *http.Request) {
+	setServicePaused(w, r, false)
+}
+
+func setServicePaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	i, ok := findServiceByID(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+
+	description := latestServicesState[i].Description
+	wasPaused := pausedServices[description]
+	if paused {
+		pausedServices[description] = true
+		log.Printf(tr("Monitoramento do serviço [%s] pausado"), description)
+	} else {
+		delete(pausedServices, description)
+		log.Printf(tr("Monitoramento do serviço [%s] retomado"), description)
+	}
+	applyPauseState()
+	auditRequest(r, pauseAction("service", paused), description, map[string]bool{"paused": wasPaused}, map[string]bool{"paused": paused})
+
+	writeJSON(w, http.StatusOK, latestServicesState[i])
+}
+
+// Handler para pausar o monitoramento de todos os serviços de um grupo
+func pauseGroupHandler(w http.ResponseWriter, r *http.Request) {
+	setGroupPaused(w, r, true)
+}
+
+// Handler para retomar o monitoramento de todos os serviços de um grupo
+func resumeGroupHandler(w http.ResponseWriter, r *http.Request) {
+	setGroupPaused(w, r, false)
+}
+
+func setGroupPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	group := r.PathValue("group")
+	groupServices := []Service{}
+	for _, service := range latestServicesState {
+		if service.Group == group {
+			groupServices = append(groupServices, service)
+		}
+	}
+	if len(groupServices) == 0 {
+		http.Error(w, "Grupo não encontrado", http.StatusNotFound)
+		return
+	}
+
+	wasPaused := pausedGroups[group]
+	if paused {
+		pausedGroups[group] = true
+		log.Printf(tr("Monitoramento do grupo [%s] pausado"), group)
+	} else {
+		delete(pausedGroups, group)
+		log.Printf(tr("Monitoramento do grupo [%s] retomado"), group)
+	}
+	applyPauseState()
+	auditRequest(r, pauseAction("group", paused), group, map[string]bool{"paused": wasPaused}, map[string]bool{"paused": paused})
+
+	// Retorna o estado atualizado dos serviços do grupo
+	groupServices = groupServices[:0]
+	for _, service := range latestServicesState {
+		if service.Group == group {
+			groupServices = append(groupServices, service)
+		}
+	}
+	writeJSON(w, http.StatusOK, groupServices)
+}
+
+// Gravidade de cada status, usada para calcular o pior status de um conjunto de serviços
+var statusSeverity = map[string]int{"paused": 0, "green": 1, "unknown": 2, "red": 3}
+
+// Resumo consolidado de um conjunto de serviços
+type overallSummary struct {
+	Status string         `json:"status"`
+	Total  int            `json:"total"`
+	Counts map[string]int `json:"counts"`
+}
+
+// Função para calcular o pior status (ignorando serviços pausados) e as contagens por status
+func summarizeOverall(services []Service) overallSummary {
+	summary := overallSummary{Status: "unknown", Counts: map[string]int{"green": 0, "red": 0, "paused": 0, "unknown": 0}}
+	worst := -1
+	for _, service := range services {
+		summary.Total++
+		summary.Counts[service.Status]++
+		if service.Status == "paused" {
+			continue
+		}
+		if severity := statusSeverity[service.Status]; severity > worst {
+			worst = severity
+			summary.Status = service.Status
+		}
+	}
+	return summary
+}
+
+// Handler para o status consolidado de todos os serviços ou de um conjunto filtrado
+// (?group= e ?service= podem ser repetidos; ?strict responde 503 quando o resultado não é verde)
+func overallHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	groups := query["group"]
+	names := query["service"]
+
+	selected := []Service{}
+	for _, service := range filterVisible(r, snapshotServices()) {
+		if len(groups) > 0 && !slices.Contains(groups, service.Group) {
+			continue
+		}
+		if len(names) > 0 && !slices.Contains(names, service.Description) {
+			continue
+		}
+		selected = append(selected, service)
+	}
+
+	summary := summarizeOverall(selected)
+	status := http.StatusOK
+	if query.Has("strict") && summary.Status != "green" {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, summary)
+}
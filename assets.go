@@ -0,0 +1,167 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Arquivos do front-end incluídos no binário, de modo que a instalação é um único arquivo
+//
+//go:embed index.html public.html embed.html
+var embeddedAssets embed.FS
+
+// Sistema de arquivos do front-end: os arquivos de assets_dir (seção [server]), quando existem, substituem os
+// incluídos no binário, permitindo personalizar o dashboard sem recompilar
+type assetsFS struct {
+	dir string
+}
+
+// Função para abrir um arquivo do front-end, primeiro em assets_dir e depois no binário
+func (a assetsFS) Open(name string) (fs.File, error) {
+	if a.dir != "" {
+		f, err := os.DirFS(a.dir).Open(name)
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return f, err
+		}
+	}
+	return embeddedAssets.Open(name)
+}
+
+// Configuração da identidade visual do dashboard (seção [branding])
+type BrandingConfig struct {
+	Title      string // Título da página e do cabeçalho
+	Subtitle   string // Texto abaixo do título (ex.: o ambiente ou o datacenter)
+	LogoText   string // Texto em destaque acima do título (ex.: PRODUÇÃO), com ou sem a imagem do logo
+	Refresh    string // Aviso do intervalo de atualização: auto (calculado), none (oculto) ou um texto próprio
+	Logo       string // Arquivo ou URL da imagem exibida acima do título
+	CSS        string // Arquivo CSS carregado depois do estilo padrão
+	FaviconDir string // Diretório com os ícones (favicon.ico, favicon.svg, favicon.png, apple-touch-icon.png)
+}
+
+// Ícones procurados em favicon_dir, com o link gerado na página para cada um
+var faviconFiles = []struct{ Name, Rel, Type string }{
+	{"favicon.svg", "icon", "image/svg+xml"},
+	{"favicon.png", "icon", "image/png"},
+	{"favicon.ico", "icon", "image/x-icon"},
+	{"apple-touch-icon.png", "apple-touch-icon", "image/png"},
+}
+
+// Dados repassados ao index.html
+type pageData struct {
+	Title string
+	Logo  string // URL da imagem (vazio = sem logo)
+	CSS   string // URL do CSS personalizado (vazio = apenas o estilo padrão)
+	Icons []pageIcon
+
+	Language string            // Idioma da página (atributo lang)
+	Messages map[string]string // Catálogo dos textos do dashboard no idioma configurado
+	View     *ViewConfig       // Visão exibida (nil = dashboard completo)
+	Kiosk    bool              // Modo quiosque: a visão é alternada pelo servidor
+}
+
+// Ícone da página
+type pageIcon struct {
+	Rel, Type, Href string
+}
+
+// Função para ler a seção [branding] do config.ini
+func loadBrandingConfig(cfg *ini.File) BrandingConfig {
+	section := cfg.Section("branding")
+	return BrandingConfig{
+		Title:      section.Key("title").MustString("Service Monitoring Dashboard"),
+		Subtitle:   section.Key("subtitle").String(),
+		LogoText:   section.Key("logo_text").String(),
+		Refresh:    section.Key("refresh_hint").MustString("auto"),
+		Logo:       section.Key("logo").String(),
+		CSS:        section.Key("css").String(),
+		FaviconDir: section.Key("favicon_dir").String(),
+	}
+}
+
+// Função para verificar se a imagem do logo é uma URL externa (e não um arquivo local)
+func isURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// Função para montar os dados da página conforme a seção [branding]
+func brandingPage(config BrandingConfig) pageData {
+	page := pageData{Title: config.Title, Language: languageOf(), Messages: dashboardCatalog()}
+	if page.Language == "" {
+		page.Language = "en"
+	}
+	if isURL(config.Logo) {
+		page.Logo = config.Logo
+	} else if config.Logo != "" {
+		page.Logo = "/static/branding/logo" + filepath.Ext(config.Logo)
+	}
+	if config.CSS != "" {
+		page.CSS = "/static/branding/custom.css"
+	}
+	if config.FaviconDir != "" {
+		for _, icon := range faviconFiles {
+			if _, err := os.Stat(filepath.Join(config.FaviconDir, icon.Name)); err == nil {
+				page.Icons = append(page.Icons, pageIcon{Rel: icon.Rel, Type: icon.Type, Href: "/static/favicon/" + icon.Name})
+			}
+		}
+	}
+	return page
+}
+
+// Função para obter os arquivos do front-end conforme a configuração atual
+func assets() fs.FS {
+	return assetsFS{dir: getConfig().Server.AssetsDir}
+}
+
+// Função para gerar o dashboard a partir do index.html
+func renderDashboard(w http.ResponseWriter, page pageData) {
+	tmpl, err := template.ParseFS(assets(), "index.html")
+	if err != nil {
+		log.Println(tr("Erro ao carregar index.html:"), err)
+		http.Error(w, "Erro ao carregar o dashboard", http.StatusInternalServerError)
+		return
+	}
+	if err := tmpl.Execute(w, page); err != nil {
+		log.Println(tr("Erro ao gerar o dashboard:"), err)
+	}
+}
+
+// Handler para a página inicial
+func indexHandler(w http.ResponseWriter, r *http.Request) {
+	renderDashboard(w, brandingPage(getConfig().Branding))
+}
+
+// Handler para os arquivos adicionais do front-end em /static/: o logo e o CSS da seção [branding] em
+// /static/branding/, os ícones de favicon_dir em /static/favicon/ e os demais (CSS, JS, imagens) lidos de
+// assets_dir/static ou do binário
+func staticHandler(w http.ResponseWriter, r *http.Request) {
+	config := getConfig().Branding
+	switch path := r.URL.Path; {
+	case path == "/static/branding/custom.css" && config.CSS != "":
+		http.ServeFile(w, r, config.CSS)
+	case strings.HasPrefix(path, "/static/branding/logo") && config.Logo != "" && !isURL(config.Logo):
+		http.ServeFile(w, r, config.Logo)
+	case strings.HasPrefix(path, "/static/favicon/") && config.FaviconDir != "":
+		http.StripPrefix("/static/favicon/", http.FileServer(http.Dir(config.FaviconDir))).ServeHTTP(w, r)
+	default:
+		http.FileServerFS(assets()).ServeHTTP(w, r)
+	}
+}
+
+// Handler para o /favicon.ico pedido automaticamente pelos navegadores, lido de favicon_dir
+func faviconHandler(w http.ResponseWriter, r *http.Request) {
+	dir := getConfig().Branding.FaviconDir
+	if dir == "" {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, filepath.Join(dir, "favicon.ico"))
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Registro de uma ação administrativa
+type auditEntry struct {
+	Time   time.Time   `json:"time"`
+	User   string      `json:"user"`
+	IP     string      `json:"ip,omitempty"`
+	Action string      `json:"action"` // Ex.: service.pause, config.reload, login
+	Target string      `json:"target,omitempty"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+const maxAuditEntries = 10000 // Limite de registros mantidos em memória para consulta
+
+var auditMu sync.Mutex        // Mutex para proteger o log de auditoria
+var auditEntries []auditEntry // Registros mais recentes, em ordem cronológica
+var auditFile *os.File        // Arquivo audit.log (JSON, um registro por linha)
+var auditFilePath string      // Caminho do arquivo aberto
+
+// Função para abrir o audit.log no diretório de logs e carregar os registros mais recentes
+func setupAuditLog(logDir string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	path := filepath.Join(logDir, "audit.log")
+	if auditFile != nil && auditFilePath == path {
+		return
+	}
+	if auditFile != nil {
+		auditFile.Close()
+	}
+
+	if file, err := os.Open(path); err == nil {
+		auditEntries = nil
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var entry auditEntry
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+				auditEntries = append(auditEntries, entry)
+			}
+		}
+		file.Close()
+		if len(auditEntries) > maxAuditEntries {
+			auditEntries = auditEntries[len(auditEntries)-maxAuditEntries:]
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Println(tr("Erro ao abrir o log de auditoria:"), err)
+		auditFile = nil
+		return
+	}
+	auditFile, auditFilePath = file, path
+}
+
+// Função para registrar uma ação no log de auditoria
+func recordAudit(user, ip, action, target string, before, after interface{}) {
+	entry := auditEntry{Time: time.Now(), User: user, IP: ip, Action: action, Target: target, Before: before, After: after}
+	if entry.User == "" {
+		entry.User = "anonymous"
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditEntries = append(auditEntries, entry)
+	if len(auditEntries) > maxAuditEntries {
+		auditEntries = auditEntries[len(auditEntries)-maxAuditEntries:]
+	}
+	if auditFile != nil {
+		data, _ := json.Marshal(entry)
+		if _, err := auditFile.Write(append(data, '\n')); err != nil {
+			log.Println(tr("Erro ao gravar o log de auditoria:"), err)
+		}
+	}
+}
+
+// Função auxiliar para registrar uma ação feita por uma requisição (usuário e IP da requisição)
+func auditRequest(r *http.Request, action, target string, before, after interface{}) {
+	recordAudit(currentUser(r), clientIP(r), action, target, before, after)
+}
+
+// Último login registrado por usuário e IP; como Basic e LDAP enviam as credenciais
+// a cada requisição, o login só é registrado novamente após authCacheTTL
+var lastLoginMu sync.Mutex
+var lastLogins = map[string]time.Time{}
+
+// Função para registrar o login de um usuário autenticado por Basic ou LDAP
+func auditLogin(r *http.Request, user, method, role string) {
+	ip := clientIP(r)
+	key := method + "\x00" + user + "\x00" + ip
+	lastLoginMu.Lock()
+	last, seen := lastLogins[key]
+	fresh := !seen || time.Since(last) > authCacheTTL
+	if fresh {
+		lastLogins[key] = time.Now()
+	}
+	lastLoginMu.Unlock()
+	if fresh {
+		recordAudit(user, ip, "login", method, nil, map[string]string{"role": role})
+	}
+}
+
+// Função auxiliar para o nome da ação de pausa/retomada
+func pauseAction(kind string, paused bool) string {
+	if paused {
+		return kind + ".pause"
+	}
+	return kind + ".resume"
+}
+
+// Função para registrar no log de auditoria as diferenças entre duas listas de serviços
+func auditServiceChanges(previous, current []Service) {
+	index := map[string]Service{}
+	for _, service := range previous {
+		index[service.Description] = service
+	}
+	for _, service := range current {
+		old, existed := index[service.Description]
+		delete(index, service.Description)
+		switch {
+		case !existed:
+			recordAudit("system", "", "service.added", service.Description, nil, serviceDefinition(service))
+		case serviceDefinition(old) != serviceDefinition(service):
+			recordAudit("system", "", "service.changed", service.Description, serviceDefinition(old), serviceDefinition(service))
+		}
+	}
+	for _, service := range previous {
+		if _, removed := index[service.Description]; removed {
+			recordAudit("system", "", "service.removed", service.Description, serviceDefinition(service), nil)
+		}
+	}
+}
+
+// Função para descrever a configuração de um serviço no log de auditoria
+func serviceDefinition(service Service) string {
+	definition := service.IP + ":" + service.Port
+	if service.Type == "push" || service.Type == "dns" {
+		definition = service.Type
+	}
+	if service.Group != "" {
+		definition = "[" + service.Group + "] " + definition
+	}
+	keys := make([]string, 0, len(service.Options))
+	for key, value := range service.Options {
+		switch key {
+		case "token":
+			value = "***" // Não expõe o token dos serviços push
+		case "proxy":
+			if proxyURL, err := url.Parse(value); err == nil {
+				value = proxyURL.Redacted() // Nem a senha do proxy
+			}
+		}
+		keys = append(keys, key+"="+value)
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		definition += " " + strings.Join(keys, " ")
+	}
+	return definition
+}
+
+// Handler para consultar o log de auditoria (mais recentes primeiro),
+// com filtros ?user, ?action (prefixo), ?from, ?to e ?limit
+func auditHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	to, err := parseTime(query.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, "Parâmetro to inválido", http.StatusBadRequest)
+		return
+	}
+	from, err := parseTime(query.Get("from"), time.Time{})
+	if err != nil {
+		http.Error(w, "Parâmetro from inválido", http.StatusBadRequest)
+		return
+	}
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "Parâmetro limit inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result := []auditEntry{}
+	auditMu.Lock()
+	for i := len(auditEntries) - 1; i >= 0 && len(result) < limit; i-- {
+		entry := auditEntries[i]
+		if entry.Time.Before(from) || entry.Time.After(to) {
+			continue
+		}
+		if user := query.Get("user"); user != "" && entry.User != user {
+			continue
+		}
+		if action := query.Get("action"); action != "" && !strings.HasPrefix(entry.Action, action) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	auditMu.Unlock()
+	writeJSON(w, http.StatusOK, result)
+}
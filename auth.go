@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações das seções [auth] e [users]
+type AuthConfig struct {
+	Enabled     bool
+	Realm       string
+	Users       map[string]string // Usuário -> hash bcrypt da senha
+	Roles       map[string]string // Usuário -> papel (padrão: admin)
+	PublicPaths []string          // Prefixos de caminho liberados sem autenticação
+	Tokens      map[[32]byte]apiToken
+}
+
+// Função para ler as seções [auth] e [users] do config.ini
+func loadAuthConfig(cfg *ini.File) AuthConfig {
+	section := cfg.Section("auth")
+	config := AuthConfig{
+		Enabled:     section.Key("enabled").MustBool(false),
+		Realm:       section.Key("realm").MustString("Service Monitoring"),
+		Users:       map[string]string{},
+		Roles:       map[string]string{},
+		PublicPaths: []string{"/healthz", "/readyz", "/api/push/"},
+	}
+	if section.HasKey("public_paths") {
+		config.PublicPaths = section.Key("public_paths").Strings(",")
+	}
+	for _, key := range cfg.Section("users").Keys() {
+		config.Users[key.Name()] = key.Value()
+	}
+	for _, key := range cfg.Section("roles").Keys() {
+		if _, ok := roleRank[key.Value()]; !ok {
+			log.Printf(tr("Papel do usuário [%s] ignorado: %q não é viewer, operator ou admin\n"), key.Name(), key.Value())
+			continue
+		}
+		config.Roles[key.Name()] = key.Value()
+	}
+	config.Tokens = loadTokens(cfg)
+	return config
+}
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+const roleContextKey contextKey = "role"
+const groupsContextKey contextKey = "groups"
+
+// Função para obter o usuário autenticado da requisição ("" quando a autenticação está desabilitada)
+func currentUser(r *http.Request) string {
+	user, _ := r.Context().Value(userContextKey).(string)
+	return user
+}
+
+// Função auxiliar para anexar o usuário e o papel autenticados à requisição
+func withPrincipal(r *http.Request, user, role string) *http.Request {
+	ctx := context.WithValue(r.Context(), userContextKey, user)
+	return r.WithContext(context.WithValue(ctx, roleContextKey, role))
+}
+
+// Função auxiliar para anexar à requisição os grupos que a identidade pode ver (tokens com groups=)
+func withVisibleGroups(r *http.Request, groups []string) *http.Request {
+	if len(groups) == 0 {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), groupsContextKey, groups))
+}
+
+// Função para obter os grupos visíveis para a identidade autenticada (nil = todos)
+func visibleGroups(r *http.Request) []string {
+	groups, _ := r.Context().Value(groupsContextKey).([]string)
+	return groups
+}
+
+// Função para verificar se o serviço pertence aos grupos visíveis para a identidade autenticada
+func serviceVisible(r *http.Request, service Service) bool {
+	groups := visibleGroups(r)
+	return len(groups) == 0 || slices.Contains(groups, service.Group)
+}
+
+// Função para manter apenas os serviços visíveis para a identidade autenticada
+func filterVisible(r *http.Request, services []Service) []Service {
+	if len(visibleGroups(r)) == 0 {
+		return services
+	}
+	return slices.DeleteFunc(services, func(service Service) bool { return !serviceVisible(r, service) })
+}
+
+// Rotas que limitam a resposta aos grupos visíveis; as demais são recusadas para tokens com groups=, já que
+// exporiam serviços de outros grupos (GraphQL, Grafana, quedas, incidentes, relatórios etc.)
+var groupScopedRoutes = map[string]bool{
+	"/ws":                                   true,
+	"GET /events":                           true,
+	"GET /status.json":                      true,
+	"GET /api/summary":                      true,
+	"GET /api/overall":                      true,
+	"GET /api/groups":                       true,
+	"GET /api/export/status":                true,
+	"GET /favicon.svg":                      true,
+	"GET /badge/{file}":                     true,
+	"GET /api/services/{id}/sla":            true,
+	"GET /api/services/{id}/history":        true,
+	"GET /api/services/{id}/timeseries":     true,
+	"GET /api/services/{id}/history/export": true,
+	"POST /api/services/{id}/pause":         true,
+	"POST /api/services/{id}/resume":        true,
+	"POST /api/services/{id}/ack":           true,
+	"DELETE /api/services/{id}/ack":         true,
+	"GET /api/ui-config":                    true,
+	"GET /api/me":                           true,
+	"GET /static/":                          true,
+	"GET /favicon.ico":                      true,
+	"/":                                     true,
+}
+
+// Cache de credenciais já validadas, evitando recalcular o bcrypt a cada requisição
+var authCacheMu sync.Mutex
+var authCache = map[[32]byte]time.Time{}
+
+const authCacheTTL = 5 * time.Minute
+
+// Hash usado nas comparações de usuários inexistentes
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("usuario-inexistente"), bcrypt.DefaultCost)
+	return hash
+})
+
+// Função para validar usuário e senha contra os hashes bcrypt do config.ini
+func checkPassword(config AuthConfig, user, password string) bool {
+	hash, ok := config.Users[user]
+	if !ok {
+		// Compara mesmo assim para não revelar, pelo tempo de resposta, se o usuário existe
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return false
+	}
+
+	key := sha256.Sum256([]byte(user + "\x00" + hash + "\x00" + password))
+	authCacheMu.Lock()
+	expires, cached := authCache[key]
+	authCacheMu.Unlock()
+	if cached && time.Now().Before(expires) {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false
+	}
+	authCacheMu.Lock()
+	authCache[key] = time.Now().Add(authCacheTTL)
+	authCacheMu.Unlock()
+	return true
+}
+
+// Função para verificar se o caminho está liberado sem autenticação
+func isPublicPath(config AuthConfig, path string) bool {
+	for _, prefix := range config.PublicPaths {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware de autenticação (sessão do SSO, bearer token ou HTTP Basic) para o dashboard, o WebSocket e a API
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config := getConfig()
+		if !config.Auth.Enabled && !config.OIDC.Enabled && !config.LDAP.Enabled || isPublicPath(config.Auth, r.URL.Path) || isPublicStatusPath(r.URL.Path) || strings.HasPrefix(r.URL.Path, "/auth/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s, ok := lookupSession(r); ok {
+			serveWithRole(next, w, withPrincipal(r, s.User, s.Role))
+			return
+		}
+
+		if value, ok := bearerToken(r); ok {
+			token, ok := lookupToken(config.Auth, value)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+config.Auth.Realm+`", error="invalid_token"`)
+				http.Error(w, "Token inválido", http.StatusUnauthorized)
+				return
+			}
+			serveWithRole(next, w, withVisibleGroups(withPrincipal(r, "token:"+token.Name, scopeRole(token.Scope)), token.Groups))
+			return
+		}
+
+		user, password, ok := r.BasicAuth()
+		if ok && config.Auth.Enabled && checkPassword(config.Auth, user, password) {
+			role, ok := config.Auth.Roles[user]
+			if !ok {
+				role = roleAdmin
+			}
+			auditLogin(r, user, "basic", role)
+			serveWithRole(next, w, withPrincipal(r, user, role))
+			return
+		}
+		if ok && config.LDAP.Enabled {
+			if role, valid := checkLDAP(config.LDAP, user, password); valid {
+				auditLogin(r, user, "ldap", role)
+				serveWithRole(next, w, withPrincipal(r, user, role))
+				return
+			}
+		}
+
+		if ok {
+			recordAudit(user, clientIP(r), "login.failed", "basic", nil, nil)
+		}
+
+		// Navegadores sem credenciais vão para o login do SSO, quando habilitado
+		if config.OIDC.Enabled && !ok && wantsHTML(r) {
+			http.Redirect(w, r, "/auth/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusFound)
+			return
+		}
+		if config.Auth.Enabled || config.LDAP.Enabled {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+config.Auth.Realm+`", charset="UTF-8"`)
+		}
+		http.Error(w, "Autenticação necessária", http.StatusUnauthorized)
+	})
+}
+
+// Função auxiliar que recusa a requisição quando o papel do usuário não atende ao exigido pela rota ou quando
+// a identidade é limitada a grupos e a rota não aplica esse limite
+func serveWithRole(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	if required := requiredRole(r); !roleAllows(currentRole(r), required) {
+		http.Error(w, "Permissão insuficiente: requer o papel "+required, http.StatusForbidden)
+		return
+	}
+	if _, pattern := mux.Handler(r); len(visibleGroups(r)) > 0 && !groupScopedRoutes[pattern] {
+		http.Error(w, "Rota indisponível para tokens limitados a grupos", http.StatusForbidden)
+		return
+	}
+	next.ServeHTTP(w, r)
+}
+
+// Função para gerar o hash bcrypt de uma senha (usada pela flag -hash-password)
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
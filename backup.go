@@ -0,0 +1,345 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+const backupVersion = 1 // Versão do formato do arquivo de backup
+
+// Estado em memória que não é gravado no banco (silêncios, alertas e reconhecimentos, pausas)
+type backupRuntime struct {
+	Silences       []silence              `json:"silences"`
+	Alerts         map[string]*alertState `json:"alerts"` // Inclui os reconhecimentos das quedas
+	PausedServices []string               `json:"paused_services"`
+	PausedGroups   []string               `json:"paused_groups"`
+}
+
+// Função para gravar o backup em um arquivo zip: manifest.json, checks.jsonl (histórico), outages.json,
+// incidents.json, annotations.json e runtime.json. Com o banco habilitado, o histórico e as quedas vêm do
+// banco (todo o período retido); sem ele, da memória.
+func writeBackup(out io.Writer, store Storage) error {
+	archive := zip.NewWriter(out)
+	writeJSONFile := func(name string, v interface{}) error {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	hostname, _ := os.Hostname()
+	if err := writeJSONFile("manifest.json", map[string]interface{}{"version": backupVersion, "created_at": time.Now(), "host": hostname}); err != nil {
+		return err
+	}
+
+	// Histórico, uma verificação por linha
+	f, err := archive.Create("checks.jsonl")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	if store != nil {
+		var encodeErr error
+		err = store.Load(time.Time{}, func(record checkRecord) {
+			if encodeErr == nil {
+				encodeErr = encoder.Encode(record)
+			}
+		})
+		if err == nil {
+			err = encodeErr
+		}
+	} else {
+		err = encodeMemoryHistory(encoder)
+	}
+	if err != nil {
+		return err
+	}
+
+	outageList := []outage{}
+	if store != nil {
+		err = store.LoadOutages(time.Time{}, func(o outage) { outageList = append(outageList, o) })
+	} else {
+		outagesMu.Lock()
+		for _, o := range outages {
+			outageList = append(outageList, *o)
+		}
+		outagesMu.Unlock()
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeJSONFile("outages.json", outageList); err != nil {
+		return err
+	}
+
+	incidentsMu.Lock()
+	incidentList := []incident{}
+	for _, i := range incidents {
+		incidentList = append(incidentList, *i)
+	}
+	incidentsMu.Unlock()
+	sort.Slice(incidentList, func(a, b int) bool { return incidentList[a].CreatedAt.Before(incidentList[b].CreatedAt) })
+	if err := writeJSONFile("incidents.json", incidentList); err != nil {
+		return err
+	}
+
+	annotationsMu.Lock()
+	annotationList := append([]annotation{}, annotations...)
+	annotationsMu.Unlock()
+	if err := writeJSONFile("annotations.json", annotationList); err != nil {
+		return err
+	}
+
+	if err := writeJSONFile("runtime.json", runtimeSnapshot()); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// Função para gravar as amostras do histórico em memória (sem banco, o status paused não é preservado)
+func encodeMemoryHistory(encoder *json.Encoder) error {
+	historyMu.Lock()
+	records := []checkRecord{}
+	for description, samples := range sampleHistory {
+		for _, sample := range samples {
+			records = append(records, checkRecord{Service: description, Time: sample.Time, Status: sample.Status, LatencyMs: sample.LatencyMs})
+		}
+	}
+	historyMu.Unlock()
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Função para copiar o estado em memória que não é gravado no banco
+func runtimeSnapshot() backupRuntime {
+	runtime := backupRuntime{Silences: activeSilences(), Alerts: map[string]*alertState{}, PausedServices: []string{}, PausedGroups: []string{}}
+	alertMu.Lock()
+	for description, state := range alertStates {
+		copied := *state
+		runtime.Alerts[description] = &copied
+	}
+	alertMu.Unlock()
+	mu.Lock()
+	for description, paused := range pausedServices {
+		if paused {
+			runtime.PausedServices = append(runtime.PausedServices, description)
+		}
+	}
+	for group, paused := range pausedGroups {
+		if paused {
+			runtime.PausedGroups = append(runtime.PausedGroups, group)
+		}
+	}
+	mu.Unlock()
+	return runtime
+}
+
+// Função para restaurar um backup ao iniciar, antes de recarregar o banco: com o banco habilitado, os dados
+// desta instância no banco são substituídos pelos do backup (e recarregados em seguida por setupStorage);
+// sem ele, o backup é carregado direto na memória. O estado em memória (silêncios, alertas, pausas) é
+// sempre restaurado.
+func restoreBackup(path string, config StorageConfig) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	readJSONFile := func(name string, v interface{}) error {
+		f, ok := files[name]
+		if !ok {
+			return fmt.Errorf("%s não encontrado no backup", name)
+		}
+		reader, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		return json.NewDecoder(reader).Decode(v)
+	}
+
+	var manifest struct {
+		Version int `json:"version"`
+	}
+	if err := readJSONFile("manifest.json", &manifest); err != nil {
+		return err
+	}
+	if manifest.Version != backupVersion {
+		return fmt.Errorf("versão do backup não suportada: %d", manifest.Version)
+	}
+	var outageList []outage
+	var incidentList []incident
+	var annotationList []annotation
+	var runtime backupRuntime
+	for name, v := range map[string]interface{}{"outages.json": &outageList, "incidents.json": &incidentList, "annotations.json": &annotationList, "runtime.json": &runtime} {
+		if err := readJSONFile(name, v); err != nil {
+			return err
+		}
+	}
+
+	var store Storage
+	if config.Enabled {
+		if store, err = openStorage(config); err != nil {
+			return err
+		}
+		defer store.Close()
+		if err := store.Clear(); err != nil {
+			return err
+		}
+	}
+
+	// Histórico, gravado em lotes no banco ou incluído direto na memória
+	f, ok := files["checks.jsonl"]
+	if !ok {
+		return fmt.Errorf("checks.jsonl não encontrado no backup")
+	}
+	reader, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	scanner := bufio.NewScanner(reader)
+	batch := []checkRecord{}
+	count := 0
+	for scanner.Scan() {
+		var record checkRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("checks.jsonl, linha %d: %w", count+1, err)
+		}
+		count++
+		if store == nil {
+			appendHistory(record.Service, record.Status, record.LatencyMs, record.Time)
+			continue
+		}
+		if batch = append(batch, record); len(batch) == 1000 {
+			if err := store.Insert(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		if err := store.Insert(batch); err != nil {
+			return err
+		}
+	}
+
+	if store != nil {
+		for _, o := range outageList {
+			if err := store.SaveOutage(o); err != nil {
+				return err
+			}
+		}
+		for _, i := range incidentList {
+			if err := store.SaveIncident(i); err != nil {
+				return err
+			}
+		}
+		for _, a := range annotationList {
+			if err := store.SaveAnnotation(a); err != nil {
+				return err
+			}
+		}
+		for description, state := range runtime.Alerts {
+			if err := store.SaveAlertState(description, *state); err != nil {
+				return err
+			}
+		}
+	} else {
+		outagesMu.Lock()
+		for _, o := range outageList {
+			appendOutage(&o)
+		}
+		outagesMu.Unlock()
+		incidentsMu.Lock()
+		for _, i := range incidentList {
+			incidents[i.ID] = &i
+		}
+		incidentsMu.Unlock()
+		annotationsMu.Lock()
+		for _, a := range annotationList {
+			insertAnnotation(a)
+		}
+		annotationsMu.Unlock()
+	}
+
+	silencesMu.Lock()
+	for _, s := range runtime.Silences {
+		silences[s.ID] = s
+	}
+	silencesMu.Unlock()
+	alertMu.Lock()
+	for description, state := range runtime.Alerts {
+		alertStates[description] = state
+	}
+	alertMu.Unlock()
+	mu.Lock()
+	for _, description := range runtime.PausedServices {
+		pausedServices[description] = true
+	}
+	for _, group := range runtime.PausedGroups {
+		pausedGroups[group] = true
+	}
+	mu.Unlock()
+
+	log.Printf(tr("Backup %s restaurado: %d verificações, %d quedas, %d incidentes, %d anotações, %d silêncios\n"),
+		path, count, len(outageList), len(incidentList), len(annotationList), len(runtime.Silences))
+	return nil
+}
+
+// Função para gravar o backup a partir do banco, sem iniciar o monitoramento (opção -backup)
+func backupFromStorage(path string, config StorageConfig) error {
+	var store Storage
+	if config.Enabled {
+		var err error
+		if store, err = openStorage(config); err != nil {
+			return err
+		}
+		defer store.Close()
+		restoreIncidents(store)
+		restoreAnnotations(store, time.Time{})
+	} else {
+		log.Println(tr("Persistência desabilitada ([storage]): o backup conterá apenas estruturas vazias"))
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeBackup(out, store); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Handler para baixar o backup do histórico e do estado atual do monitor
+func backupHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="backup-`+time.Now().Format("20060102-150405")+`.zip"`)
+	auditRequest(r, "backup.download", "", nil, nil)
+	if err := writeBackup(w, storage); err != nil {
+		log.Println(tr("Erro ao gerar o backup:"), err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Modelo do badge no estilo do shields.io (rótulo à esquerda, status à direita)
+const badgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[3]s: %[4]s">
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[6]d" height="20" fill="%[5]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[7]d" y="14">%[3]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text><text x="%[8]d" y="14">%[4]s</text>
+</g>
+</svg>`
+
+// Cores do badge para cada status
+var badgeColors = map[string]string{"green": "#4c1", "red": "#e05d44", "paused": "#9f9f9f", "unknown": "#9f9f9f"}
+
+// Função para localizar um serviço pelo ID ou pela descrição (sem diferenciar maiúsculas)
+func findServiceByName(name string) (Service, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	id, err := strconv.Atoi(name)
+	for _, service := range latestServicesState {
+		if (err == nil && service.ID == id) || strings.EqualFold(service.Description, name) {
+			return service, true
+		}
+	}
+	return Service{}, false
+}
+
+// Função para estimar a largura do texto no badge (aproximação para Verdana 11px)
+func badgeTextWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
+
+// Handler para o badge SVG de um serviço (/badge/{service}.svg)
+func badgeHandler(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	if !strings.HasSuffix(file, ".svg") {
+		http.NotFound(w, r)
+		return
+	}
+	service, ok := findServiceByName(strings.TrimSuffix(file, ".svg"))
+	if !ok || !serviceVisible(r, service) {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+
+	var message string
+	switch service.Status {
+	case "green":
+		message = fmt.Sprintf("up %dms", service.LatencyMs)
+	case "red":
+		message = "down"
+	default:
+		message = service.Status
+	}
+	color, ok := badgeColors[service.Status]
+	if !ok {
+		color = badgeColors["unknown"]
+	}
+
+	label := html.EscapeString(service.Description)
+	message = html.EscapeString(message)
+	labelWidth := badgeTextWidth(service.Description)
+	messageWidth := badgeTextWidth(message)
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	fmt.Fprintf(w, badgeTemplate, labelWidth+messageWidth, labelWidth, label, message, color, messageWidth, labelWidth/2, labelWidth+messageWidth/2)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Benchmarks do caminho crítico do hub com serviços e clientes sintéticos: publicação do snapshot completo, delta
+// de um serviço, snapshot de um cliente que se conecta e o /status.json. Usados para avaliar implantações com
+// milhares de serviços antes de colocá-las em produção:
+//
+//	go test -run '^$' -bench . -benchmem
+
+// Quantidades de serviços medidas em cada benchmark
+var benchmarkSizes = []int{1000, 5000, 10000}
+
+// Função para executar o benchmark com cada quantidade de serviços, sobre um hub com os serviços já publicados
+// e 100 clientes WebSocket (metade recebe todos os serviços e a outra metade assina um grupo, como os painéis
+// dos times)
+func benchmarkHub(b *testing.B, fn func(b *testing.B, h *wsHub, services []Service)) {
+	for _, count := range benchmarkSizes {
+		b.Run(strconv.Itoa(count), func(b *testing.B) {
+			services := benchmarkServices(count)
+			h := newHub(1)
+			h.publish(services)
+
+			stop := make(chan struct{})
+			b.Cleanup(func() { close(stop) })
+			for i := range 100 {
+				var filter *wsFilter
+				if i%2 == 1 {
+					filter = &wsFilter{Groups: []string{services[i%len(services)].Group}}
+				}
+				client := h.register(filter, 0)
+				go func() {
+					for {
+						select {
+						case <-client.send:
+						case <-stop:
+							return
+						}
+					}
+				}()
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			fn(b, h, services)
+		})
+	}
+}
+
+// Publicação do snapshot completo
+func BenchmarkHubPublish(b *testing.B) {
+	benchmarkHub(b, func(b *testing.B, h *wsHub, services []Service) {
+		for range b.N {
+			h.publish(services)
+		}
+	})
+}
+
+// Delta de um serviço
+func BenchmarkHubUpdate(b *testing.B) {
+	benchmarkHub(b, func(b *testing.B, h *wsHub, services []Service) {
+		for n := range b.N {
+			service := services[n%len(services)]
+			service.LatencyMs = int64(n)
+			service.ResponseTime = formatResponseTime(service.LatencyMs)
+			h.update(service)
+		}
+	})
+}
+
+// Snapshot de um cliente conectando
+func BenchmarkHubSnapshot(b *testing.B) {
+	benchmarkHub(b, func(b *testing.B, h *wsHub, services []Service) {
+		for range b.N {
+			h.snapshot(nil)
+		}
+	})
+}
+
+// Snapshot de um cliente que assina um grupo
+func BenchmarkHubSnapshotGroup(b *testing.B) {
+	benchmarkHub(b, func(b *testing.B, h *wsHub, services []Service) {
+		filter := &wsFilter{Groups: []string{services[0].Group}}
+		for range b.N {
+			h.snapshot(filter)
+		}
+	})
+}
+
+// /status.json sem parâmetros
+func BenchmarkStatusJSON(b *testing.B) {
+	benchmarkHub(b, func(b *testing.B, h *wsHub, services []Service) {
+		for range b.N {
+			h.statusJSON()
+		}
+	})
+}
+
+// Função para gerar serviços sintéticos com o estado completo (uptime, percentis, sparkline), em grupos de 50
+func benchmarkServices(count int) []Service {
+	now := time.Now()
+	services := make([]Service, count)
+	for i := range services {
+		status := "green"
+		if i%40 == 0 {
+			status = "red"
+		}
+		sparkline := make([]int64, 30)
+		for j := range sparkline {
+			sparkline[j] = int64(10 + (i+j)%50)
+		}
+		services[i] = Service{
+			ID:           i + 1,
+			Description:  "Serviço " + strconv.Itoa(i+1),
+			Group:        "Grupo " + strconv.Itoa(i/50+1),
+			IP:           "10.0." + strconv.Itoa(i/250) + "." + strconv.Itoa(i%250),
+			Port:         "443",
+			Status:       status,
+			ResponseTime: formatResponseTime(int64(10 + i%50)),
+			LatencyMs:    int64(10 + i%50),
+			Type:         "tcp",
+			Options:      map[string]string{"tags": "producao"},
+			ChangedAt:    &now,
+			Uptime:       &uptimeSummary{},
+			Latency:      map[string]latencyPercentiles{"1h": {}, "24h": {}},
+			Sparkline:    sparkline,
+		}
+	}
+	return services
+}
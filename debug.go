@@ -0,0 +1,64 @@
+package main
+
+import (
+	"expvar"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [debug]
+type DebugConfig struct {
+	Enabled bool   // Habilita /debug/vars e /debug/pprof
+	Listen  string // Endereço da porta administrativa (ex.: 127.0.0.1:6060)
+}
+
+// Função para ler a seção [debug] do config.ini
+func loadDebugConfig(cfg *ini.File) DebugConfig {
+	section := cfg.Section("debug")
+	return DebugConfig{
+		Enabled: section.Key("enabled").MustBool(false),
+		Listen:  section.Key("listen").MustString("127.0.0.1:6060"),
+	}
+}
+
+// Função para iniciar o servidor de debug em uma porta separada, fora do alcance do dashboard.
+// Alterações nesta seção só têm efeito após reiniciar o processo.
+func startDebugServer(config DebugConfig) {
+	if !config.Enabled {
+		return
+	}
+
+	expvar.Publish("services", expvar.Func(func() interface{} { return len(snapshotServices()) }))
+	expvar.Publish("websocket_clients", expvar.Func(func() interface{} { return wsClients.Load() }))
+	expvar.Publish("goroutines", expvar.Func(func() interface{} { return runtime.NumGoroutine() }))
+	expvar.Publish("check_cycles", expvar.Func(func() interface{} {
+		metricsMu.Lock()
+		defer metricsMu.Unlock()
+		return checkCycles
+	}))
+	expvar.Publish("last_cycle_duration_ms", expvar.Func(func() interface{} {
+		metricsMu.Lock()
+		defer metricsMu.Unlock()
+		return lastCycleDuration.Milliseconds()
+	}))
+
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/debug/vars", expvar.Handler())
+	debugMux.HandleFunc("/debug/pprof/", pprof.Index)
+	debugMux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	debugMux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	debugMux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	debugMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := registerServer(&http.Server{Addr: config.Listen, Handler: debugMux})
+	go func() {
+		log.Printf(tr("Servidor de debug iniciado em %s\n"), config.Listen)
+		if err := server.ListenAndServe(); err != nil && !serverClosed(err) {
+			log.Println(tr("Erro no servidor de debug:"), err)
+		}
+	}()
+}
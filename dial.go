@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+	"gopkg.in/ini.v1"
+)
+
+// Famílias de endereços aceitas em address_family e na opção family= dos serviços
+var addressFamilies = []string{"auto", "prefer_ipv4", "ipv4", "ipv6"}
+
+// Configurações da seção [network], aplicadas às conexões das verificações
+type NetworkConfig struct {
+	Family        string        // auto (Happy Eyeballs, IPv6 primeiro), prefer_ipv4, ipv4 ou ipv6 (apenas a família informada)
+	FallbackDelay time.Duration // Espera pela tentativa em andamento antes de começar a do próximo endereço
+	Proxy         *url.URL      // Proxy das verificações TCP (socks5://, socks5h:// ou http://), nil = conexão direta
+	Source        string        // IP ou interface de origem das conexões, vazio = escolhido pelo sistema
+}
+
+// Função para ler a seção [network] do config.ini
+func loadNetworkConfig(cfg *ini.File) NetworkConfig {
+	section := cfg.Section("network")
+	config := NetworkConfig{Family: strings.ToLower(section.Key("address_family").MustString("auto"))}
+	if !slices.Contains(addressFamilies, config.Family) {
+		log.Printf(tr("address_family inválido %q na seção [network] (use %s), usando auto\n"), config.Family, strings.Join(addressFamilies, ", "))
+		config.Family = "auto"
+	}
+	var err error
+	if config.FallbackDelay, err = parseRange(section.Key("fallback_delay").String(), 300*time.Millisecond); err != nil {
+		log.Println(tr("fallback_delay inválido na seção [network], usando 300ms"))
+		config.FallbackDelay = 300 * time.Millisecond
+	}
+	if value := section.Key("proxy").String(); value != "" {
+		if config.Proxy, err = parseProxy(value); err != nil {
+			log.Printf(tr("proxy inválido na seção [network] (%v), conectando diretamente\n"), err)
+		}
+	}
+	config.Source = strings.TrimSpace(section.Key("source").String())
+	return config
+}
+
+// Função para validar o endereço de um proxy: socks5://, socks5h:// (o proxy resolve o nome do host) ou
+// http:// (CONNECT), com usuário e senha opcionais e a porta obrigatória. Os erros não repetem o endereço,
+// que pode conter a senha.
+func parseProxy(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, errors.New("endereço inválido")
+	}
+	if !slices.Contains([]string{"socks5", "socks5h", "http"}, proxyURL.Scheme) {
+		return nil, fmt.Errorf("esquema %q não suportado (use socks5, socks5h ou http)", proxyURL.Scheme)
+	}
+	if proxyURL.Hostname() == "" || proxyURL.Port() == "" {
+		return nil, errors.New("informe o host e a porta do proxy")
+	}
+	return proxyURL, nil
+}
+
+// Função para obter o proxy de um serviço: o da opção proxy= (none = conexão direta) ou o da seção [network]
+func serviceProxy(service Service) *url.URL {
+	value, ok := service.Options["proxy"]
+	if !ok {
+		return getConfig().Network.Proxy
+	}
+	if value == "none" {
+		return nil
+	}
+	proxyURL, _ := parseProxy(value) // Validado ao carregar o config.ini
+	return proxyURL
+}
+
+// Função para obter a família de endereços de um serviço: a da opção family= ou a da seção [network]
+func serviceFamily(service Service) string {
+	if value := service.Options["family"]; value != "" {
+		return value
+	}
+	return getConfig().Network.Family
+}
+
+// Função para obter a origem das conexões de um serviço: a da opção source= (none = escolhida pelo sistema) ou a
+// da seção [network]
+func serviceSource(service Service) string {
+	value, ok := service.Options["source"]
+	if !ok {
+		return getConfig().Network.Source
+	}
+	if value == "none" {
+		return ""
+	}
+	return value
+}
+
+// Função para obter o endereço de origem das conexões com o host: o IP informado ou, para o nome de uma
+// interface, o endereço dela da mesma família do host (IPv4, se o host for um nome), lido a cada conexão para
+// acompanhar mudanças de endereço da interface
+func sourceAddr(source, host string) (netip.Addr, error) {
+	if addr, err := netip.ParseAddr(source); err == nil {
+		return addr, nil
+	}
+	iface, err := net.InterfaceByName(source)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("interface de origem %s: %w", source, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("interface de origem %s: %w", source, err)
+	}
+	ipv6 := false
+	if target, err := netip.ParseAddr(host); err == nil {
+		ipv6 = !target.Unmap().Is4()
+	}
+	for _, addr := range addrs {
+		prefix, err := netip.ParsePrefix(addr.String())
+		if err != nil || prefix.Addr().IsLinkLocalUnicast() {
+			continue
+		}
+		if ip := prefix.Addr(); ip.Is4() != ipv6 {
+			return ip, nil
+		}
+	}
+	family := "IPv4"
+	if ipv6 {
+		family = "IPv6"
+	}
+	return netip.Addr{}, fmt.Errorf("interface de origem %s sem endereço %s", source, family)
+}
+
+// Dialer das conexões das verificações, ligado à origem configurada (vazia = escolhida pelo sistema); também
+// usado para chegar ao proxy
+type sourceDialer struct {
+	source string
+}
+
+// Função para conectar ao endereço informado a partir da origem
+func (d sourceDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	if d.source != "" {
+		host, _, _ := net.SplitHostPort(address)
+		local, err := sourceAddr(d.source, host)
+		if err != nil {
+			return nil, err
+		}
+		dialer.LocalAddr = &net.TCPAddr{IP: local.AsSlice()}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Função para conectar sem prazo, exigida pelo dialer do SOCKS5 (que usa DialContext quando disponível)
+func (d sourceDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// Função para conectar à porta do serviço pelo proxy configurado ou, sem ele, diretamente pelos endereços
+// resolvidos do host
+func connectService(ctx context.Context, service Service) (net.Conn, error) {
+	if proxyURL := serviceProxy(service); proxyURL != nil {
+		return dialProxy(ctx, proxyURL, service)
+	}
+	addrs, err := resolveHost(ctx, service.IP)
+	if err != nil {
+		return nil, err
+	}
+	return dialService(ctx, service, addrs)
+}
+
+// Função para ordenar os endereços do host conforme a família: alternando IPv6 e IPv4 (RFC 8305), a partir da
+// família preferida, ou apenas os endereços da família exigida
+func orderAddrs(addrs []string, family string) []string {
+	var v4, v6 []string
+	for _, addr := range addrs {
+		if ip, err := netip.ParseAddr(addr); err == nil && ip.Unmap().Is4() {
+			v4 = append(v4, addr)
+		} else {
+			v6 = append(v6, addr)
+		}
+	}
+	switch family {
+	case "ipv4":
+		return v4
+	case "ipv6":
+		return v6
+	}
+	first, second := v6, v4
+	if family == "prefer_ipv4" {
+		first, second = v4, v6
+	}
+	ordered := make([]string, 0, len(addrs))
+	for i := range max(len(first), len(second)) {
+		if i < len(first) {
+			ordered = append(ordered, first[i])
+		}
+		if i < len(second) {
+			ordered = append(ordered, second[i])
+		}
+	}
+	return ordered
+}
+
+// Resultado de uma tentativa de conexão
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// Função para conectar à porta do serviço pelos endereços resolvidos do host (Happy Eyeballs): as tentativas
+// começam uma de cada vez, a cada fallback_delay ou assim que a anterior falhar, e a primeira conexão
+// estabelecida vence, para que um host com IPv6 e IPv4 não fique vermelho porque uma das famílias não chega
+// até ele a partir do monitor. O erro retornado é o da primeira tentativa.
+func dialService(ctx context.Context, service Service, addrs []string) (net.Conn, error) {
+	config := getConfig().Network
+	family := serviceFamily(service)
+	dialer := sourceDialer{serviceSource(service)}
+	if local, err := netip.ParseAddr(dialer.source); err == nil {
+		// Com um IP de origem, apenas os endereços da mesma família podem ser conectados
+		family = "ipv6"
+		if local.Unmap().Is4() {
+			family = "ipv4"
+		}
+	}
+	addrs = orderAddrs(addrs, family)
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("host %s sem endereços %s", service.IP, family)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Interrompe as tentativas que ainda não terminaram
+	results := make(chan dialResult, len(addrs))
+	fallback := time.NewTimer(config.FallbackDelay)
+	defer fallback.Stop()
+	started, pending := 0, 0
+	next := func() {
+		if started == len(addrs) {
+			return
+		}
+		addr := net.JoinHostPort(addrs[started], service.Port)
+		started++
+		pending++
+		go func() {
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			results <- dialResult{conn, err}
+		}()
+		fallback.Reset(config.FallbackDelay)
+	}
+
+	next()
+	var firstErr error
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				// Fecha as conexões que ainda forem estabelecidas pelas demais tentativas
+				go func(pending int) {
+					for range pending {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}
+				}(pending)
+				return result.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			next()
+		case <-fallback.C:
+			next()
+		}
+	}
+	return nil, firstErr
+}
+
+// Função para conectar à porta do serviço através do proxy: no socks5, o host é resolvido pelo monitor (pelo
+// cache da seção [dns], no primeiro endereço da família); no socks5h e no http, o nome é enviado ao proxy, que
+// o resolve na rede de destino
+func dialProxy(ctx context.Context, proxyURL *url.URL, service Service) (net.Conn, error) {
+	host := service.IP
+	if proxyURL.Scheme == "socks5" {
+		addrs, err := resolveHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		family := serviceFamily(service)
+		if addrs = orderAddrs(addrs, family); len(addrs) == 0 {
+			return nil, fmt.Errorf("host %s sem endereços %s", service.IP, family)
+		}
+		host = addrs[0]
+	}
+	target := net.JoinHostPort(host, service.Port)
+
+	dialer := sourceDialer{serviceSource(service)}
+	if proxyURL.Scheme == "http" {
+		return dialConnect(ctx, dialer, proxyURL, target)
+	}
+	socks, err := proxy.FromURL(proxyURL, dialer)
+	if err != nil {
+		return nil, err
+	}
+	return socks.(proxy.ContextDialer).DialContext(ctx, "tcp", target)
+}
+
+// Função para abrir um túnel até o destino em um proxy HTTP (método CONNECT), dentro do prazo do contexto
+func dialConnect(ctx context.Context, dialer sourceDialer, proxyURL *url.URL, target string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) }) // Cancelamento antes do prazo
+	defer stop()
+
+	request := &http.Request{Method: http.MethodConnect, URL: &url.URL{Opaque: target}, Host: target, Header: http.Header{}}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := request.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s recusou a conexão com %s: %s", proxyURL.Host, target, response.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	if reader.Buffered() > 0 {
+		return bufferedConn{conn, reader}, nil // O destino já enviou dados, lidos junto com a resposta do proxy
+	}
+	return conn, nil
+}
+
+// Conexão cuja leitura começa pelos dados já lidos para o buffer
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Função para ler primeiro os dados guardados no buffer e depois os da conexão
+func (c bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [discord]
+type DiscordConfig struct {
+	Enabled    bool
+	WebhookURL string            // Webhook padrão
+	Groups     map[string]string // Webhook por grupo de serviços, da seção [discord.groups]
+}
+
+// Função para ler as seções [discord] e [discord.groups] do config.ini
+func loadDiscordConfig(cfg *ini.File) DiscordConfig {
+	section := cfg.Section("discord")
+	config := DiscordConfig{
+		Enabled:    section.Key("enabled").MustBool(false),
+		WebhookURL: section.Key("webhook_url").String(),
+		Groups:     map[string]string{},
+	}
+	for _, key := range cfg.Section("discord.groups").Keys() {
+		config.Groups[key.Name()] = key.String()
+	}
+	return config
+}
+
+// Notificação por webhook do Discord
+type discordNotifier struct {
+	config DiscordConfig
+}
+
+func (n discordNotifier) Name() string { return "discord" }
+
+// Função para publicar a mudança de status como um embed com link para o dashboard
+func (n discordNotifier) Notify(change stateChange) error {
+	webhookURL := n.config.WebhookURL
+	if url, ok := n.config.Groups[change.Service.Group]; ok && change.Service.Group != "" {
+		webhookURL = url
+	}
+	if webhookURL == "" {
+		return fmt.Errorf("informe webhook_url na seção [discord]")
+	}
+
+	color := 0x2eb886
+	if change.To == "red" {
+		color = 0xe01e5a
+	}
+	fields := []map[string]interface{}{
+		{"name": tr("Response time"), "value": change.Service.ResponseTime, "inline": true},
+		{"name": tr("Address"), "value": change.address(), "inline": true},
+	}
+	if change.Service.Group != "" {
+		fields = append(fields, map[string]interface{}{"name": tr("Group"), "value": change.Service.Group, "inline": true})
+	}
+	if change.Service.Message != "" {
+		fields = append(fields, map[string]interface{}{"name": tr("Message"), "value": change.Service.Message})
+	}
+	embed := map[string]interface{}{
+		"title":       change.title(),
+		"description": change.durationText(),
+		"color":       color,
+		"fields":      fields,
+		"timestamp":   change.Time.Format(time.RFC3339),
+		"footer":      map[string]string{"text": "Service Monitoring"},
+	}
+	if url := dashboardURL(); url != "" {
+		embed["url"] = url
+	}
+
+	_, err := postJSON(webhookURL, nil, map[string]interface{}{
+		"username": "Service Monitoring",
+		"embeds":   []interface{}{embed},
+	})
+	return err
+}
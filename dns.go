@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [dns]
+type DNSConfig struct {
+	Cache       bool          // Cache interno das resoluções dos hosts dos serviços
+	Servers     []string      // Servidores DNS consultados (host:porta); vazio = os do /etc/resolv.conf
+	MinTTL      time.Duration // TTL mínimo das resoluções em cache
+	MaxTTL      time.Duration // TTL máximo das resoluções em cache
+	MaxStale    time.Duration // Tempo após a expiração em que a resolução ainda é usada se o DNS não responder (0 desabilita)
+	FallbackTTL time.Duration // TTL das resoluções feitas pelo resolvedor do sistema, que não informa o TTL
+}
+
+// Função para ler a seção [dns] do config.ini
+func loadDNSConfig(cfg *ini.File) DNSConfig {
+	section := cfg.Section("dns")
+	config := DNSConfig{Cache: section.Key("cache").MustBool(true)}
+	for _, server := range section.Key("servers").Strings(",") {
+		config.Servers = append(config.Servers, dnsServerAddress(server))
+	}
+	var err error
+	if config.MinTTL, err = parseRange(section.Key("min_ttl").String(), 5*time.Second); err != nil {
+		log.Println(tr("min_ttl inválido na seção [dns], usando 5s"))
+		config.MinTTL = 5 * time.Second
+	}
+	if config.MaxTTL, err = parseRange(section.Key("max_ttl").String(), 5*time.Minute); err != nil || config.MaxTTL < config.MinTTL {
+		log.Println(tr("max_ttl inválido na seção [dns], usando 5m"))
+		config.MaxTTL = max(5*time.Minute, config.MinTTL)
+	}
+	if value := section.Key("max_stale").MustString("1m"); value != "0" {
+		if config.MaxStale, err = parseRange(value, 0); err != nil {
+			log.Println(tr("max_stale inválido na seção [dns], usando 1m"))
+			config.MaxStale = time.Minute
+		}
+	}
+	if config.FallbackTTL, err = parseRange(section.Key("fallback_ttl").String(), 30*time.Second); err != nil {
+		log.Println(tr("fallback_ttl inválido na seção [dns], usando 30s"))
+		config.FallbackTTL = 30 * time.Second
+	}
+	return config
+}
+
+// Função para completar o endereço de um servidor DNS com a porta 53, se não informada
+func dnsServerAddress(server string) string {
+	server = strings.TrimSpace(server)
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+	return server
+}
+
+// Servidores DNS do sistema, lidos uma única vez do /etc/resolv.conf (vazio no Windows)
+var systemNameservers = sync.OnceValue(func() []string {
+	file, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, dnsServerAddress(fields[1]))
+		}
+	}
+	return servers
+})
+
+// Resolução guardada no cache
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// Resolução em andamento, compartilhada pelas verificações que pedem o mesmo host ao mesmo tempo
+type dnsLookup struct {
+	done  chan struct{}
+	addrs []string
+	err   error
+}
+
+var dnsMu sync.Mutex                      // Mutex para proteger o cache e as resoluções em andamento
+var dnsCache = map[string]dnsEntry{}      // Resoluções por host
+var dnsInflight = map[string]*dnsLookup{} // Resoluções em andamento por host
+
+// Função para resolver o host de um serviço usando o cache: dentro do TTL, a resolução guardada é usada sem
+// consultar o DNS; depois dele, o host é resolvido novamente e, se o DNS não responder, a resolução expirada
+// continua valendo por até max_stale (hosts inexistentes não usam a resolução expirada)
+func resolveHost(ctx context.Context, host string) ([]string, error) {
+	if _, err := netip.ParseAddr(host); err == nil {
+		return []string{host}, nil
+	}
+	config := getConfig().DNS
+	if !config.Cache {
+		return net.DefaultResolver.LookupHost(ctx, host)
+	}
+
+	dnsMu.Lock()
+	entry, cached := dnsCache[host]
+	if cached && time.Now().Before(entry.expires) {
+		dnsMu.Unlock()
+		return entry.addrs, nil
+	}
+	lookup, running := dnsInflight[host]
+	if !running {
+		lookup = &dnsLookup{done: make(chan struct{})}
+		dnsInflight[host] = lookup
+	}
+	dnsMu.Unlock()
+
+	if running {
+		select {
+		case <-lookup.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	} else {
+		var ttl time.Duration
+		lookup.addrs, ttl, lookup.err = lookupHost(ctx, host, config)
+		now := time.Now()
+		dnsMu.Lock()
+		delete(dnsInflight, host)
+		if lookup.err == nil {
+			dnsCache[host] = dnsEntry{addrs: lookup.addrs, expires: now.Add(min(max(ttl, config.MinTTL), config.MaxTTL))}
+		}
+		for name, old := range dnsCache {
+			if now.After(old.expires.Add(config.MaxStale)) {
+				delete(dnsCache, name) // Hosts que deixaram de ser verificados
+			}
+		}
+		dnsMu.Unlock()
+		close(lookup.done)
+	}
+
+	if lookup.err != nil {
+		if cached && !hostNotFound(lookup.err) && time.Since(entry.expires) <= config.MaxStale {
+			return entry.addrs, nil
+		}
+		return nil, lookup.err
+	}
+	return lookup.addrs, nil
+}
+
+// Função para verificar se o erro indica que o host não existe (NXDOMAIN ou sem endereços)
+func hostNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// Função para resolver um host nos servidores configurados ou nos do sistema, retornando o TTL da resolução.
+// Sem servidores conhecidos (ex.: Windows) ou em nomes curtos, que dependem dos domínios de busca do sistema,
+// usa o resolvedor do sistema, com o TTL de fallback_ttl.
+func lookupHost(ctx context.Context, host string, config DNSConfig) ([]string, time.Duration, error) {
+	servers := config.Servers
+	if len(servers) == 0 {
+		servers = systemNameservers()
+	}
+	if len(servers) == 0 || !strings.Contains(strings.TrimSuffix(host, "."), ".") {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		return addrs, config.FallbackTTL, err
+	}
+	return queryHost(ctx, host, servers)
+}
+
+// Função para consultar os registros A e AAAA de um host, tentando os servidores em ordem até um responder
+func queryHost(ctx context.Context, host string, servers []string) ([]string, time.Duration, error) {
+	var lastErr error
+	for _, server := range servers {
+		v4, ttl4, err := queryDNS(ctx, server, host, dnsmessage.TypeA)
+		if err != nil {
+			if hostNotFound(err) {
+				return nil, 0, err
+			}
+			lastErr = err
+			continue
+		}
+		v6, ttl6, err := queryDNS(ctx, server, host, dnsmessage.TypeAAAA)
+		if err != nil {
+			v6 = nil // Sem IPv6, usa apenas os endereços IPv4
+		}
+		addrs := append(v4, v6...)
+		if len(addrs) == 0 {
+			return nil, 0, &net.DNSError{Err: "host sem endereços", Name: host, Server: server, IsNotFound: true}
+		}
+		ttl := ttl4
+		if len(v4) == 0 || (len(v6) > 0 && ttl6 < ttl4) {
+			ttl = ttl6
+		}
+		return addrs, ttl, nil
+	}
+	return nil, 0, lastErr
+}
+
+// Função para consultar um servidor DNS diretamente (UDP, repetindo em TCP se a resposta vier truncada),
+// retornando os endereços do tipo pedido e o menor TTL entre os registros da resposta
+func queryDNS(ctx context.Context, server, host string, qtype dnsmessage.Type) ([]string, time.Duration, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(host, ".") + ".")
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: "nome inválido", Name: host, IsNotFound: true}
+	}
+	id := uint16(rand.Uint32())
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := query.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+	response, err := exchangeDNS(ctx, "udp", server, packet)
+	if err == nil && response.Truncated {
+		response, err = exchangeDNS(ctx, "tcp", server, packet)
+	}
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: err.Error(), Name: host, Server: server, IsTimeout: errors.Is(err, context.DeadlineExceeded) || isTimeout(err)}
+	}
+	if response.ID != id {
+		return nil, 0, &net.DNSError{Err: "resposta com ID diferente da consulta", Name: host, Server: server}
+	}
+	switch response.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, 0, &net.DNSError{Err: "no such host", Name: host, Server: server, IsNotFound: true}
+	default:
+		return nil, 0, &net.DNSError{Err: "resposta " + response.RCode.String(), Name: host, Server: server, IsTemporary: true}
+	}
+
+	var addrs []string
+	ttl := uint32(math.MaxUint32)
+	for _, answer := range response.Answers {
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			addrs = append(addrs, netip.AddrFrom4(body.A).String())
+		case *dnsmessage.AAAAResource:
+			addrs = append(addrs, netip.AddrFrom16(body.AAAA).String())
+		}
+		ttl = min(ttl, answer.Header.TTL) // Inclui os CNAMEs da cadeia
+	}
+	if len(addrs) == 0 {
+		return nil, 0, nil
+	}
+	return addrs, time.Duration(ttl) * time.Second, nil
+}
+
+// Função para verificar se o erro de rede é um timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Função para enviar uma consulta ao servidor DNS e ler a resposta, no prazo do contexto (5 segundos sem prazo)
+func exchangeDNS(ctx context.Context, network, server string, packet []byte) (*dnsmessage.Message, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	buffer := make([]byte, math.MaxUint16)
+	var n int
+	if network == "tcp" {
+		// Em TCP, cada mensagem é precedida pelo tamanho em 2 bytes
+		if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(packet))), packet...)); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(conn, buffer[:2]); err != nil {
+			return nil, err
+		}
+		n = int(binary.BigEndian.Uint16(buffer[:2]))
+		if _, err := io.ReadFull(conn, buffer[:n]); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := conn.Write(packet); err != nil {
+			return nil, err
+		}
+		if n, err = conn.Read(buffer); err != nil {
+			return nil, err
+		}
+	}
+
+	var response dnsmessage.Message
+	if err := response.Unpack(buffer[:n]); err != nil {
+		return nil, fmt.Errorf("resposta inválida: %w", err)
+	}
+	return &response, nil
+}
+
+// Função para verificar um serviço do tipo dns: resolve name sem passar pelo cache, no servidor da opção server=
+// ou nos servidores usados pelos demais serviços, e falha se o host não resolver ou, com expect=, se o endereço
+// esperado não estiver na resposta. Com o cache, é a forma de detectar falhas do DNS enquanto os serviços
+// continuam usando as resoluções guardadas.
+func checkDNS(ctx context.Context, service Service) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, getConfig().Timeout)
+	defer cancel()
+
+	start := time.Now()
+	name := service.Options["name"]
+	var addrs []string
+	var err error
+	if server := service.Options["server"]; server != "" {
+		addrs, _, err = queryHost(ctx, name, []string{dnsServerAddress(server)})
+	} else {
+		addrs, _, err = lookupHost(ctx, name, getConfig().DNS)
+	}
+	latency := time.Since(start).Milliseconds()
+
+	if expect := service.Options["expect"]; err == nil && expect != "" && !slices.Contains(addrs, expect) {
+		err = fmt.Errorf("%s resolvido para %s, esperado %s", name, strings.Join(addrs, ", "), expect)
+	}
+	if err != nil {
+		return "red", latency, err
+	}
+	return "green", latency, nil
+}
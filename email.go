@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [email] (notificações por SMTP)
+type EmailConfig struct {
+	Enabled  bool
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string            // Destinatários padrão
+	TLS      string              // "starttls" (padrão), "tls" (porta 465) ou "none"
+	Groups   map[string][]string // Destinatários por grupo, da seção [email.groups]
+}
+
+// Função para ler as seções [email] e [email.groups] do config.ini
+func loadEmailConfig(cfg *ini.File) EmailConfig {
+	section := cfg.Section("email")
+	config := EmailConfig{
+		Enabled:  section.Key("enabled").MustBool(false),
+		Host:     section.Key("host").String(),
+		Port:     section.Key("port").MustString("587"),
+		Username: section.Key("username").String(),
+		Password: section.Key("password").String(),
+		From:     section.Key("from").String(),
+		To:       section.Key("to").Strings(","),
+		TLS:      section.Key("tls").MustString("starttls"),
+		Groups:   map[string][]string{},
+	}
+	for _, key := range cfg.Section("email.groups").Keys() {
+		config.Groups[key.Name()] = key.Strings(",")
+	}
+	return config
+}
+
+// Notificação por e-mail
+type emailNotifier struct {
+	config EmailConfig
+}
+
+func (n emailNotifier) Name() string { return "email" }
+
+// Função para definir os destinatários: a opção email= do serviço tem prioridade
+// sobre a seção [email.groups], que tem prioridade sobre os destinatários padrão
+func (n emailNotifier) recipients(service Service) []string {
+	if value := service.Options["email"]; value != "" {
+		return strings.Split(value, ",")
+	}
+	if to, ok := n.config.Groups[service.Group]; ok && service.Group != "" {
+		return to
+	}
+	return n.config.To
+}
+
+// Função para enviar o e-mail da mudança de status
+func (n emailNotifier) Notify(change stateChange) error {
+	to := n.recipients(change.Service)
+	if len(to) == 0 {
+		return nil
+	}
+
+	body := &strings.Builder{}
+	fmt.Fprintf(body, "%s: %s\r\n", tr("Service"), change.Service.Description)
+	if change.Service.Group != "" {
+		fmt.Fprintf(body, "%s: %s\r\n", tr("Group"), change.Service.Group)
+	}
+	fmt.Fprintf(body, "%s: %s\r\n", tr("Address"), change.address())
+	fmt.Fprintf(body, "%s: %s -> %s\r\n", tr("Status"), change.From, change.To)
+	fmt.Fprintf(body, "%s: %s\r\n", tr("Response time"), change.Service.ResponseTime)
+	fmt.Fprintf(body, "%s\r\n", change.durationText())
+	if change.Service.Message != "" {
+		fmt.Fprintf(body, "%s: %s\r\n", tr("Message"), change.Service.Message)
+	}
+	fmt.Fprintf(body, "%s: %s\r\n", tr("Time"), change.Time.Format("2006-01-02 15:04:05 MST"))
+
+	return sendMail(n.config, to, change.title(), body.String())
+}
+
+// Função para enviar uma mensagem de texto simples pelo servidor SMTP configurado
+func sendMail(config EmailConfig, to []string, subject, body string) error {
+	addr := net.JoinHostPort(config.Host, config.Port)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	tlsConfig := &tls.Config{ServerName: config.Host}
+
+	var conn net.Conn
+	var err error
+	if config.TLS == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("conexão com %s: %w", addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if config.TLS == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("o servidor %s não oferece STARTTLS (use tls=none para enviar sem criptografia)", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+	if config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", config.Username, config.Password, config.Host)); err != nil {
+			return fmt.Errorf("autenticação SMTP: %w", err)
+		}
+	}
+
+	if err := client.Mail(config.From); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(strings.TrimSpace(recipient)); err != nil {
+			return fmt.Errorf("destinatário %s: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	message := "From: " + config.From + "\r\n" +
+		"To: " + strings.Join(to, ", ") + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n" +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" + body
+	if _, err := writer.Write([]byte(message)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
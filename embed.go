@@ -0,0 +1,56 @@
+package main
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações do widget incorporável (seção [embed])
+type EmbedConfig struct {
+	FrameAncestors []string // Origens que podem incorporar /embed em iframes (vazio = qualquer uma)
+}
+
+// Dados repassados ao embed.html
+type embedPage struct {
+	Title    string // Título opcional do widget (?title=)
+	Theme    string // auto, light ou dark (?theme= ou o theme da seção [ui])
+	Language string
+	Messages map[string]string
+	UI       UIConfig
+}
+
+// Função para ler a seção [embed] do config.ini
+func loadEmbedConfig(cfg *ini.File) EmbedConfig {
+	return EmbedConfig{FrameAncestors: splitList(cfg.Section("embed").Key("frame_ancestors").String())}
+}
+
+// Handler para o widget incorporável: uma página mínima, própria para iframes em portais e wikis, com o status
+// ao vivo dos serviços selecionados pelos mesmos filtros do WebSocket (view, group, tag e service)
+func embedHandler(w http.ResponseWriter, r *http.Request) {
+	config := getConfig()
+	query := r.URL.Query()
+	page := embedPage{Title: query.Get("title"), Theme: config.UI.Theme, Language: languageOf(), Messages: dashboardCatalog(), UI: config.UI}
+	if theme := query.Get("theme"); theme == "light" || theme == "dark" || theme == "auto" {
+		page.Theme = theme
+	}
+	if page.Language == "" {
+		page.Language = "en"
+	}
+
+	tmpl, err := template.ParseFS(assets(), "embed.html")
+	if err != nil {
+		log.Println(tr("Erro ao carregar embed.html:"), err)
+		http.Error(w, "Erro ao carregar o widget", http.StatusInternalServerError)
+		return
+	}
+	if len(config.Embed.FrameAncestors) > 0 {
+		w.Header().Set("Content-Security-Policy", "frame-ancestors "+strings.Join(config.Embed.FrameAncestors, " "))
+	}
+	if err := tmpl.Execute(w, page); err != nil {
+		log.Println(tr("Erro ao gerar o widget:"), err)
+	}
+}
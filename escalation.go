@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Etapa de uma política de escalonamento
+type EscalationStep struct {
+	After    time.Duration // Tempo fora do ar antes de acionar a etapa (0 = imediatamente)
+	Channels []string      // Canais acionados, pelo nome da seção (email, slack, webhook.chamados...)
+}
+
+// Função para ler as seções [escalation.<grupo>] do config.ini, com as etapas ordenadas pelo tempo
+func loadEscalationConfig(cfg *ini.File) map[string][]EscalationStep {
+	policies := map[string][]EscalationStep{}
+	for _, section := range cfg.Section("escalation").ChildSections() {
+		group := strings.TrimPrefix(section.Name(), "escalation.")
+		steps := []EscalationStep{}
+		for _, key := range section.Keys() {
+			var after time.Duration
+			if key.Name() != "0" {
+				var err error
+				if after, err = parseRange(key.Name(), 0); err != nil {
+					log.Printf(tr("Etapa de escalonamento inválida %q no grupo [%s], ignorada\n"), key.Name(), group)
+					continue
+				}
+			}
+			steps = append(steps, EscalationStep{After: after, Channels: splitList(key.String())})
+		}
+		sort.SliceStable(steps, func(i, j int) bool { return steps[i].After < steps[j].After })
+		policies[group] = steps
+	}
+	return policies
+}
+
+// Função para obter a política de escalonamento do grupo do serviço (nil se o grupo não tiver política,
+// caso em que todos os canais são acionados imediatamente)
+func escalationPolicy(service Service) []EscalationStep {
+	return getConfig().Escalation[service.Group]
+}
+
+// Função para calcular quantas etapas da política já devem ter sido acionadas após o tempo fora do ar
+func escalationLevel(steps []EscalationStep, down time.Duration) int {
+	level := 0
+	for level < len(steps) && steps[level].After <= down {
+		level++
+	}
+	return level
+}
+
+// Função para listar os canais das etapas no intervalo [from, to) da política
+func escalationChannels(steps []EscalationStep, from, to int) []string {
+	channels := []string{}
+	for i := from; i < to && i < len(steps); i++ {
+		channels = append(channels, steps[i].Channels...)
+	}
+	return channels
+}
+
+// Função para obter o nome do canal usado nas políticas (o nome da seção do config.ini)
+func channelName(n notifier) string {
+	return strings.ReplaceAll(n.Name(), " ", ".")
+}
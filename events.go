@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+const sseKeepAlive = 30 * time.Second // Intervalo dos comentários enviados para manter a conexão aberta em proxies
+
+// Handler do stream Server-Sent Events: o mesmo conteúdo do WebSocket (snapshot, deltas e mudanças de
+// status), para proxies que bloqueiam o upgrade e consumidores simples (curl, EventSource). Os parâmetros
+// group, tag e service fazem a mesma assinatura da mensagem subscribe do WebSocket.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if !acquireWSClient() {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "Limite de clientes WebSocket atingido", http.StatusTooManyRequests)
+		return
+	}
+	defer releaseWSClient()
+
+	client := hub.register(queryFilter(r), resumeSeq(r))
+	defer hub.unregister(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Desabilita o buffer do nginx
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+	if err := controller.Flush(); err != nil {
+		log.Println(tr("Streaming SSE não suportado:"), err)
+		return
+	}
+
+	// Cada envio tem prazo de wsWriteWait, para não ficar bloqueado em um cliente que parou de ler
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case data := <-client.send:
+			if data == nil {
+				if data, err = hub.snapshot(hub.filterOf(client)); err != nil {
+					log.Println(tr("Erro ao serializar o estado dos serviços:"), err)
+					return
+				}
+			}
+			controller.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", messageSeq(data), messageType(data), data)
+		case <-ticker.C:
+			controller.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+		case <-r.Context().Done():
+			return
+		case <-hub.closing:
+			return // Encerramento do processo: o EventSource reconecta sozinho
+		}
+		if err != nil {
+			log.Println(tr("Erro ao enviar eventos SSE:"), err)
+			return
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Função para enviar uma tabela como CSV ou XLSX, conforme o parâmetro format
+func writeTable(w http.ResponseWriter, r *http.Request, filename string, header []string, rows [][]string) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	var err error
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		err = writeCSV(w, header, rows)
+	case "xlsx":
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.xlsx"`)
+		err = writeXLSX(w, header, rows)
+	default:
+		http.Error(w, "Parâmetro format inválido (use csv ou xlsx)", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Println(tr("Erro ao exportar dados:"), err)
+	}
+}
+
+// Função para escrever a tabela em CSV
+func writeCSV(out io.Writer, header []string, rows [][]string) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Arquivos fixos de uma planilha XLSX com uma única aba
+var xlsxStaticFiles = []struct{ Name, Content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Dados" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// Colunas numéricas das exportações, gravadas como números na planilha XLSX; as demais são sempre texto, para
+// que descrições como "NaN" ou "007" não sejam convertidas
+var xlsxNumericColumns = map[string]bool{
+	"id": true, "response_time_ms": true, "avg_latency_ms": true, "max_latency_ms": true, "checks": true, "failures": true,
+}
+
+// Função para escrever a tabela como uma planilha XLSX mínima (strings inline, sem estilos)
+func writeXLSX(out io.Writer, header []string, rows [][]string) error {
+	archive := zip.NewWriter(out)
+	for _, file := range xlsxStaticFiles {
+		f, err := archive.Create(file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, file.Content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`+"\n")
+	io.WriteString(sheet, `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range append([][]string{header}, rows...) {
+		fmt.Fprintf(sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			ref := xlsxColumn(j) + strconv.Itoa(i+1)
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil && i > 0 && xlsxNumericColumns[header[j]] {
+				fmt.Fprintf(sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+				continue
+			}
+			var escaped strings.Builder
+			xml.EscapeText(&escaped, []byte(value))
+			fmt.Fprintf(sheet, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, escaped.String())
+		}
+		io.WriteString(sheet, `</row>`)
+	}
+	if _, err := io.WriteString(sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// Função para converter o índice da coluna (0, 1, ...) na letra usada pelo Excel (A, B, ..., AA)
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// Handler para exportar o estado atual dos serviços
+func exportStatusHandler(w http.ResponseWriter, r *http.Request) {
+	rows := [][]string{}
+	for _, service := range filterVisible(r, snapshotServices()) {
+		rows = append(rows, []string{strconv.Itoa(service.ID), service.Description, service.Group, service.Status, strconv.FormatInt(service.LatencyMs, 10)})
+	}
+	writeTable(w, r, "status", []string{"id", "description", "group", "status", "response_time_ms"}, rows)
+}
+
+// Handler para exportar o histórico de um serviço, com os mesmos filtros da API JSON
+func exportHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	service, ok := lookupService(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+	from, to, resolution, ok := parseHistoryQuery(w, r)
+	if !ok {
+		return
+	}
+
+	rows := [][]string{}
+	for _, bucket := range downsampleHistory(service.Description, from, to, resolution) {
+		rows = append(rows, []string{
+			bucket.Time.Format(time.RFC3339),
+			bucket.Status,
+			strconv.FormatInt(bucket.AvgLatencyMs, 10),
+			strconv.FormatInt(bucket.MaxLatencyMs, 10),
+			strconv.Itoa(bucket.Checks),
+			strconv.Itoa(bucket.Failures),
+		})
+	}
+	writeTable(w, r, "history-"+strconv.Itoa(service.ID), []string{"time", "status", "avg_latency_ms", "max_latency_ms", "checks", "failures"}, rows)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Estado agregado dos serviços, exibido no favicon dinâmico e enviado em "overall" no snapshot e nos deltas do
+// WebSocket, para que uma aba fixada do navegador sirva de indicador passivo
+type aggregateStatus struct {
+	Color        string `json:"color"`        // green (todos online), yellow (quedas reconhecidas ou serviços ainda não verificados) ou red
+	Down         int    `json:"down"`         // Serviços fora do ar
+	Acknowledged int    `json:"acknowledged"` // Serviços fora do ar com a queda reconhecida
+	Total        int    `json:"total"`
+}
+
+// Cores padrão do favicon, substituídas por up_color e down_color da seção [ui]
+var faviconColors = map[string]string{"green": "#4caf50", "yellow": "#ffc107", "red": "#f44336"}
+
+// Contagens que compõem o estado agregado, mantidas pelo hub a cada delta em vez de recalculadas sobre todos
+// os serviços
+type aggregateCounts struct {
+	total, down, acknowledged, pending int
+}
+
+// Função para somar (delta 1) ou descontar (delta -1) um serviço das contagens
+func (c *aggregateCounts) add(service Service, delta int) {
+	c.total += delta
+	switch service.Status {
+	case "red":
+		c.down += delta
+		if service.Acknowledged != nil {
+			c.acknowledged += delta
+		}
+	case "unknown":
+		c.pending += delta
+	}
+}
+
+// Função para calcular o estado agregado: vermelho com alguma queda não reconhecida, amarelo com quedas
+// reconhecidas ou serviços ainda não verificados, verde com todos online
+func (c aggregateCounts) status() aggregateStatus {
+	result := aggregateStatus{Color: "green", Down: c.down, Acknowledged: c.acknowledged, Total: c.total}
+	if c.down > c.acknowledged {
+		result.Color = "red"
+	} else if c.down > 0 || c.pending > 0 {
+		result.Color = "yellow"
+	}
+	return result
+}
+
+// Função para calcular o estado agregado dos serviços (serviços pausados não contam)
+func aggregateOf(services []Service) aggregateStatus {
+	var counts aggregateCounts
+	for _, service := range services {
+		counts.add(service, 1)
+	}
+	return counts.status()
+}
+
+// Função para contar os serviços que atendem ao filtro, com o último estado enviado de cada serviço (chamada
+// com h.mu travado)
+func (h *wsHub) countsFor(filter *wsFilter) aggregateCounts {
+	if filter == nil {
+		return h.counts
+	}
+	var counts aggregateCounts
+	for _, id := range h.order {
+		if service := h.state[id]; filter.matches(service) {
+			counts.add(service, 1)
+		}
+	}
+	return counts
+}
+
+// Função para serializar o estado agregado das contagens informadas
+func marshalAggregate(counts aggregateCounts) []byte {
+	data, err := json.Marshal(counts.status())
+	if err != nil {
+		log.Println(tr("Erro ao serializar o estado agregado:"), err)
+		return []byte("null")
+	}
+	return data
+}
+
+// Handler para o favicon dinâmico: um círculo na cor do estado agregado, com a quantidade de serviços fora do ar.
+// Aceita os mesmos filtros do WebSocket (view, group, tag e service).
+func statusFaviconHandler(w http.ResponseWriter, r *http.Request) {
+	filter := queryFilter(r)
+	services := []Service{}
+	for _, service := range snapshotServices() {
+		if filter.matches(service) {
+			services = append(services, service)
+		}
+	}
+	status := aggregateOf(services)
+
+	color := faviconColors[status.Color]
+	ui := getConfig().UI
+	if status.Color == "green" && ui.UpColor != "" {
+		color = ui.UpColor
+	} else if status.Color == "red" && ui.DownColor != "" {
+		color = ui.DownColor
+	}
+	label := ""
+	if status.Down > 0 {
+		count := strconv.Itoa(status.Down)
+		if status.Down > 9 {
+			count = "9+"
+		}
+		label = `<text x="16" y="21.5" font-family="sans-serif" font-size="15" font-weight="bold" fill="#fff" text-anchor="middle">` + count + `</text>`
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 32"><circle cx="16" cy="16" r="15" fill="%s"/>%s</svg>`, color, label)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const feedSize = 50 // Quantidade de mudanças de status publicadas no feed
+
+// Estruturas do feed RSS 2.0
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+}
+
+// Estruturas do feed Atom
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+}
+
+// Textos exibidos para cada status no feed
+var feedStatusNames = map[string]string{"green": "UP", "red": "DOWN", "paused": "PAUSED"}
+
+// Função para montar o título de uma mudança de status
+func transitionTitle(t statusTransition) string {
+	to, ok := feedStatusNames[t.To]
+	if !ok {
+		to = t.To
+	}
+	return fmt.Sprintf("%s is %s", t.Service, to)
+}
+
+// Função para gerar o identificador único de uma mudança de status
+func transitionID(link string, t statusTransition) string {
+	return fmt.Sprintf("%s#%s-%d", link, url.PathEscape(t.Service), t.Time.UnixNano())
+}
+
+// Função para obter a URL base do dashboard a partir da requisição
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// Handler para o feed de mudanças de status (RSS 2.0 por padrão, Atom com ?format=atom)
+func feedHandler(w http.ResponseWriter, r *http.Request) {
+	link := baseURL(r) + "/"
+	transitions := recentTransitions(feedSize)
+
+	var feed interface{}
+	if r.URL.Query().Get("format") == "atom" {
+		updated := time.Now()
+		if len(transitions) > 0 {
+			updated = transitions[0].Time
+		}
+		atom := atomFeed{Title: "Service Monitoring Dashboard", ID: link, Link: atomLink{Href: link}, Updated: updated.Format(time.RFC3339)}
+		for _, t := range transitions {
+			atom.Entries = append(atom.Entries, atomEntry{
+				Title:   transitionTitle(t),
+				ID:      transitionID(link, t),
+				Link:    atomLink{Href: link},
+				Updated: t.Time.Format(time.RFC3339),
+			})
+		}
+		feed = atom
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	} else {
+		rss := rssFeed{Version: "2.0", Channel: rssChannel{Title: "Service Monitoring Dashboard", Link: link, Description: "Mudanças de status dos serviços monitorados"}}
+		for _, t := range transitions {
+			rss.Channel.Items = append(rss.Channel.Items, rssItem{
+				Title:   transitionTitle(t),
+				Link:    link,
+				GUID:    transitionID(link, t),
+				PubDate: t.Time.Format(time.RFC1123Z),
+			})
+		}
+		feed = rss
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	}
+
+	w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		log.Println(tr("Erro ao gerar feed:"), err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [gotify]
+type GotifyConfig struct {
+	Enabled      bool
+	Server       string // Ex.: https://gotify.empresa.com
+	Token        string // Token da aplicação cadastrada no Gotify
+	PriorityDown int    // Prioridade das quedas (0 a 10)
+	PriorityUp   int    // Prioridade das recuperações
+}
+
+// Função para ler a seção [gotify] do config.ini
+func loadGotifyConfig(cfg *ini.File) GotifyConfig {
+	section := cfg.Section("gotify")
+	return GotifyConfig{
+		Enabled:      section.Key("enabled").MustBool(false),
+		Server:       strings.TrimSuffix(section.Key("server").String(), "/"),
+		Token:        section.Key("token").String(),
+		PriorityDown: section.Key("priority_down").MustInt(8),
+		PriorityUp:   section.Key("priority_up").MustInt(4),
+	}
+}
+
+// Notificação por push do Gotify
+type gotifyNotifier struct {
+	config GotifyConfig
+}
+
+func (n gotifyNotifier) Name() string { return "gotify" }
+
+// Função para enviar a mudança de status como mensagem da aplicação
+func (n gotifyNotifier) Notify(change stateChange) error {
+	if n.config.Server == "" || n.config.Token == "" {
+		return fmt.Errorf("informe server e token na seção [gotify]")
+	}
+	priority := n.config.PriorityUp
+	if change.To == "red" {
+		priority = n.config.PriorityDown
+	}
+	message := change.durationText() + "\nResponse time: " + change.Service.ResponseTime
+	if change.Service.Message != "" {
+		message += "\n" + change.Service.Message
+	}
+	payload := map[string]interface{}{
+		"title":    change.title(),
+		"message":  message,
+		"priority": priority,
+	}
+	if url := dashboardURL(); url != "" {
+		payload["extras"] = map[string]interface{}{
+			"client::notification": map[string]interface{}{"click": map[string]string{"url": url}},
+		}
+	}
+
+	_, err := postJSON(n.config.Server+"/message", map[string]string{"X-Gotify-Key": n.config.Token}, payload)
+	return err
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Contrato do datasource simple-JSON/Infinity do Grafana, servido em /grafana/.
+// Os alvos (targets) são as descrições dos serviços para séries de tempo de resposta,
+// ou "status:<descrição>" para séries de disponibilidade (1 online, 0 offline).
+
+const grafanaStatusPrefix = "status:"
+
+// Intervalo de tempo enviado pelo Grafana
+type grafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Handler de teste de conexão do datasource
+func grafanaTestHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Handler que lista os alvos disponíveis
+func grafanaSearchHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Target string `json:"target"`
+	}
+	json.NewDecoder(r.Body).Decode(&request) // O corpo é opcional
+
+	targets := []string{}
+	for _, service := range snapshotServices() {
+		for _, target := range []string{service.Description, grafanaStatusPrefix + service.Description} {
+			if strings.Contains(strings.ToLower(target), strings.ToLower(request.Target)) {
+				targets = append(targets, target)
+			}
+		}
+	}
+	sort.Strings(targets)
+	writeJSON(w, http.StatusOK, targets)
+}
+
+// Handler que retorna as séries de tempo dos alvos solicitados
+func grafanaQueryHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Range      grafanaRange `json:"range"`
+		IntervalMs int64        `json:"intervalMs"`
+		Targets    []struct {
+			Target string `json:"target"`
+		} `json:"targets"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	resolution := time.Duration(request.IntervalMs) * time.Millisecond
+	if resolution <= 0 {
+		resolution = time.Minute
+	}
+
+	type series struct {
+		Target     string     `json:"target"`
+		Datapoints [][2]int64 `json:"datapoints"`
+	}
+	result := []series{}
+	for _, target := range request.Targets {
+		description, statusSeries := strings.CutPrefix(target.Target, grafanaStatusPrefix)
+		points := [][2]int64{}
+		for _, bucket := range downsampleHistory(description, request.Range.From, request.Range.To, resolution) {
+			value := bucket.AvgLatencyMs
+			if statusSeries {
+				value = 0
+				if bucket.Status == "green" {
+					value = 1
+				}
+			}
+			points = append(points, [2]int64{value, bucket.Time.UnixMilli()})
+		}
+		result = append(result, series{Target: target.Target, Datapoints: points})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Handler que retorna as quedas (intervalos em vermelho) como anotações;
+// a query da anotação filtra pela descrição do serviço (vazia para todos)
+func grafanaAnnotationsHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Range      grafanaRange           `json:"range"`
+		Annotation map[string]interface{} `json:"annotation"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	filter, _ := request.Annotation["query"].(string)
+
+	type annotation struct {
+		Annotation map[string]interface{} `json:"annotation"`
+		Time       int64                  `json:"time"`
+		TimeEnd    int64                  `json:"timeEnd"`
+		Title      string                 `json:"title"`
+		Text       string                 `json:"text"`
+		Tags       []string               `json:"tags"`
+	}
+	result := []annotation{}
+	for _, service := range snapshotServices() {
+		if filter != "" && !strings.EqualFold(service.Description, filter) {
+			continue
+		}
+		tags := []string{"outage"}
+		if service.Group != "" {
+			tags = append(tags, service.Group)
+		}
+		for _, span := range historySpans(service.Description, request.Range.From, request.Range.To) {
+			if span.Status != "red" {
+				continue
+			}
+			result = append(result, annotation{
+				Annotation: request.Annotation,
+				Time:       span.Start.UnixMilli(),
+				TimeEnd:    span.End.UnixMilli(),
+				Title:      service.Description + " DOWN",
+				Text:       "Fora do ar por " + span.End.Sub(span.Start).Round(time.Second).String(),
+				Tags:       tags,
+			})
+		}
+	}
+
+	// Anotações registradas pela API (deploys, manutenções), aplicáveis aos serviços selecionados
+	for _, a := range annotationsInRange(request.Range.From, request.Range.To) {
+		if filter != "" && a.Service != "" && !strings.EqualFold(a.Service, filter) {
+			continue
+		}
+		item := annotation{Annotation: request.Annotation, Time: a.Time.UnixMilli(), TimeEnd: a.Time.UnixMilli(), Title: a.Text, Text: a.Text, Tags: slices.Clone(a.Tags)}
+		if a.End != nil {
+			item.TimeEnd = a.End.UnixMilli()
+		}
+		for _, label := range []string{a.Service, a.Group} {
+			if label != "" {
+				item.Tags = append(item.Tags, label)
+			}
+		}
+		result = append(result, item)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Resumo dos serviços de um grupo
+type groupSummary struct {
+	Name           string    `json:"name"`
+	Total          int       `json:"total"`
+	Up             int       `json:"up"`
+	Down           int       `json:"down"`
+	Paused         int       `json:"paused"`
+	WorstLatencyMs int64     `json:"worst_latency_ms"`
+	Services       []Service `json:"-"`
+}
+
+// Função para agrupar os serviços e calcular os totais de cada grupo (ordenados pelo nome)
+func summarizeGroups(services []Service) []groupSummary {
+	index := map[string]int{}
+	groups := []groupSummary{}
+	for _, service := range services {
+		i, ok := index[service.Group]
+		if !ok {
+			i = len(groups)
+			index[service.Group] = i
+			groups = append(groups, groupSummary{Name: service.Group})
+		}
+		group := &groups[i]
+		group.Total++
+		group.Services = append(group.Services, service)
+		switch service.Status {
+		case "green":
+			group.Up++
+			if service.LatencyMs > group.WorstLatencyMs {
+				group.WorstLatencyMs = service.LatencyMs
+			}
+		case "red":
+			group.Down++
+		case "paused":
+			group.Paused++
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// Função auxiliar para criar um campo cujo valor é extraído de um Service
+func serviceField(fieldType graphql.Output, value func(Service) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return value(p.Source.(Service)), nil
+		},
+	}
+}
+
+// Função auxiliar para criar um campo cujo valor é extraído de um groupSummary
+func groupField(fieldType graphql.Output, value func(groupSummary) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return value(p.Source.(groupSummary)), nil
+		},
+	}
+}
+
+var graphqlSchema graphql.Schema
+
+// Função para montar o schema GraphQL sobre o modelo de serviços, grupos e histórico
+func buildGraphQLSchema() (graphql.Schema, error) {
+	slaType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SLA",
+		Fields: graphql.Fields{
+			"uptimePercent":    &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(slaReport).UptimePercent, nil }},
+			"outages":          &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(slaReport).Outages, nil }},
+			"mttrSeconds":      &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(slaReport).MTTRSeconds, nil }},
+			"downtimeSeconds":  &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(slaReport).DowntimeSeconds, nil }},
+			"monitoredSeconds": &graphql.Field{Type: graphql.Float, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(slaReport).MonitoredSeconds, nil }},
+		},
+	})
+
+	sampleType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HistorySample",
+		Fields: graphql.Fields{
+			"time":         &graphql.Field{Type: graphql.DateTime, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(historyBucket).Time, nil }},
+			"status":       &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(historyBucket).Status, nil }},
+			"avgLatencyMs": &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(historyBucket).AvgLatencyMs, nil }},
+			"maxLatencyMs": &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(historyBucket).MaxLatencyMs, nil }},
+			"checks":       &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(historyBucket).Checks, nil }},
+			"failures":     &graphql.Field{Type: graphql.Int, Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(historyBucket).Failures, nil }},
+		},
+	})
+
+	serviceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Service",
+		Fields: graphql.Fields{
+			"id":           serviceField(graphql.Int, func(s Service) interface{} { return s.ID }),
+			"description":  serviceField(graphql.String, func(s Service) interface{} { return s.Description }),
+			"group":        serviceField(graphql.String, func(s Service) interface{} { return s.Group }),
+			"status":       serviceField(graphql.String, func(s Service) interface{} { return s.Status }),
+			"responseTime": serviceField(graphql.String, func(s Service) interface{} { return s.ResponseTime }),
+			"latencyMs":    serviceField(graphql.Int, func(s Service) interface{} { return s.LatencyMs }),
+			"sla": &graphql.Field{
+				Type: slaType,
+				Args: graphql.FieldConfigArgument{"range": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "30d"}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					window, err := parseRange(p.Args["range"].(string), 30*24*time.Hour)
+					if err != nil {
+						return nil, err
+					}
+					to := time.Now()
+					return computeSLA(p.Source.(Service), to.Add(-window), to), nil
+				},
+			},
+			"history": &graphql.Field{
+				Type: graphql.NewList(sampleType),
+				Args: graphql.FieldConfigArgument{
+					"range":      &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "24h"},
+					"resolution": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "1m"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					window, err := parseRange(p.Args["range"].(string), 24*time.Hour)
+					if err != nil {
+						return nil, err
+					}
+					resolution, err := parseRange(p.Args["resolution"].(string), time.Minute)
+					if err != nil {
+						return nil, err
+					}
+					to := time.Now()
+					return downsampleHistory(p.Source.(Service).Description, to.Add(-window), to, resolution), nil
+				},
+			},
+		},
+	})
+
+	groupType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Group",
+		Fields: graphql.Fields{
+			"name":           groupField(graphql.String, func(g groupSummary) interface{} { return g.Name }),
+			"total":          groupField(graphql.Int, func(g groupSummary) interface{} { return g.Total }),
+			"up":             groupField(graphql.Int, func(g groupSummary) interface{} { return g.Up }),
+			"down":           groupField(graphql.Int, func(g groupSummary) interface{} { return g.Down }),
+			"paused":         groupField(graphql.Int, func(g groupSummary) interface{} { return g.Paused }),
+			"worstLatencyMs": groupField(graphql.Int, func(g groupSummary) interface{} { return g.WorstLatencyMs }),
+			"services":       groupField(graphql.NewList(serviceType), func(g groupSummary) interface{} { return g.Services }),
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"services": &graphql.Field{
+				Type: graphql.NewList(serviceType),
+				Args: graphql.FieldConfigArgument{
+					"group":  &graphql.ArgumentConfig{Type: graphql.String},
+					"status": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					group, filterGroup := p.Args["group"].(string)
+					status, filterStatus := p.Args["status"].(string)
+					result := []Service{}
+					for _, service := range snapshotServices() {
+						if (filterGroup && service.Group != group) || (filterStatus && service.Status != status) {
+							continue
+						}
+						result = append(result, service)
+					}
+					return result, nil
+				},
+			},
+			"service": &graphql.Field{
+				Type: serviceType,
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.Int},
+					"name": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					for _, service := range snapshotServices() {
+						if id, ok := p.Args["id"].(int); ok && service.ID == id {
+							return service, nil
+						}
+						if name, ok := p.Args["name"].(string); ok && service.Description == name {
+							return service, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			"groups": &graphql.Field{
+				Type: graphql.NewList(groupType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return summarizeGroups(snapshotServices()), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// Handler para consultas GraphQL (POST com JSON ou GET com ?query=)
+func graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Query         string                 `json:"query"`
+		Variables     map[string]interface{} `json:"variables"`
+		OperationName string                 `json:"operationName"`
+	}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			return
+		}
+	} else {
+		request.Query = r.URL.Query().Get("query")
+		request.OperationName = r.URL.Query().Get("operationName")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         graphqlSchema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		Context:        r.Context(),
+	})
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Metadados e resumo de um grupo de serviços (seção [services.<grupo>] do config.ini), para que o dashboard
+// exiba uma seção recolhível por datacenter ou time em vez de uma única lista
+type groupRollup struct {
+	Name  string `json:"name"`  // Vazio = serviços da seção [services], sem grupo
+	Order int    `json:"order"` // Posição do grupo no config.ini
+	overallSummary
+	Up                  int    `json:"up"`
+	WorstLatencyMs      int64  `json:"worst_latency_ms"`                // Maior tempo de resposta entre os serviços online
+	WorstLatencyService string `json:"worst_latency_service,omitempty"` // Serviço com o maior tempo de resposta
+	Services            []int  `json:"services"`                        // IDs dos serviços do grupo, na ordem do config.ini
+}
+
+// Função para agrupar os serviços (na ordem do config.ini) e calcular o resumo de cada grupo
+func groupRollups(services []Service) []groupRollup {
+	members := map[string][]Service{}
+	order := []string{}
+	for _, service := range services {
+		if _, ok := members[service.Group]; !ok {
+			order = append(order, service.Group)
+		}
+		members[service.Group] = append(members[service.Group], service)
+	}
+
+	rollups := []groupRollup{}
+	for i, name := range order {
+		rollup := groupRollup{Name: name, Order: i, overallSummary: summarizeOverall(members[name]), Services: []int{}}
+		rollup.Up = rollup.Counts["green"]
+		for _, service := range members[name] {
+			rollup.Services = append(rollup.Services, service.ID)
+			if service.Status == "green" && service.LatencyMs > rollup.WorstLatencyMs {
+				rollup.WorstLatencyMs = service.LatencyMs
+				rollup.WorstLatencyService = service.Description
+			}
+		}
+		rollups = append(rollups, rollup)
+	}
+	return rollups
+}
+
+// Handler para listar os grupos com o resumo de cada um (x de y online, pior status e pior tempo de resposta),
+// limitado aos grupos que a identidade autenticada pode ver
+func groupsHandler(w http.ResponseWriter, r *http.Request) {
+	allowed := visibleGroups(r)
+	selected := []Service{}
+	for _, service := range snapshotServices() {
+		if len(allowed) == 0 || slices.Contains(allowed, service.Group) {
+			selected = append(selected, service)
+		}
+	}
+	writeJSON(w, http.StatusOK, groupRollups(selected))
+}
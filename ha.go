@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [ha]: par de instâncias com o mesmo banco, em que só o líder verifica e alerta
+type HAConfig struct {
+	Enabled bool
+	Node    string        // Nome deste nó, diferente em cada instância do par (padrão o nome da máquina)
+	Lease   time.Duration // Validade da liderança sem renovação; o reserva assume após esse prazo
+}
+
+// Função para calcular a menor validade aceita para a liderança: o líder sem renovação deixa de iniciar
+// verificações na metade do lease, e essa metade precisa cobrir o intervalo em que o monitoramento percebe a
+// perda da liderança e o timeout das verificações em andamento, com folga
+func minHALease(timeout time.Duration) time.Duration {
+	return max(10*time.Second, 4*(configWatchInterval+timeout))
+}
+
+// Função para ler a seção [ha] do config.ini (timeout é o prazo das verificações da seção [general])
+func loadHAConfig(cfg *ini.File, timeout time.Duration) HAConfig {
+	section := cfg.Section("ha")
+	hostname, _ := os.Hostname()
+	config := HAConfig{
+		Enabled: section.Key("enabled").MustBool(false),
+		Node:    section.Key("node").MustString(hostname),
+	}
+	// O instance= padrão é o nome da máquina, diferente em cada nó: cada um teria a própria liderança e o
+	// próprio histórico, e os dois verificariam e alertariam ao mesmo tempo
+	if config.Enabled && strings.TrimSpace(cfg.Section("storage").Key("instance").String()) == "" {
+		log.Println(tr("Alta disponibilidade desabilitada: defina o mesmo instance= da seção [storage] nos dois nós"))
+		config.Enabled = false
+	}
+	minimum := minHALease(timeout)
+	var err error
+	if config.Lease, err = parseRange(section.Key("lease").String(), 15*time.Second); err != nil || config.Lease < minimum {
+		config.Lease = max(15*time.Second, minimum)
+		if section.HasKey("lease") {
+			log.Printf(tr("lease inválido na seção [ha] (mínimo %s com o timeout atual), usando %s\n"), minimum, config.Lease)
+		}
+	}
+	return config
+}
+
+// Banco compartilhado pelo par, onde a liderança é registrada
+type leaseStore interface {
+	AcquireLease(node string, ttl time.Duration) (string, error) // Adquire ou renova a liderança; retorna o líder atual
+	ReleaseLease(node string) error                              // Libera a liderança deste nó
+}
+
+var haStandby atomic.Bool           // Esta instância é o reserva do par (não verifica nem alerta)
+var haDeadline atomic.Int64         // Até quando (UnixMilli) o líder pode agir sem renovar a liderança (0 = sem prazo)
+var haLeader atomic.Pointer[string] // Nó que detém a liderança, exibido na readiness
+var haSyncedAt time.Time            // Até quando o histórico em memória está completo (usado ao assumir)
+var haRelease func()                // Libera a liderança ao encerrar o processo (nil sem alta disponibilidade)
+
+// Função para verificar se esta instância é a líder (sempre, sem a seção [ha]); o líder deixa de ser
+// considerado líder assim que o prazo da última renovação vence, mesmo com a renovação travada no banco
+func isLeader() bool {
+	if haStandby.Load() {
+		return false
+	}
+	deadline := haDeadline.Load()
+	return deadline == 0 || time.Now().UnixMilli() < deadline
+}
+
+// Função para iniciar a eleição do líder pelo banco da seção [storage], que deve ser o mesmo nas duas instâncias
+// (com o mesmo instance=, obrigatório). O primeiro nó a adquirir a liderança verifica os serviços e envia os
+// alertas e os relatórios; o outro fica de reserva e assume quando a liderança não é renovada dentro de lease
+// (processo encerrado, máquina fora do ar ou sem acesso ao banco). Alterações na seção [ha] exigem reiniciar o processo.
+func setupHA(ctx context.Context, config HAConfig) {
+	haSyncedAt = time.Now()
+	if !config.Enabled {
+		return
+	}
+	store, ok := storage.(leaseStore)
+	if !ok {
+		log.Println(tr("Alta disponibilidade desabilitada: a eleição do líder exige a persistência da seção [storage]"))
+		haStandby.Store(false)
+		return
+	}
+
+	// A primeira tentativa é feita antes de iniciar o monitoramento, para que o líder comece a verificar na hora
+	renewed := electLeader(store, config, time.Time{})
+	go func() {
+		ticker := time.NewTicker(config.Lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				renewed = electLeader(store, config, renewed)
+			}
+		}
+	}()
+	haRelease = func() {
+		if isLeader() {
+			if err := store.ReleaseLease(config.Node); err != nil {
+				log.Println(tr("Erro ao liberar a liderança:"), err)
+			}
+		}
+	}
+}
+
+// Função para adquirir ou renovar a liderança, retornando o momento da última renovação. A liderança vale até a
+// metade do lease a partir do início da renovação, medida pelo relógio local: sem acesso ao banco, o líder deixa
+// de verificar antes de a liderança expirar no banco, para que os dois nós nunca verifiquem ao mesmo tempo.
+func electLeader(store leaseStore, config HAConfig, renewed time.Time) time.Time {
+	start := time.Now()
+	leader, err := store.AcquireLease(config.Node, config.Lease)
+	if err != nil {
+		log.Println(tr("Erro ao renovar a liderança:"), err)
+		if !haStandby.Load() && time.Since(renewed) >= config.Lease/2 {
+			log.Println(tr("Liderança não renovada, deixando de verificar os serviços"))
+			haStandby.Store(true)
+		}
+		return renewed
+	}
+	haLeader.Store(&leader)
+	if leader != config.Node {
+		if !haStandby.Load() {
+			log.Printf(tr("Nó %s assumiu a liderança, deixando de verificar os serviços\n"), leader)
+			haStandby.Store(true)
+		}
+		return renewed
+	}
+	haDeadline.Store(start.Add(config.Lease / 2).UnixMilli())
+	if haStandby.Load() {
+		log.Printf(tr("Nó %s assumiu a liderança\n"), config.Node)
+		haStandby.Store(false)
+	}
+	return start
+}
+
+// Função para assumir o monitoramento: recarrega do banco o que o líder anterior gravou enquanto esta instância
+// era o reserva (resultados, quedas, incidentes, anotações, o estado de alerta e os tokens de API) e reconstrói
+// o estado dos serviços. Com o estado de alerta do líder anterior, quedas já notificadas não são notificadas de
+// novo, mas continuam sendo repetidas e escalonadas e têm a recuperação notificada.
+func takeOver(services *[]Service) {
+	if storage != nil {
+		restoreHistory(storage, haSyncedAt)
+		syncOutages(storage, haSyncedAt)
+		restoreIncidents(storage)
+		syncAnnotations(storage, haSyncedAt)
+		restoreAlertStates(storage)
+		restoreTokens(storage)
+	}
+
+	mu.Lock()
+	restoreServiceStates(*services, nil, getConfig())
+	latestServicesState = make([]Service, len(*services))
+	copy(latestServicesState, *services)
+	mu.Unlock()
+	hub.publish(snapshotServices())
+}
+
+// Função para recarregar as quedas gravadas pelo líder anterior: as iniciadas desde since e o fechamento das que
+// estavam em andamento quando esta instância deixou de verificar
+func syncOutages(store Storage, since time.Time) {
+	outagesMu.Lock()
+	defer outagesMu.Unlock()
+	for _, o := range openOutages {
+		if o.Start.Before(since) {
+			since = o.Start
+		}
+	}
+	err := store.LoadOutages(since, func(o outage) {
+		i := slices.IndexFunc(outages, func(known *outage) bool {
+			return known.Service == o.Service && known.Start.UnixMilli() == o.Start.UnixMilli()
+		})
+		if i < 0 {
+			appendOutage(&o)
+			return
+		}
+		if outages[i].End == nil && o.End != nil {
+			outages[i].End, outages[i].DurationSeconds = o.End, o.DurationSeconds
+			delete(openOutages, o.Service)
+		}
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar as quedas do banco:"), err)
+	}
+}
+
+// Função para recarregar as anotações gravadas desde since que ainda não estão na memória
+func syncAnnotations(store Storage, since time.Time) {
+	annotationsMu.Lock()
+	defer annotationsMu.Unlock()
+	err := store.LoadAnnotations(since, func(a annotation) {
+		if !slices.ContainsFunc(annotations, func(known annotation) bool { return known.ID == a.ID }) {
+			insertAnnotation(a)
+		}
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar as anotações do banco:"), err)
+	}
+}
+
+// Função para liberar a liderança ao encerrar o processo
+func releaseLeadership() {
+	if haRelease != nil {
+		haRelease()
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var configLoaded atomic.Bool      // Indica se o config.ini foi carregado com sucesso
+var schedulerStarted atomic.Int64 // Momento (unix nano) em que o monitoramento foi iniciado
+var lastCycleAt atomic.Int64      // Momento (unix nano) do fim da última verificação de um serviço
+
+// Handler de liveness: o processo está de pé e respondendo
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Handler de readiness: configuração carregada, monitoramento rodando e verificações recentes
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{}
+	ready := true
+
+	if configLoaded.Load() {
+		checks["config"] = "ok"
+	} else {
+		checks["config"] = "não carregada"
+		ready = false
+	}
+
+	// No par de alta disponibilidade, o reserva não fica pronto, para que o balanceador envie o tráfego ao líder
+	standby := !isLeader()
+	if getConfig().HA.Enabled {
+		checks["ha"] = "líder"
+		if standby {
+			checks["ha"] = "reserva"
+			if leader := haLeader.Load(); leader != nil {
+				checks["ha"] = "reserva (líder: " + *leader + ")"
+			}
+			ready = false
+		}
+	}
+
+	started := schedulerStarted.Load()
+	if started == 0 {
+		checks["scheduler"] = "não iniciado"
+		ready = false
+	} else if !standby {
+		checks["scheduler"] = "ok"
+
+		// A última verificação (ou o início do monitoramento, antes da primeira) deve ser recente
+		last := lastCycleAt.Load()
+		if last == 0 {
+			last = started
+		}
+		age := time.Since(time.Unix(0, last))
+		if age > maxCycleAge() {
+			checks["last_cycle"] = "atrasado há " + age.Round(time.Second).String()
+			ready = false
+		} else {
+			checks["last_cycle"] = "ok"
+		}
+	}
+
+	status := http.StatusOK
+	result := "ok"
+	if !ready {
+		status = http.StatusServiceUnavailable
+		result = "unavailable"
+	}
+	writeJSON(w, status, map[string]interface{}{"status": result, "checks": checks})
+}
+
+// Função para calcular a idade máxima aceitável da última verificação, a partir do serviço verificado com mais
+// frequência
+func maxCycleAge() time.Duration {
+	metricsMu.Lock()
+	cycle := lastCycleDuration
+	metricsMu.Unlock()
+
+	maxAge := 3 * (shortestInterval() + cycle)
+	if maxAge < 30*time.Second {
+		maxAge = 30 * time.Second
+	}
+	return maxAge
+}
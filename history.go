@@ -0,0 +1,352 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Intervalo contínuo em que um serviço permaneceu no mesmo status
+type statusSpan struct {
+	Status string    `json:"status"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Resultado individual de uma verificação
+type checkSample struct {
+	Time      time.Time `json:"time"`
+	Status    string    `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+}
+
+const maxSpansPerService = 10000   // Limite de intervalos mantidos em memória por serviço
+const maxSamplesPerService = 20000 // Limite de amostras de tempo de resposta mantidas em memória por serviço
+
+var historyMu sync.Mutex                       // Mutex para proteger o histórico
+var statusHistory = map[string][]statusSpan{}  // Histórico de status por serviço, indexado pela descrição
+var sampleHistory = map[string][]checkSample{} // Amostras de tempo de resposta por serviço, indexadas pela descrição
+
+// Função para registrar o resultado de uma verificação no histórico (e no banco e no TSDB, se habilitados)
+func recordHistory(service Service, status string, latency int64, at time.Time) {
+	storeCheck(checkRecord{Service: service.Description, Time: at, Status: status, LatencyMs: latency})
+	exportCheck(service, status, latency, at)
+	appendHistory(service.Description, status, latency, at)
+}
+
+// Função para incluir um resultado no histórico em memória
+func appendHistory(description string, status string, latency int64, at time.Time) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	// Serviços pausados não geram amostras de tempo de resposta
+	if status == "green" || status == "red" {
+		samples := append(sampleHistory[description], checkSample{Time: at, Status: status, LatencyMs: latency})
+		if len(samples) > maxSamplesPerService {
+			samples = samples[len(samples)-maxSamplesPerService:]
+		}
+		sampleHistory[description] = samples
+	}
+
+	spans := statusHistory[description]
+	if n := len(spans); n > 0 && spans[n-1].Status == status {
+		spans[n-1].End = at // Mesmo status: apenas estende o intervalo atual
+		return
+	}
+
+	// Mudança de status: o intervalo anterior termina onde o novo começa
+	if n := len(spans); n > 0 {
+		spans[n-1].End = at
+	}
+	spans = append(spans, statusSpan{Status: status, Start: at, End: at})
+	if len(spans) > maxSpansPerService {
+		spans = spans[len(spans)-maxSpansPerService:]
+	}
+	statusHistory[description] = spans
+}
+
+// Função para obter o início do status atual de um serviço (nil sem histórico)
+func statusChangedAt(description string) *time.Time {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	spans := statusHistory[description]
+	if len(spans) == 0 {
+		return nil
+	}
+	start := spans[len(spans)-1].Start
+	return &start
+}
+
+// Função para obter os intervalos de um serviço recortados à janela [from, to]
+func historySpans(description string, from, to time.Time) []statusSpan {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	result := []statusSpan{}
+	for _, span := range statusHistory[description] {
+		if span.End.Before(from) || span.Start.After(to) {
+			continue
+		}
+		if span.Start.Before(from) {
+			span.Start = from
+		}
+		if span.End.After(to) {
+			span.End = to
+		}
+		result = append(result, span)
+	}
+	return result
+}
+
+// Função para agregar as amostras de um serviço em intervalos de tamanho fixo (resolution)
+func downsampleHistory(description string, from, to time.Time, resolution time.Duration) []historyBucket {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	buckets := []historyBucket{}
+	for _, sample := range sampleHistory[description] {
+		if sample.Time.Before(from) || sample.Time.After(to) {
+			continue
+		}
+		bucketTime := from.Add(sample.Time.Sub(from).Truncate(resolution))
+		n := len(buckets)
+		if n == 0 || !buckets[n-1].Time.Equal(bucketTime) {
+			buckets = append(buckets, historyBucket{Time: bucketTime, Status: "green"})
+			n++
+		}
+		bucket := &buckets[n-1]
+		bucket.Checks++
+		bucket.sum += sample.LatencyMs
+		bucket.AvgLatencyMs = bucket.sum / int64(bucket.Checks)
+		if sample.LatencyMs > bucket.MaxLatencyMs {
+			bucket.MaxLatencyMs = sample.LatencyMs
+		}
+		if sample.Status == "red" {
+			bucket.Status = "red" // Qualquer falha no intervalo marca o intervalo como falho
+			bucket.Failures++
+		}
+	}
+	return buckets
+}
+
+// Amostras agregadas de um intervalo do histórico
+type historyBucket struct {
+	Time         time.Time `json:"time"`
+	Status       string    `json:"status"`
+	AvgLatencyMs int64     `json:"avg_latency_ms"`
+	MaxLatencyMs int64     `json:"max_latency_ms"`
+	Checks       int       `json:"checks"`
+	Failures     int       `json:"failures"`
+	sum          int64
+}
+
+// Mudança de status de um serviço: derivada do histórico (feed) ou publicada pelo monitor no momento em que
+// acontece (transitions.go), com o motivo
+type statusTransition struct {
+	ServiceID int       `json:"service_id,omitempty"`
+	Service   string    `json:"service"`
+	Group     string    `json:"group,omitempty"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Time      time.Time `json:"time"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Função para listar as mudanças de status mais recentes de todos os serviços (mais recentes primeiro)
+func recentTransitions(limit int) []statusTransition {
+	historyMu.Lock()
+	transitions := []statusTransition{}
+	for description, spans := range statusHistory {
+		for i := 1; i < len(spans); i++ {
+			transitions = append(transitions, statusTransition{
+				Service: description,
+				From:    spans[i-1].Status,
+				To:      spans[i].Status,
+				Time:    spans[i].Start,
+			})
+		}
+	}
+	historyMu.Unlock()
+
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].Time.After(transitions[j].Time) })
+	if len(transitions) > limit {
+		transitions = transitions[:limit]
+	}
+	return transitions
+}
+
+// Função para interpretar datas como RFC 3339 ou unix timestamp (segundos)
+func parseTime(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// Função para interpretar intervalos como "30d", "2w", "12h" ou "90m"
+func parseRange(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(value, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(value, suffix))
+			if err != nil || n <= 0 {
+				return 0, strconv.ErrSyntax
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && d <= 0 {
+		err = strconv.ErrSyntax
+	}
+	return d, err
+}
+
+// Relatório de disponibilidade de um serviço
+type slaReport struct {
+	ID               int       `json:"id"`
+	Service          string    `json:"service"`
+	Range            string    `json:"range"`
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	UptimePercent    float64   `json:"uptime_percent"`
+	Outages          int       `json:"outages"`
+	MTTRSeconds      float64   `json:"mttr_seconds"`
+	DowntimeSeconds  float64   `json:"downtime_seconds"`
+	MonitoredSeconds float64   `json:"monitored_seconds"`
+}
+
+// Função para calcular o SLA de um serviço a partir do histórico
+func computeSLA(service Service, from, to time.Time) slaReport {
+	report := slaReport{ID: service.ID, Service: service.Description, From: from, To: to, UptimePercent: 100}
+
+	var up, down time.Duration
+	var recovered int
+	var recoveredDowntime time.Duration
+	spans := historySpans(service.Description, from, to)
+	for i, span := range spans {
+		duration := span.End.Sub(span.Start)
+		switch span.Status {
+		case "green":
+			up += duration
+		case "red":
+			down += duration
+			report.Outages++
+			if i < len(spans)-1 && spans[i+1].Status == "green" { // Quedas já recuperadas entram no cálculo do MTTR
+				recovered++
+				recoveredDowntime += duration
+			}
+		}
+	}
+
+	report.DowntimeSeconds = down.Seconds()
+	report.MonitoredSeconds = (up + down).Seconds()
+	if up+down > 0 {
+		report.UptimePercent = float64(up) / float64(up+down) * 100
+	}
+	if recovered > 0 {
+		report.MTTRSeconds = (recoveredDowntime / time.Duration(recovered)).Seconds()
+	}
+	return report
+}
+
+// Disponibilidade recente de um serviço, incluída no payload do WebSocket e da API
+type uptimeSummary struct {
+	Day   float64 `json:"24h"`
+	Week  float64 `json:"7d"`
+	Month float64 `json:"30d"`
+}
+
+// Função para calcular o uptime do serviço nas últimas 24 horas, 7 e 30 dias (nil sem histórico)
+func computeUptime(service Service, at time.Time) *uptimeSummary {
+	month := computeSLA(service, at.Add(-30*24*time.Hour), at)
+	if month.MonitoredSeconds == 0 {
+		return nil
+	}
+	round := func(percent float64) float64 { return math.Round(percent*1000) / 1000 }
+	return &uptimeSummary{
+		Day:   round(computeSLA(service, at.Add(-24*time.Hour), at).UptimePercent),
+		Week:  round(computeSLA(service, at.Add(-7*24*time.Hour), at).UptimePercent),
+		Month: round(month.UptimePercent),
+	}
+}
+
+// Handler para o relatório de SLA de um serviço
+func slaHandler(w http.ResponseWriter, r *http.Request) {
+	service, ok := lookupService(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+
+	rangeParam := r.URL.Query().Get("range")
+	window, err := parseRange(rangeParam, 30*24*time.Hour)
+	if err != nil {
+		http.Error(w, "Parâmetro range inválido", http.StatusBadRequest)
+		return
+	}
+	if rangeParam == "" {
+		rangeParam = "30d"
+	}
+
+	to := time.Now()
+	report := computeSLA(service, to.Add(-window), to)
+	report.Range = rangeParam
+	writeJSON(w, http.StatusOK, report)
+}
+
+// Função para interpretar os filtros from, to e resolution das consultas de histórico
+// (responde com erro 400 e retorna false se algum parâmetro for inválido)
+func parseHistoryQuery(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, time.Duration, bool) {
+	query := r.URL.Query()
+	to, err := parseTime(query.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, "Parâmetro to inválido", http.StatusBadRequest)
+		return time.Time{}, time.Time{}, 0, false
+	}
+	from, err := parseTime(query.Get("from"), to.Add(-24*time.Hour))
+	if err != nil || !from.Before(to) {
+		http.Error(w, "Parâmetro from inválido", http.StatusBadRequest)
+		return time.Time{}, time.Time{}, 0, false
+	}
+	resolution, err := parseRange(query.Get("resolution"), time.Minute)
+	if err != nil {
+		http.Error(w, "Parâmetro resolution inválido", http.StatusBadRequest)
+		return time.Time{}, time.Time{}, 0, false
+	}
+	return from, to, resolution, true
+}
+
+// Handler para o histórico de um serviço: transições de status e amostras agregadas
+func historyHandler(w http.ResponseWriter, r *http.Request) {
+	service, ok := lookupService(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+
+	from, to, resolution, ok := parseHistoryQuery(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":          service.ID,
+		"service":     service.Description,
+		"from":        from,
+		"to":          to,
+		"resolution":  resolution.String(),
+		"transitions": historySpans(service.Description, from, to),
+		"samples":     downsampleHistory(service.Description, from, to, resolution),
+		"annotations": serviceAnnotations(service, from, to),
+	})
+}
@@ -0,0 +1,677 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const wsClientBuffer = 64   // Mensagens aguardando envio por cliente; além disso, o cliente recebe um snapshot completo
+const wsReplayEvents = 1000 // Deltas e mudanças de status guardados para que clientes reconectados com ?since= recuperem o que perderam
+
+const (
+	wsWriteWait      = 10 * time.Second    // Tempo máximo para enviar uma mensagem ao cliente
+	wsPongWait       = 60 * time.Second    // Tempo máximo sem receber nada do cliente (pong ou mensagem)
+	wsPingPeriod     = wsPongWait * 9 / 10 // Intervalo dos pings, menor que wsPongWait
+	wsMaxMessageSize = 4096                // Tamanho máximo das mensagens recebidas (assinaturas)
+)
+
+// Cliente WebSocket registrado no hub
+type wsClient struct {
+	send    chan []byte // Mensagens a enviar; nil pede um snapshot completo e atual
+	filter  *wsFilter   // Serviços assinados pelo cliente (nil = todos), protegido por hub.mu
+	allowed []string    // Grupos que a identidade autenticada pode ver (vazio = todos)
+	kiosk   bool        // Quiosque (/kiosk): a assinatura acompanha a visão alternada pelo servidor
+
+	counts aggregateCounts // Contagens dos serviços assinados (com filtro), atualizadas a cada delta; protegido por hub.mu
+}
+
+// Assinatura enviada pelo cliente: {"type": "subscribe", "groups": [...], "tags": [...], "services": [...]}.
+// O cliente recebe os serviços listados em services (descrição ou ID) e os que atendem a groups e tags.
+type wsFilter struct {
+	Groups   []string `json:"groups"`
+	Tags     []string `json:"tags"`
+	Services []string `json:"services"`
+	allowed  []string // Grupos visíveis para a identidade autenticada, aplicados sobre a assinatura
+}
+
+// Função para limitar a assinatura aos grupos que a identidade pode ver (sem limite, retorna a própria assinatura)
+func restrictFilter(filter *wsFilter, allowed []string) *wsFilter {
+	if len(allowed) == 0 {
+		return filter
+	}
+	restricted := &wsFilter{allowed: allowed}
+	if filter != nil {
+		restricted.Groups, restricted.Tags, restricted.Services = filter.Groups, filter.Tags, filter.Services
+	}
+	return restricted
+}
+
+// Função para verificar se o serviço faz parte da assinatura
+func (f *wsFilter) matches(service Service) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.allowed) > 0 && !slices.Contains(f.allowed, service.Group) {
+		return false
+	}
+	if len(f.Groups) == 0 && len(f.Tags) == 0 && len(f.Services) == 0 {
+		return true
+	}
+	if len(f.Services) > 0 && (slices.Contains(f.Services, service.Description) || slices.Contains(f.Services, strconv.Itoa(service.ID))) {
+		return true
+	}
+	if len(f.Groups) == 0 && len(f.Tags) == 0 {
+		return false
+	}
+	if len(f.Groups) > 0 && !slices.Contains(f.Groups, service.Group) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if hasTag(service, tag) {
+			return true
+		}
+	}
+	return len(f.Tags) == 0
+}
+
+// Serviço serializado uma única vez e repassado aos clientes que o assinam
+type wsEntry struct {
+	service Service
+	data    []byte
+}
+
+// Resumo de um grupo serializado uma única vez, repassado aos clientes que assinam algum serviço do grupo
+type wsGroup struct {
+	services []Service
+	data     []byte
+}
+
+// Delta ou mudança de status publicada, guardada para a retomada
+type wsEvent struct {
+	seq     uint64
+	kind    string    // delta ou transition
+	entries []wsEntry // Na mudança de status, um único item com o serviço e o evento serializado
+	groups  []wsGroup // Resumos dos grupos dos serviços alterados
+}
+
+// Função para montar a mensagem do evento com os serviços que atendem ao filtro (false se nenhum atende) e o
+// estado agregado informado
+func (e wsEvent) message(filter *wsFilter, overall []byte) ([]byte, bool) {
+	if e.kind == "transition" {
+		if !filter.matches(e.entries[0].service) {
+			return nil, false
+		}
+		message := fmt.Appendf(nil, `{"type":"transition","seq":%d,"transition":`, e.seq)
+		return append(append(message, e.entries[0].data...), '}'), true
+	}
+	list, count := joinEntries(e.entries, filter)
+	return wsMessage(e.kind, e.seq, list, joinGroups(e.groups, filter), overall), count > 0
+}
+
+// Hub que distribui o estado dos serviços para todos os clientes WebSocket: cada serviço é serializado uma
+// única vez por publicação, em vez de uma vez por conexão. Cada publicação recebe um número de sequência
+// crescente: {"type": "snapshot", "seq": ..., "services": [...]} com a lista completa ou
+// {"type": "delta", "seq": ..., "services": [...]}, enviado assim que acontece, apenas com os serviços alterados.
+// Ambas trazem em groups o resumo (groupRollup) dos grupos envolvidos: todos no snapshot, os dos serviços
+// alterados no delta; e em overall o estado agregado (aggregateStatus) de todos os serviços do cliente.
+// As mudanças de status publicadas no barramento de eventos seguem a mesma sequência:
+// {"type": "transition", "seq": ..., "transition": {"service": ..., "from": ..., "to": ..., "time": ..., "reason": ...}}.
+type wsHub struct {
+	mu       sync.Mutex
+	clients  map[*wsClient]bool
+	services map[int][]byte // Último estado enviado de cada serviço, indexado pelo ID, para detectar mudanças
+	seq      uint64         // Sequência da última publicação
+	replay   []wsEvent      // Últimos deltas e mudanças de status publicados, em ordem
+	complete uint64         // Retomadas a partir desta sequência encontram todos os deltas seguintes em replay
+
+	// Último estado de cada serviço, indexado pelo ID, e os índices montados a cada publicação, para que um delta
+	// recalcule apenas os resumos dos grupos alterados e as contagens do estado agregado
+	state     map[int]Service
+	order     []int             // IDs dos serviços na ordem do config.ini
+	groups    []string          // Grupos na ordem do config.ini
+	members   map[string][]int  // IDs dos serviços de cada grupo
+	groupData map[string][]byte // Último resumo serializado de cada grupo
+	counts    aggregateCounts   // Contagens de todos os serviços
+	full      []byte            // Lista JSON com todos os serviços, montada sob demanda (nil após cada mudança)
+
+	closing chan struct{} // Fechado ao encerrar o processo, para desconectar os clientes WebSocket e SSE
+}
+
+// A sequência começa no horário de início (em microssegundos, ainda exato em JavaScript), de modo que
+// sequências de uma execução anterior do processo não são confundidas com as atuais
+var hub = newHub(uint64(time.Now().UnixMicro()))
+
+// Função para criar o hub com a sequência inicial informada
+func newHub(seq uint64) *wsHub {
+	return &wsHub{clients: map[*wsClient]bool{}, services: map[int][]byte{}, state: map[int]Service{}, members: map[string][]int{},
+		groupData: map[string][]byte{}, seq: seq, complete: seq, closing: make(chan struct{})}
+}
+
+// Função para desconectar todos os clientes WebSocket e SSE ao encerrar o processo
+func (h *wsHub) shutdown() {
+	close(h.closing)
+}
+
+// Função para registrar um cliente com a assinatura informada (nil = todos os serviços). O cliente recebe
+// primeiro um snapshot completo ou, se since (a última sequência recebida antes de reconectar) ainda estiver
+// coberta pelos eventos guardados, apenas os deltas e mudanças de status publicados depois dela.
+func (h *wsHub) register(filter *wsFilter, since uint64) *wsClient {
+	client := &wsClient{send: make(chan []byte, wsClientBuffer), filter: filter}
+	if filter != nil {
+		client.allowed = filter.allowed
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[client] = true
+	client.counts = h.countsFor(filter)
+	if since == 0 || since < h.complete || since > h.seq {
+		client.send <- nil
+		return client
+	}
+	overall := marshalAggregate(client.counts)
+	for _, event := range h.replay {
+		if event.seq <= since {
+			continue
+		}
+		if message, ok := event.message(filter, overall); ok {
+			h.enqueue(client, message)
+		}
+	}
+	return client
+}
+
+// Função para ler a assinatura dos parâmetros view (visão do config.ini) ou group, tag e service da URL
+// (nil = todos os serviços), limitada aos grupos que a identidade autenticada pode ver
+func queryFilter(r *http.Request) *wsFilter {
+	query := r.URL.Query()
+	var filter *wsFilter
+	if query.Has("view") {
+		filter = viewFilter(query.Get("view"))
+	} else if query.Has("group") || query.Has("tag") || query.Has("service") {
+		filter = &wsFilter{Groups: query["group"], Tags: query["tag"], Services: query["service"]}
+	}
+	return restrictFilter(filter, visibleGroups(r))
+}
+
+// Função para obter a última sequência recebida pelo cliente antes de reconectar: ?since= ou, no SSE, o
+// cabeçalho Last-Event-ID enviado automaticamente pelo EventSource (0 se não informada ou inválida)
+func resumeSeq(r *http.Request) uint64 {
+	value := r.URL.Query().Get("since")
+	if value == "" {
+		value = r.Header.Get("Last-Event-ID")
+	}
+	seq, _ := strconv.ParseUint(value, 10, 64)
+	return seq
+}
+
+// Função para remover um cliente desconectado
+func (h *wsHub) unregister(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, client)
+}
+
+// Função para alterar a assinatura do cliente, que recebe em seguida um snapshot apenas com os serviços assinados
+func (h *wsHub) subscribe(client *wsClient, filter *wsFilter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.setFilter(client, restrictFilter(filter, client.allowed))
+	h.enqueue(client, nil)
+}
+
+// Função para trocar o filtro do cliente e recontar os serviços assinados (chamada com h.mu travado)
+func (h *wsHub) setFilter(client *wsClient, filter *wsFilter) {
+	client.filter = filter
+	client.counts = h.countsFor(filter)
+}
+
+// Função para obter o filtro atual do cliente
+func (h *wsHub) filterOf(client *wsClient) *wsFilter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return client.filter
+}
+
+// Função para enfileirar uma mensagem sem bloquear (chamada com h.mu travado). Um cliente lento demais para
+// acompanhar descarta as mensagens pendentes e recebe um snapshot completo em seguida.
+func (h *wsHub) enqueue(client *wsClient, message []byte) {
+	select {
+	case client.send <- message:
+	default:
+		// O cliente pode ler ao mesmo tempo: a fila é esvaziada sem bloquear, para não travar o hub em h.mu
+		for drained := false; !drained; {
+			select {
+			case <-client.send:
+			default:
+				drained = true
+			}
+		}
+		client.send <- nil
+	}
+}
+
+// Função para montar uma lista JSON com os serviços serializados que atendem ao filtro
+func joinEntries(entries []wsEntry, filter *wsFilter) ([]byte, int) {
+	size := 2
+	if filter == nil {
+		for _, entry := range entries {
+			size += len(entry.data) + 1
+		}
+	}
+	list := make([]byte, 1, size)
+	list[0] = '['
+	count := 0
+	for _, entry := range entries {
+		if !filter.matches(entry.service) {
+			continue
+		}
+		if count > 0 {
+			list = append(list, ',')
+		}
+		list = append(list, entry.data...)
+		count++
+	}
+	return append(list, ']'), count
+}
+
+// Função para montar uma lista JSON com os resumos dos grupos que têm algum serviço que atende ao filtro
+func joinGroups(groups []wsGroup, filter *wsFilter) []byte {
+	var list bytes.Buffer
+	list.WriteByte('[')
+	count := 0
+	for _, group := range groups {
+		if !slices.ContainsFunc(group.services, filter.matches) {
+			continue
+		}
+		if count > 0 {
+			list.WriteByte(',')
+		}
+		list.Write(group.data)
+		count++
+	}
+	list.WriteByte(']')
+	return list.Bytes()
+}
+
+// Função para montar uma mensagem do hub com as listas JSON de serviços e de resumos dos grupos e o estado
+// agregado dos serviços do cliente
+func wsMessage(kind string, seq uint64, list, groups, overall []byte) []byte {
+	message := make([]byte, 0, len(list)+len(groups)+len(overall)+80)
+	message = fmt.Appendf(message, `{"type":%q,"seq":%d,"services":`, kind, seq)
+	message = append(append(message, list...), `,"groups":`...)
+	message = append(append(message, groups...), `,"overall":`...)
+	return append(append(message, overall...), '}')
+}
+
+// Função para obter o tipo de uma mensagem do hub (usado como nome dos eventos SSE)
+func messageType(message []byte) string {
+	_, rest, _ := bytes.Cut(message, []byte(`"type":"`))
+	end := bytes.IndexByte(rest, '"')
+	if end < 0 {
+		return ""
+	}
+	return string(rest[:end])
+}
+
+// Função para obter a sequência de uma mensagem do hub (usada como id dos eventos SSE)
+func messageSeq(message []byte) string {
+	_, rest, _ := bytes.Cut(message, []byte(`"seq":`))
+	end := bytes.IndexByte(rest, ',')
+	if end < 0 {
+		return ""
+	}
+	return string(rest[:end])
+}
+
+// Função para repassar os serviços a todos os clientes (chamada com h.mu travado): a lista completa é montada
+// uma única vez para os clientes sem assinatura; os demais recebem apenas os serviços assinados, e deltas
+// sem nenhum serviço assinado não são enviados
+func (h *wsHub) broadcast(entries []wsEntry, groups []wsGroup, delta bool) {
+	h.seq++
+	kind := "snapshot"
+	if delta {
+		kind = "delta"
+		h.remember(wsEvent{seq: h.seq, kind: kind, entries: entries, groups: groups})
+	}
+	all, _ := joinEntries(entries, nil)
+	shared := wsMessage(kind, h.seq, all, joinGroups(groups, nil), marshalAggregate(h.counts))
+	for client := range h.clients {
+		if client.filter == nil {
+			h.enqueue(client, shared)
+			continue
+		}
+		list, count := joinEntries(entries, client.filter)
+		if delta && count == 0 {
+			continue
+		}
+		h.enqueue(client, wsMessage(kind, h.seq, list, joinGroups(groups, client.filter), marshalAggregate(client.counts)))
+	}
+}
+
+// Função para guardar o evento para a retomada, descartando o mais antigo além de wsReplayEvents (chamada
+// com h.mu travado)
+func (h *wsHub) remember(event wsEvent) {
+	h.replay = append(h.replay, event)
+	if len(h.replay) > wsReplayEvents {
+		h.complete = h.replay[0].seq
+		h.replay = h.replay[1:]
+	}
+}
+
+// Função para serializar cada serviço, fora de h.mu para não atrasar os demais publicadores e clientes
+func marshalEntries(services []Service) []wsEntry {
+	entries := make([]wsEntry, 0, len(services))
+	for _, service := range services {
+		data, err := json.Marshal(service)
+		if err != nil {
+			log.Println(tr("Erro ao serializar o estado do serviço:"), err)
+			continue
+		}
+		entries = append(entries, wsEntry{service: service, data: data})
+	}
+	return entries
+}
+
+// Função para guardar o estado enviado de cada serviço, atualizando as contagens do estado agregado de todos
+// os serviços e de cada cliente com filtro; sem all, apenas os que mudaram são retornados (chamada com h.mu travado)
+func (h *wsHub) store(entries []wsEntry, all bool) []wsEntry {
+	changed := entries[:0]
+	for _, entry := range entries {
+		id := entry.service.ID
+		if !all && bytes.Equal(h.services[id], entry.data) {
+			continue
+		}
+		previous, known := h.state[id]
+		if known {
+			h.counts.add(previous, -1)
+		}
+		h.counts.add(entry.service, 1)
+		for client := range h.clients {
+			if client.filter == nil || !client.filter.matches(entry.service) {
+				continue
+			}
+			if known {
+				client.counts.add(previous, -1)
+			}
+			client.counts.add(entry.service, 1)
+		}
+		h.services[id] = entry.data
+		h.state[id] = entry.service
+		changed = append(changed, entry)
+	}
+	if len(changed) > 0 {
+		h.full = nil
+	}
+	return changed
+}
+
+// Função para serializar os resumos dos grupos informados, calculados com o último estado de cada serviço do
+// grupo e guardados para os snapshots (chamada com h.mu travado)
+func (h *wsHub) rollups(names []string) []wsGroup {
+	groups := make([]wsGroup, 0, len(names))
+	for _, name := range names {
+		members := make([]Service, 0, len(h.members[name]))
+		for _, id := range h.members[name] {
+			members = append(members, h.state[id])
+		}
+		for _, rollup := range groupRollups(members) {
+			rollup.Order = slices.Index(h.groups, name)
+			data, err := json.Marshal(rollup)
+			if err != nil {
+				log.Println(tr("Erro ao serializar o resumo do grupo:"), err)
+				continue
+			}
+			h.groupData[name] = data
+			groups = append(groups, wsGroup{services: members, data: data})
+		}
+	}
+	return groups
+}
+
+// Função para publicar o snapshot completo dos serviços (periodicamente e quando a lista muda). Se a lista
+// mudou (config.ini recarregado), os deltas não bastam para atualizar um cliente, que precisa do snapshot.
+func (h *wsHub) publish(services []Service) {
+	entries := marshalEntries(services)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	previous := h.services
+	h.services = make(map[int][]byte, len(entries))
+	h.state = make(map[int]Service, len(entries))
+	h.counts = aggregateCounts{}
+	for client := range h.clients {
+		client.counts = aggregateCounts{}
+	}
+	entries = h.store(entries, true)
+	changed := len(previous) != len(h.services)
+	for id := range h.services {
+		if _, ok := previous[id]; !ok {
+			changed = true
+		}
+	}
+
+	h.order, h.groups, h.members = make([]int, 0, len(entries)), nil, map[string][]int{}
+	h.groupData = map[string][]byte{}
+	for _, entry := range entries {
+		group := entry.service.Group
+		if _, ok := h.members[group]; !ok {
+			h.groups = append(h.groups, group)
+		}
+		h.order = append(h.order, entry.service.ID)
+		h.members[group] = append(h.members[group], entry.service.ID)
+	}
+	h.broadcast(entries, h.rollups(h.groups), false)
+	if changed {
+		h.complete = h.seq
+		h.replay = nil
+	}
+}
+
+// Função para publicar imediatamente, como delta, os serviços cujo estado mudou desde o último envio
+func (h *wsHub) update(services ...Service) {
+	entries := marshalEntries(services)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if changed := h.store(entries, false); len(changed) > 0 {
+		names := []string{}
+		for _, entry := range changed {
+			if !slices.Contains(names, entry.service.Group) {
+				names = append(names, entry.service.Group)
+			}
+		}
+		slices.SortFunc(names, func(a, b string) int { return slices.Index(h.groups, a) - slices.Index(h.groups, b) })
+		h.broadcast(changed, h.rollups(names), true)
+	}
+}
+
+// Função para repassar a mudança de status (consumidor do barramento de eventos) aos clientes que assinam o serviço
+func (h *wsHub) transition(t statusTransition, service Service) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		log.Println(tr("Erro ao serializar a mudança de status:"), err)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	event := wsEvent{seq: h.seq, kind: "transition", entries: []wsEntry{{service: service, data: data}}}
+	h.remember(event)
+	for client := range h.clients {
+		if message, ok := event.message(client.filter, nil); ok {
+			h.enqueue(client, message)
+		}
+	}
+}
+
+// Função para montar o snapshot enviado a um cliente que acabou de se conectar, mudou a assinatura ou ficou
+// para trás, com os serviços, resumos e contagens guardados na última publicação, sem serializá-los novamente
+func (h *wsHub) snapshot(filter *wsFilter) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if filter == nil {
+		groups := make([][]byte, 0, len(h.groups))
+		for _, name := range h.groups {
+			groups = append(groups, h.groupData[name])
+		}
+		return wsMessage("snapshot", h.seq, h.fullList(), joinJSON(groups), marshalAggregate(h.counts)), nil
+	}
+
+	entries := make([][]byte, 0, len(h.order))
+	matched := map[string]bool{}
+	var counts aggregateCounts
+	for _, id := range h.order {
+		service := h.state[id]
+		if !filter.matches(service) {
+			continue
+		}
+		entries = append(entries, h.services[id])
+		matched[service.Group] = true
+		counts.add(service, 1)
+	}
+	groups := [][]byte{}
+	for _, name := range h.groups {
+		if matched[name] {
+			groups = append(groups, h.groupData[name])
+		}
+	}
+	return wsMessage("snapshot", h.seq, joinJSON(entries), joinJSON(groups), marshalAggregate(counts)), nil
+}
+
+// Função para obter a lista JSON com todos os serviços, na ordem do config.ini, montada uma única vez a cada
+// mudança e compartilhada pelos snapshots sem filtro e pelo /status.json (chamada com h.mu travado)
+func (h *wsHub) fullList() []byte {
+	if h.full == nil {
+		entries := make([][]byte, 0, len(h.order))
+		for _, id := range h.order {
+			entries = append(entries, h.services[id])
+		}
+		h.full = joinJSON(entries)
+	}
+	return h.full
+}
+
+// Função para obter a lista JSON com o estado atual de todos os serviços, igual à serialização de
+// snapshotServices, sem serializar os serviços novamente a cada requisição
+func (h *wsHub) statusJSON() []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.fullList()
+}
+
+// Função para montar uma lista JSON com os itens já serializados
+func joinJSON(items [][]byte) []byte {
+	size := 2
+	for _, item := range items {
+		size += len(item) + 1
+	}
+	list := make([]byte, 1, size)
+	list[0] = '['
+	for i, item := range items {
+		if i > 0 {
+			list = append(list, ',')
+		}
+		list = append(list, item...)
+	}
+	return append(list, ']')
+}
+
+// Função para ler as mensagens do cliente, necessária para processar os pongs: conexões meio abertas
+// (notebooks em suspensão, Wi-Fi instável) deixam de responder e são encerradas após wsPongWait
+func readPump(conn *websocket.Conn, client *wsClient, closed chan struct{}) {
+	defer close(closed)
+	conn.SetReadLimit(wsMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+				log.Println(tr("Conexão WebSocket encerrada:"), err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		// Assinatura de grupos, tags ou serviços; {"type": "subscribe"} sem filtros volta a receber todos
+		var message struct {
+			Type string `json:"type"`
+			wsFilter
+		}
+		if err := json.Unmarshal(data, &message); err != nil || message.Type != "subscribe" {
+			log.Println(tr("Mensagem WebSocket ignorada:"), string(data))
+			continue
+		}
+		var filter *wsFilter
+		if len(message.Groups) > 0 || len(message.Tags) > 0 || len(message.Services) > 0 {
+			filter = &message.wsFilter
+		}
+		hub.subscribe(client, filter)
+	}
+}
+
+// Função para enviar ao cliente as mensagens do hub e os pings, cada envio com prazo de wsWriteWait para
+// não ficar bloqueada em um cliente que parou de ler
+func writePump(conn *websocket.Conn, client *wsClient, closed chan struct{}) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data := <-client.send:
+			if data == nil {
+				var err error
+				if data, err = hub.snapshot(hub.filterOf(client)); err != nil {
+					log.Println(tr("Erro ao serializar o estado dos serviços:"), err)
+					return
+				}
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				log.Println(tr("Erro ao enviar atualizações periódicas:"), err)
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				log.Println(tr("Erro ao enviar ping ao WebSocket:"), err)
+				return
+			}
+		case <-closed:
+			// O WebSocket foi fechado ou deixou de responder aos pings
+			log.Println(tr("Conexão WebSocket fechada."))
+			return
+		case <-hub.closing:
+			// Encerramento do processo: avisa o cliente (1001 Going Away), que reconecta sozinho
+			message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsWriteWait))
+			return
+		}
+	}
+}
+
+// Função para publicar o snapshot completo a cada push_interval, corrigindo clientes que tenham perdido algum
+// delta; o ticker é independente do ciclo de verificações (check_interval) e segue as alterações do config.ini
+func runHub() {
+	interval := getConfig().PushInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if services := snapshotServices(); len(services) > 0 {
+			hub.publish(services)
+		}
+		if current := getConfig().PushInterval; current != interval {
+			interval = current
+			ticker.Reset(interval)
+		}
+	}
+}
@@ -0,0 +1,560 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"sync/atomic"
+)
+
+// Idiomas disponíveis na opção language da seção [general]
+var languages = []string{"en", "pt-BR", "es"}
+
+var currentLanguage atomic.Value // Idioma configurado (vazio = textos originais)
+
+// Catálogos das mensagens dos logs e das notificações, indexados pelo texto original: em português nos logs e
+// em inglês nas notificações. Mensagens sem tradução no idioma configurado são exibidas no texto original.
+var messageCatalogs = map[string]map[string]string{
+	"en": {
+		"%d resultados antigos agregados em intervalos de %s\n":                                         "%d old results aggregated into %s intervals\n",
+		"%d resultados antigos apagados do banco\n":                                                     "%d old results deleted from the database\n",
+		"%d resultados descartados: fila de gravação cheia\n":                                           "%d results dropped: write queue full\n",
+		"%d resultados recarregados do banco\n":                                                         "%d results reloaded from the database\n",
+		"%s inválido na seção [%s], usando a mensagem padrão: %v\n":                                     "invalid %s in the [%s] section, using the default message: %v\n",
+		"%s inválido na seção [ui] (use #rrggbb ou o nome da cor), usando a cor padrão\n":               "invalid %s in the [ui] section (use #rrggbb or a color name), using the default color\n",
+		"%s não gravado no banco: %v\n":                                                                 "%s not saved to the database: %v\n",
+		"%s não gravado no banco: fila de gravação cheia\n":                                             "%s not saved to the database: write queue full\n",
+		"Acesso de %s a %s (%s) recusado pela seção [access]\n":                                         "Access from %s to %s (%s) refused by the [access] section\n",
+		"address_family inválido %q na seção [network] (use %s), usando auto\n":                         "invalid address_family %q in the [network] section (use %s), using auto\n",
+		"after inválido %q na seção [backoff], espaçamento desabilitado\n":                              "invalid after %q in the [backoff] section, backoff disabled\n",
+		"Alta disponibilidade desabilitada: a eleição do líder exige a persistência da seção [storage]": "High availability disabled: leader election requires persistence in the [storage] section",
+		"Alta disponibilidade desabilitada: defina o mesmo instance= da seção [storage] nos dois nós":   "High availability disabled: set the same instance= in the [storage] section on both nodes",
+		"Anotação %s registrada por %s: %s\n":                                                           "Annotation %s recorded by %s: %s\n",
+		"Arquivo config.ini modificado, recarregando configurações...":                                  "config.ini changed, reloading configuration...",
+		"Arquivo removido:":                    "File removed:",
+		"Assumindo a verificação dos serviços": "Taking over service checks",
+		"Backup %s restaurado: %d verificações, %d quedas, %d incidentes, %d anotações, %d silêncios\n": "Backup %s restored: %d checks, %d outages, %d incidents, %d annotations, %d silences\n",
+		"Certificado ACME emitido para":                                                    "ACME certificate issued for",
+		"Certificado TLS recarregado de":                                                   "TLS certificate reloaded from",
+		"Conexão WebSocket encerrada:":                                                     "WebSocket connection terminated:",
+		"Conexão WebSocket fechada.":                                                       "WebSocket connection closed.",
+		"Conexão WebSocket recusada para a origem:":                                        "WebSocket connection refused for origin:",
+		"Conexão com a central perdida (%v), reconectando em %s\n":                         "Connection to the central server lost (%v), reconnecting in %s\n",
+		"Conexão da sonda [%s] encerrada: %v\n":                                            "Agent [%s] connection closed: %v\n",
+		"Configurações recarregadas com sucesso!":                                          "Configuration reloaded successfully!",
+		"Descoberta do Kubernetes desabilitada: informe api_server fora do cluster":        "Kubernetes discovery disabled: set api_server when running outside the cluster",
+		"Descobrindo serviços do Kubernetes em %s (%s, selector %q)\n":                     "Discovering Kubernetes services at %s (%s, selector %q)\n",
+		"Endereço da central inválido (-server):":                                          "Invalid central server address (-server):",
+		"Erro ao abrir o log de auditoria:":                                                "Error opening the audit log:",
+		"Erro ao abrir WebSocket:":                                                         "Error opening WebSocket:",
+		"Erro ao abrir arquivo de log: %v":                                                 "Error opening log file: %v",
+		"Erro ao abrir o banco (%s), persistência desabilitada: %v\n":                      "Error opening the database (%s), persistence disabled: %v\n",
+		"Erro ao agregar resultados antigos do banco:":                                     "Error aggregating old results in the database:",
+		"Erro ao apagar resultados antigos do banco:":                                      "Error deleting old results from the database:",
+		"Erro ao carregar a CA da API do Kubernetes:":                                      "Error loading the Kubernetes API CA:",
+		"Erro ao carregar a CA:":                                                           "Error loading the CA:",
+		"Erro ao carregar embed.html:":                                                     "Error loading embed.html:",
+		"Erro ao carregar index.html:":                                                     "Error loading index.html:",
+		"Erro ao carregar o certificado ACME salvo:":                                       "Error loading the saved ACME certificate:",
+		"Erro ao carregar o certificado da sonda:":                                         "Error loading the agent certificate:",
+		"Erro ao carregar o certificado TLS:":                                              "Error loading the TLS certificate:",
+		"Erro ao compactar o banco:":                                                       "Error compacting the database:",
+		"Erro ao converter check_interval, usando valor padrão de 10 segundos":             "Invalid check_interval, using the default of 10 seconds",
+		"Erro ao converter push_interval, usando valor padrão de 1 minuto":                 "Invalid push_interval, using the default of 1 minute",
+		"Erro ao converter response_time, usando valor padrão de 10 segundos":              "Invalid response_time, using the default of 10 seconds",
+		"Erro ao converter session_ttl, usando valor padrão de 12 horas":                   "Error parsing session_ttl, using the default of 12 hours",
+		"Erro ao converter timeout, usando valor padrão de 1 segundo":                      "Invalid timeout, using the default of 1 second",
+		"Erro ao criar diretório de logs: %v":                                              "Error creating the log directory: %v",
+		"Erro ao emitir o certificado ACME:":                                               "Error issuing the ACME certificate:",
+		"Erro ao encerrar o servidor %s: %v\n":                                             "Error shutting down server %s: %v\n",
+		"Erro ao enviar %d pontos ao banco de séries temporais: %v\n":                      "Error sending %d points to the time series database: %v\n",
+		"Erro ao enviar atualizações periódicas:":                                          "Error sending updates:",
+		"Erro ao enviar eventos SSE:":                                                      "Error sending SSE events:",
+		"Erro ao enviar notificação (%s) do serviço [%s]: %v\n":                            "Error sending notification (%s) for service [%s]: %v\n",
+		"Erro ao enviar o relatório [%s]: %v\n":                                            "Error sending report [%s]: %v\n",
+		"Erro ao enviar ping ao WebSocket:":                                                "Error sending WebSocket ping:",
+		"Erro ao enviar resposta JSON:":                                                    "Error sending JSON response:",
+		"Erro ao enviar status JSON:":                                                      "Error sending JSON status:",
+		"Erro ao exportar dados:":                                                          "Error exporting data:",
+		"Erro ao fechar o banco:":                                                          "Error closing the database:",
+		"Erro ao gerar feed:":                                                              "Error generating feed:",
+		"Erro ao gerar o backup:":                                                          "Error generating the backup:",
+		"Erro ao gerar o dashboard:":                                                       "Error generating the dashboard:",
+		"Erro ao gerar o relatório de SLA:":                                                "Error generating the SLA report:",
+		"Erro ao gerar o widget:":                                                          "Error generating the widget:",
+		"Erro ao gravar %d resultados no banco: %v\n":                                      "Error saving %d results to the database: %v\n",
+		"Erro ao gravar o backup:":                                                         "Error writing the backup:",
+		"Erro ao gravar o log de auditoria:":                                               "Error writing the audit log:",
+		"Erro ao ler diretório de logs:":                                                   "Error reading the log directory:",
+		"Erro ao ler message_template_file da seção [%s], usando a mensagem padrão: %v\n":  "Error reading message_template_file in the [%s] section, using the default message: %v\n",
+		"Erro ao liberar a liderança:":                                                     "Error releasing leadership:",
+		"Erro ao montar schema GraphQL:":                                                   "Error building the GraphQL schema:",
+		"Erro ao obter informações do arquivo:":                                            "Error reading file information:",
+		"Erro ao recarregar arquivo de configuração: %v":                                   "Error reloading the configuration file: %v",
+		"Erro ao recarregar as anotações do banco:":                                        "Error reloading annotations from the database:",
+		"Erro ao recarregar as quedas do banco:":                                           "Error reloading outages from the database:",
+		"Erro ao recarregar o estado de alerta do banco:":                                  "Error reloading alert state from the database:",
+		"Erro ao recarregar o histórico do banco:":                                         "Error reloading history from the database:",
+		"Erro ao recarregar os incidentes do banco:":                                       "Error reloading incidents from the database:",
+		"Erro ao recarregar os tokens de API do banco:":                                    "Error reloading the API tokens from the database:",
+		"Erro ao remover arquivo:":                                                         "Error removing file:",
+		"Erro ao renovar a liderança:":                                                     "Error renewing leadership:",
+		"Erro ao restaurar o backup:":                                                      "Error restoring the backup:",
+		"Erro ao salvar o certificado ACME:":                                               "Error saving the ACME certificate:",
+		"Erro ao serializar a mudança de status:":                                          "Error encoding the status change:",
+		"Erro ao serializar o estado do serviço:":                                          "Error encoding the service state:",
+		"Erro ao serializar o estado dos serviços:":                                        "Error encoding the services state:",
+		"Erro ao serializar o resumo do grupo:":                                            "Error encoding the group summary:",
+		"Erro ao trocar o código de autorização:":                                          "Error exchanging the authorization code:",
+		"Erro ao verificar arquivo de configuração:":                                       "Error checking the configuration file:",
+		"Erro na descoberta do Kubernetes (namespace %q): %v\n":                            "Kubernetes discovery error (namespace %q): %v\n",
+		"Erro na porta das sondas:":                                                        "Error on the agent port:",
+		"Erro no acme_dns_hook cleanup %s: %v\n":                                           "Error in acme_dns_hook cleanup %s: %v\n",
+		"Erro no redirecionamento HTTP:":                                                   "HTTP redirect error:",
+		"Erro no servidor de debug:":                                                       "Debug server error:",
+		"Erro no SSO:":                                                                     "SSO error:",
+		"Erro no template %s, usando a mensagem padrão: %v\n":                              "Error in template %s, using the default message: %v\n",
+		"Etapa de escalonamento inválida %q no grupo [%s], ignorada\n":                     "Invalid escalation step %q in group [%s], ignored\n",
+		"factor inválido na seção [backoff] (deve ser maior que 1), usando 2":              "invalid factor in the [backoff] section (must be greater than 1), using 2",
+		"fallback_delay inválido na seção [network], usando 300ms":                         "invalid fallback_delay in the [network] section, using 300ms",
+		"fallback_ttl inválido na seção [dns], usando 30s":                                 "invalid fallback_ttl in the [dns] section, using 30s",
+		"flush_interval inválido na seção [tsdb], usando 10s":                              "invalid flush_interval in the [tsdb] section, using 10s",
+		"font_scale inválido na seção [ui] (use 0.5 a 3), usando 1":                        "invalid font_scale in the [ui] section (use 0.5 to 3), using 1",
+		"ID token inválido:":                                                               "Invalid ID token:",
+		"Incidente %s aberto por %s: %s\n":                                                 "Incident %s opened by %s: %s\n",
+		"Incidente %s atualizado por %s: %s\n":                                             "Incident %s updated by %s: %s\n",
+		"interval inválido na seção [kiosk] (mínimo 5s), usando 30s":                       "invalid interval in the [kiosk] section (minimum 5s), using 30s",
+		"Janela %q inválida em latency_windows, ignorada\n":                                "Invalid window %q in latency_windows, ignored\n",
+		"jitter inválido %q (use 0%% a 50%% ou uma duração), usando 10%%\n":                "invalid jitter %q (use 0%% to 50%% or a duration), using 10%%\n",
+		"lease inválido na seção [ha] (mínimo %s com o timeout atual), usando %s\n":        "invalid lease in the [ha] section (minimum %s with the current timeout), using %s\n",
+		"Liderança não renovada, deixando de verificar os serviços":                        "Leadership not renewed, no longer checking services",
+		"Limite de clientes WebSocket atingido, conexão recusada":                          "WebSocket client limit reached, connection refused",
+		"Login LDAP de %s recusado: %v\n":                                                  "LDAP login for %s refused: %v\n",
+		"Login SSO de %s (%s)\n":                                                           "SSO login for %s (%s)\n",
+		"Login SSO de %s recusado: nenhum grupo autorizado\n":                              "SSO login for %s refused: no authorized group\n",
+		"match_name inválido na seção [%s], filtro por nome ignorado: %v\n":                "invalid match_name in the [%s] section, name filter ignored: %v\n",
+		"max_interval inválido na seção [backoff], usando 10m":                             "invalid max_interval in the [backoff] section, using 10m",
+		"max_stale inválido na seção [dns], usando 1m":                                     "invalid max_stale in the [dns] section, using 1m",
+		"max_ttl inválido na seção [dns], usando 5m":                                       "invalid max_ttl in the [dns] section, using 5m",
+		"Mensagem WebSocket ignorada:":                                                     "WebSocket message ignored:",
+		"Mensagem da sonda [%s] ignorada: %s %q\n":                                         "Message from agent [%s] ignored: %s %q\n",
+		"min_ttl inválido na seção [dns], usando 5s":                                       "invalid min_ttl in the [dns] section, using 5s",
+		"Monitor encerrado.":                                                               "Monitor stopped.",
+		"Monitoramento do grupo [%s] pausado":                                              "Monitoring of group [%s] paused",
+		"Monitoramento do grupo [%s] retomado":                                             "Monitoring of group [%s] resumed",
+		"Monitoramento do serviço [%s] pausado":                                            "Monitoring of service [%s] paused",
+		"Monitoramento do serviço [%s] retomado":                                           "Monitoring of service [%s] resumed",
+		"Nome de visão inválido [%s], ignorada\n":                                          "Invalid view name [%s], ignored\n",
+		"Notificação (%s) do serviço [%s] não enviada: fora do horário do canal\n":         "Notification (%s) for service [%s] not sent: outside the channel schedule\n",
+		"Notificação do serviço [%s] (%s) suprimida por um silêncio ativo\n":               "Notification for service [%s] (%s) suppressed by an active silence\n",
+		"Nó %s assumiu a liderança\n":                                                      "Node %s took over leadership\n",
+		"Nó %s assumiu a liderança, deixando de verificar os serviços\n":                   "Node %s took over leadership, no longer checking services\n",
+		"options inválido na seção [kubernetes] (%v), ignorado\n":                          "invalid options in the [kubernetes] section (%v), ignored\n",
+		"Papel do usuário [%s] ignorado: %q não é viewer, operator ou admin\n":             "Role of user [%s] ignored: %q is not viewer, operator or admin\n",
+		"period inválido na seção [%s], relatório desabilitado\n":                          "invalid period in the [%s] section, report disabled\n",
+		"Persistência desabilitada ([storage]): o backup conterá apenas estruturas vazias": "Persistence disabled ([storage]): the backup will contain only empty structures",
+		"Porta das sondas (mTLS) iniciada em %s\n":                                         "Agent port (mTLS) started on %s\n",
+		"Porta das sondas desabilitada, erro ao carregar a CA:":                            "Agent port disabled, error loading the CA:",
+		"Porta das sondas desabilitada, erro ao carregar o certificado:":                   "Agent port disabled, error loading the certificate:",
+		"Primeira verificação de %d serviço(s) concluída\n":                                "First check of %d service(s) completed\n",
+		"proxy inválido na seção [network] (%v), conectando diretamente\n":                 "invalid proxy in the [network] section (%v), connecting directly\n",
+		"Push recebido para o serviço [%s]: %s":                                            "Push received for service [%s]: %s",
+		"Queda do serviço [%s] reconhecida por %s\n":                                       "Outage of service [%s] acknowledged by %s\n",
+		"Reconhecimento do serviço [%s] desfeito por %s\n":                                 "Acknowledgment of service [%s] undone by %s\n",
+		"Rede %q ignorada na seção [access]: %v\n":                                         "Network %q ignored in the [access] section: %v\n",
+		"Redirecionamento HTTP → HTTPS na porta :%s\n":                                     "HTTP → HTTPS redirect on port :%s\n",
+		"Relatório [%s] enviado\n":                                                         "Report [%s] sent\n",
+		"Resultado do serviço [%s] descartado: fila de envio à central cheia\n":            "Result of service [%s] dropped: queue to the central server is full\n",
+		"Resultados das verificações gravados em %s (retenção de %s)\n":                    "Check results saved to %s (retention %s)\n",
+		"schedule inválido na seção [%s], canal acionado a qualquer hora: %v\n":            "invalid schedule in the [%s] section, channel notified at any time: %v\n",
+		"schedule inválido na seção [%s], relatório desabilitado: %v\n":                    "invalid schedule in the [%s] section, report disabled: %v\n",
+		"Servidor HTTPS iniciado na porta :%s\n":                                           "HTTPS server started on port :%s\n",
+		"Serviço [%s] fora do ar há %s: verificações espaçadas até %s\n":                   "Service [%s] down for %s: backing off checks up to %s\n",
+		"Serviço [%s] voltou a ser verificado a cada %s\n":                                 "Service [%s] is checked every %s again\n",
+		"Serviços descobertos no Kubernetes alterados, recarregando...":                    "Services discovered in Kubernetes changed, reloading...",
+		"Silêncio %s criado por %s até %s\n":                                               "Silence %s created by %s until %s\n",
+		"Silêncio %s encerrado por %s\n":                                                   "Silence %s ended by %s\n",
+		"Sinal de encerramento recebido, finalizando...":                                   "Shutdown signal received, stopping...",
+		"Sonda [%s] conectada de %s\n":                                                     "Agent [%s] connected from %s\n",
+		"Sonda [%s] desconectada\n":                                                        "Agent [%s] disconnected\n",
+		"Sonda conectada à central %s\n":                                                   "Agent connected to the central server %s\n",
+		"Sonda recebeu %d serviço(s) da central\n":                                         "Agent received %d service(s) from the central server\n",
+		"sparkline_samples inválido (use 0 a %d), usando 30\n":                             "invalid sparkline_samples (use 0 to %d), using 30\n",
+		"timezone inválido na seção [%s], usando o fuso do servidor: %v\n":                 "invalid timezone in the [%s] section, using the server time zone: %v\n",
+		"Token [%s] (%s) emitido por %s\n":                                                 "Token [%s] (%s) issued by %s\n",
+		"Token [%s] ignorado: escopo inválido %q\n":                                        "Token [%s] ignored: invalid scope %q\n",
+		"Token [%s] ignorado: valor não informado\n":                                       "Token [%s] ignored: no value set\n",
+		"Token [%s] mantido apenas na memória: sem a persistência da seção [storage], será perdido ao reiniciar\n": "Token [%s] kept only in memory: without the [storage] persistence, it will be lost on restart\n",
+		"Token [%s] revogado por %s\n": "Token [%s] revoked by %s\n",
+		"Verificação do serviço [%s] levou %s, acima do intervalo de %s (aumente workers ou o intervalo)\n": "Check of service [%s] took %s, longer than its %s interval (increase workers or the interval)\n",
+		"Verificação do serviço [%s] voltou a caber no intervalo de %s\n":                                   "Check of service [%s] fits its %s interval again\n",
+		"Página de status pública habilitada sem serviços na seção [public.names]":                          "Public status page enabled without services in the [public.names] section",
+		"Página de status pública iniciada em %s\n":                                                         "Public status page started on %s\n",
+		"Erro no servidor da página de status pública:":                                                     "Public status page server error:",
+		"Erro ao carregar public.html:":                                                                     "Error loading public.html:",
+		"Erro ao gerar a página de status:":                                                                 "Error rendering the status page:",
+		"Erro ao serializar a visão do quiosque:":                                                           "Error serializing the kiosk view:",
+		"Erro ao serializar o estado agregado:":                                                             "Error serializing the aggregate status:",
+		"Servidor de debug iniciado em %s\n":                                                                "Debug server started on %s\n",
+		"Servidor iniciado na porta :%s\n":                                                                  "Server started on port :%s\n",
+		"Serviço [%s] ignorado: %v":                                                                         "Service [%s] ignored: %v",
+		"Serviço [%s] mudou de %s para %s: %s\n":                                                            "Service [%s] changed from %s to %s: %s\n",
+		"Streaming SSE não suportado:":                                                                      "SSE streaming not supported:",
+		"downsample_after inválido na seção [storage], agregação desabilitada":                              "Invalid downsample_after in [storage], aggregation disabled",
+		"downsample_resolution inválido na seção [storage], usando 5m":                                      "Invalid downsample_resolution in [storage], using 5m",
+		"history_retention inválido na seção [storage], usando 90d":                                         "Invalid history_retention in [storage], using 90d",
+		"renotify_every inválido %q, repetição desabilitada\n":                                              "Invalid renotify_every %q, repeat disabled\n",
+		"restore inválido na seção [storage], usando 30d":                                                   "Invalid restore in [storage], using 30d",
+		"Webhook [%s] desabilitado, erro ao ler template_file: %v\n":                                        "Webhook [%s] disabled, error reading template_file: %v\n",
+		"Webhook [%s] desabilitado, template inválido: %v\n":                                                "Webhook [%s] disabled, invalid template: %v\n",
+		"workers inválido (mínimo 1), usando 10":                                                            "invalid workers (minimum 1), using 10",
+		"ws_compression inválido na seção [server] (use 0 a 9), usando 1":                                   "Invalid ws_compression in [server] (use 0 to 9), using 1",
+	},
+	"pt-BR": {
+		"[DOWN] %s is still offline":                          "[FORA] %s continua offline",
+		"[DOWN] %s is offline":                                "[FORA] %s está offline",
+		"[UP] %s is back online":                              "[OK] %s voltou a ficar online",
+		"Offline for %s (first failure at %s)":                "Offline há %s (primeira falha em %s)",
+		"Offline since the first check (first failure at %s)": "Offline desde a primeira verificação (primeira falha em %s)",
+		"Was offline for %s (first failure at %s)":            "Ficou offline por %s (primeira falha em %s)",
+		"Was online for %s":                                   "Ficou online por %s",
+		"Service":                                             "Serviço",
+		"Group":                                               "Grupo",
+		"Address":                                             "Endereço",
+		"Status":                                              "Status",
+		"Response time":                                       "Tempo de resposta",
+		"Message":                                             "Mensagem",
+		"Time":                                                "Horário",
+		"Open dashboard":                                      "Abrir o dashboard",
+		"Checked every %s · full refresh every %s":   "Verificado a cada %s · atualização completa a cada %s",
+		"monitoring paused":                          "monitoramento pausado",
+		"connection established":                     "conexão estabelecida",
+		"name resolved":                              "nome resolvido",
+		"agent %s has never connected":               "a sonda %s nunca se conectou",
+		"agent %s disconnected since %s":             "sonda %s desconectada desde %s",
+		"waiting for the first result from agent %s": "aguardando o primeiro resultado da sonda %s",
+		"no location has reported a result":          "nenhum local enviou resultado",
+		"down only from %s (quorum %d of %d)":        "fora do ar apenas em %s (quorum %d de %d)",
+	},
+	"es": {
+		"%d resultados antigos agregados em intervalos de %s\n":                                         "%d resultados antiguos agregados en intervalos de %s\n",
+		"%d resultados antigos apagados do banco\n":                                                     "%d resultados antiguos eliminados de la base de datos\n",
+		"%d resultados descartados: fila de gravação cheia\n":                                           "%d resultados descartados: cola de escritura llena\n",
+		"%d resultados recarregados do banco\n":                                                         "%d resultados recargados de la base de datos\n",
+		"%s inválido na seção [%s], usando a mensagem padrão: %v\n":                                     "%s inválido en la sección [%s], usando el mensaje predeterminado: %v\n",
+		"%s inválido na seção [ui] (use #rrggbb ou o nome da cor), usando a cor padrão\n":               "%s inválido en la sección [ui] (use #rrggbb o el nombre del color), usando el color predeterminado\n",
+		"%s não gravado no banco: %v\n":                                                                 "%s no guardado en la base de datos: %v\n",
+		"%s não gravado no banco: fila de gravação cheia\n":                                             "%s no guardado en la base de datos: cola de escritura llena\n",
+		"Acesso de %s a %s (%s) recusado pela seção [access]\n":                                         "Acceso de %s a %s (%s) rechazado por la sección [access]\n",
+		"address_family inválido %q na seção [network] (use %s), usando auto\n":                         "address_family inválido %q en la sección [network] (use %s), usando auto\n",
+		"after inválido %q na seção [backoff], espaçamento desabilitado\n":                              "after inválido %q en la sección [backoff], espaciado deshabilitado\n",
+		"Alta disponibilidade desabilitada: a eleição do líder exige a persistência da seção [storage]": "Alta disponibilidad deshabilitada: la elección del líder requiere la persistencia de la sección [storage]",
+		"Alta disponibilidade desabilitada: defina o mesmo instance= da seção [storage] nos dois nós":   "Alta disponibilidad deshabilitada: defina el mismo instance= de la sección [storage] en los dos nodos",
+		"Anotação %s registrada por %s: %s\n":                                                           "Anotación %s registrada por %s: %s\n",
+		"Arquivo config.ini modificado, recarregando configurações...":                                  "config.ini modificado, recargando la configuración...",
+		"Arquivo removido:":                    "Archivo eliminado:",
+		"Assumindo a verificação dos serviços": "Asumiendo la verificación de los servicios",
+		"Backup %s restaurado: %d verificações, %d quedas, %d incidentes, %d anotações, %d silêncios\n": "Copia de seguridad %s restaurada: %d verificaciones, %d caídas, %d incidentes, %d anotaciones, %d silencios\n",
+		"Certificado ACME emitido para":                                                    "Certificado ACME emitido para",
+		"Certificado TLS recarregado de":                                                   "Certificado TLS recargado de",
+		"Conexão WebSocket encerrada:":                                                     "Conexión WebSocket terminada:",
+		"Conexão WebSocket fechada.":                                                       "Conexión WebSocket cerrada.",
+		"Conexão WebSocket recusada para a origem:":                                        "Conexión WebSocket rechazada para el origen:",
+		"Conexão com a central perdida (%v), reconectando em %s\n":                         "Conexión con la central perdida (%v), reconectando en %s\n",
+		"Conexão da sonda [%s] encerrada: %v\n":                                            "Conexión de la sonda [%s] cerrada: %v\n",
+		"Configurações recarregadas com sucesso!":                                          "¡Configuración recargada con éxito!",
+		"Descoberta do Kubernetes desabilitada: informe api_server fora do cluster":        "Descubrimiento de Kubernetes deshabilitado: informe api_server fuera del clúster",
+		"Descobrindo serviços do Kubernetes em %s (%s, selector %q)\n":                     "Descubriendo servicios de Kubernetes en %s (%s, selector %q)\n",
+		"Endereço da central inválido (-server):":                                          "Dirección de la central inválida (-server):",
+		"Erro ao abrir o log de auditoria:":                                                "Error al abrir el registro de auditoría:",
+		"Erro ao abrir WebSocket:":                                                         "Error al abrir el WebSocket:",
+		"Erro ao abrir arquivo de log: %v":                                                 "Error al abrir el archivo de log: %v",
+		"Erro ao abrir o banco (%s), persistência desabilitada: %v\n":                      "Error al abrir la base de datos (%s), persistencia deshabilitada: %v\n",
+		"Erro ao agregar resultados antigos do banco:":                                     "Error al agregar resultados antiguos de la base de datos:",
+		"Erro ao apagar resultados antigos do banco:":                                      "Error al eliminar resultados antiguos de la base de datos:",
+		"Erro ao carregar a CA da API do Kubernetes:":                                      "Error al cargar la CA de la API de Kubernetes:",
+		"Erro ao carregar a CA:":                                                           "Error al cargar la CA:",
+		"Erro ao carregar embed.html:":                                                     "Error al cargar embed.html:",
+		"Erro ao carregar index.html:":                                                     "Error al cargar index.html:",
+		"Erro ao carregar o certificado ACME salvo:":                                       "Error al cargar el certificado ACME guardado:",
+		"Erro ao carregar o certificado da sonda:":                                         "Error al cargar el certificado de la sonda:",
+		"Erro ao carregar o certificado TLS:":                                              "Error al cargar el certificado TLS:",
+		"Erro ao compactar o banco:":                                                       "Error al compactar la base de datos:",
+		"Erro ao converter check_interval, usando valor padrão de 10 segundos":             "check_interval inválido, usando el valor por defecto de 10 segundos",
+		"Erro ao converter push_interval, usando valor padrão de 1 minuto":                 "push_interval inválido, usando el valor por defecto de 1 minuto",
+		"Erro ao converter response_time, usando valor padrão de 10 segundos":              "response_time inválido, usando el valor por defecto de 10 segundos",
+		"Erro ao converter session_ttl, usando valor padrão de 12 horas":                   "Error al convertir session_ttl, usando el valor predeterminado de 12 horas",
+		"Erro ao converter timeout, usando valor padrão de 1 segundo":                      "timeout inválido, usando el valor por defecto de 1 segundo",
+		"Erro ao criar diretório de logs: %v":                                              "Error al crear el directorio de logs: %v",
+		"Erro ao emitir o certificado ACME:":                                               "Error al emitir el certificado ACME:",
+		"Erro ao encerrar o servidor %s: %v\n":                                             "Error al detener el servidor %s: %v\n",
+		"Erro ao enviar %d pontos ao banco de séries temporais: %v\n":                      "Error al enviar %d puntos a la base de series temporales: %v\n",
+		"Erro ao enviar atualizações periódicas:":                                          "Error al enviar actualizaciones:",
+		"Erro ao enviar eventos SSE:":                                                      "Error al enviar eventos SSE:",
+		"Erro ao enviar notificação (%s) do serviço [%s]: %v\n":                            "Error al enviar la notificación (%s) del servicio [%s]: %v\n",
+		"Erro ao enviar o relatório [%s]: %v\n":                                            "Error al enviar el informe [%s]: %v\n",
+		"Erro ao enviar ping ao WebSocket:":                                                "Error al enviar ping al WebSocket:",
+		"Erro ao enviar resposta JSON:":                                                    "Error al enviar la respuesta JSON:",
+		"Erro ao enviar status JSON:":                                                      "Error al enviar el estado JSON:",
+		"Erro ao exportar dados:":                                                          "Error al exportar datos:",
+		"Erro ao fechar o banco:":                                                          "Error al cerrar la base de datos:",
+		"Erro ao gerar feed:":                                                              "Error al generar el feed:",
+		"Erro ao gerar o backup:":                                                          "Error al generar la copia de seguridad:",
+		"Erro ao gerar o dashboard:":                                                       "Error al generar el dashboard:",
+		"Erro ao gerar o relatório de SLA:":                                                "Error al generar el informe de SLA:",
+		"Erro ao gerar o widget:":                                                          "Error al generar el widget:",
+		"Erro ao gravar %d resultados no banco: %v\n":                                      "Error al guardar %d resultados en la base de datos: %v\n",
+		"Erro ao gravar o backup:":                                                         "Error al grabar la copia de seguridad:",
+		"Erro ao gravar o log de auditoria:":                                               "Error al escribir el registro de auditoría:",
+		"Erro ao ler diretório de logs:":                                                   "Error al leer el directorio de logs:",
+		"Erro ao ler message_template_file da seção [%s], usando a mensagem padrão: %v\n":  "Error al leer message_template_file de la sección [%s], usando el mensaje predeterminado: %v\n",
+		"Erro ao liberar a liderança:":                                                     "Error al liberar el liderazgo:",
+		"Erro ao montar schema GraphQL:":                                                   "Error al construir el schema GraphQL:",
+		"Erro ao obter informações do arquivo:":                                            "Error al obtener información del archivo:",
+		"Erro ao recarregar arquivo de configuração: %v":                                   "Error al recargar el archivo de configuración: %v",
+		"Erro ao recarregar as anotações do banco:":                                        "Error al recargar las anotaciones de la base de datos:",
+		"Erro ao recarregar as quedas do banco:":                                           "Error al recargar las caídas de la base de datos:",
+		"Erro ao recarregar o estado de alerta do banco:":                                  "Error al recargar el estado de alerta de la base de datos:",
+		"Erro ao recarregar o histórico do banco:":                                         "Error al recargar el historial de la base de datos:",
+		"Erro ao recarregar os incidentes do banco:":                                       "Error al recargar los incidentes de la base de datos:",
+		"Erro ao recarregar os tokens de API do banco:":                                    "Error al recargar los tokens de API de la base de datos:",
+		"Erro ao remover arquivo:":                                                         "Error al eliminar el archivo:",
+		"Erro ao renovar a liderança:":                                                     "Error al renovar el liderazgo:",
+		"Erro ao restaurar o backup:":                                                      "Error al restaurar la copia de seguridad:",
+		"Erro ao salvar o certificado ACME:":                                               "Error al guardar el certificado ACME:",
+		"Erro ao serializar a mudança de status:":                                          "Error al serializar el cambio de estado:",
+		"Erro ao serializar o estado do serviço:":                                          "Error al serializar el estado del servicio:",
+		"Erro ao serializar o estado dos serviços:":                                        "Error al serializar el estado de los servicios:",
+		"Erro ao serializar o resumo do grupo:":                                            "Error al serializar el resumen del grupo:",
+		"Erro ao trocar o código de autorização:":                                          "Error al canjear el código de autorización:",
+		"Erro ao verificar arquivo de configuração:":                                       "Error al verificar el archivo de configuración:",
+		"Erro na descoberta do Kubernetes (namespace %q): %v\n":                            "Error en el descubrimiento de Kubernetes (namespace %q): %v\n",
+		"Erro na porta das sondas:":                                                        "Error en el puerto de las sondas:",
+		"Erro no acme_dns_hook cleanup %s: %v\n":                                           "Error en acme_dns_hook cleanup %s: %v\n",
+		"Erro no redirecionamento HTTP:":                                                   "Error en la redirección HTTP:",
+		"Erro no servidor de debug:":                                                       "Error en el servidor de debug:",
+		"Erro no SSO:":                                                                     "Error en el SSO:",
+		"Erro no template %s, usando a mensagem padrão: %v\n":                              "Error en la plantilla %s, usando el mensaje predeterminado: %v\n",
+		"Etapa de escalonamento inválida %q no grupo [%s], ignorada\n":                     "Etapa de escalamiento inválida %q en el grupo [%s], ignorada\n",
+		"factor inválido na seção [backoff] (deve ser maior que 1), usando 2":              "factor inválido en la sección [backoff] (debe ser mayor que 1), usando 2",
+		"fallback_delay inválido na seção [network], usando 300ms":                         "fallback_delay inválido en la sección [network], usando 300ms",
+		"fallback_ttl inválido na seção [dns], usando 30s":                                 "fallback_ttl inválido en la sección [dns], usando 30s",
+		"flush_interval inválido na seção [tsdb], usando 10s":                              "flush_interval inválido en la sección [tsdb], usando 10s",
+		"font_scale inválido na seção [ui] (use 0.5 a 3), usando 1":                        "font_scale inválido en la sección [ui] (use 0.5 a 3), usando 1",
+		"ID token inválido:":                                                               "ID token inválido:",
+		"Incidente %s aberto por %s: %s\n":                                                 "Incidente %s abierto por %s: %s\n",
+		"Incidente %s atualizado por %s: %s\n":                                             "Incidente %s actualizado por %s: %s\n",
+		"interval inválido na seção [kiosk] (mínimo 5s), usando 30s":                       "interval inválido en la sección [kiosk] (mínimo 5s), usando 30s",
+		"Janela %q inválida em latency_windows, ignorada\n":                                "Ventana %q inválida en latency_windows, ignorada\n",
+		"jitter inválido %q (use 0%% a 50%% ou uma duração), usando 10%%\n":                "jitter inválido %q (use 0%% a 50%% o una duración), usando 10%%\n",
+		"lease inválido na seção [ha] (mínimo %s com o timeout atual), usando %s\n":        "lease inválido en la sección [ha] (mínimo %s con el timeout actual), usando %s\n",
+		"Liderança não renovada, deixando de verificar os serviços":                        "Liderazgo no renovado, se dejan de verificar los servicios",
+		"Limite de clientes WebSocket atingido, conexão recusada":                          "Límite de clientes WebSocket alcanzado, conexión rechazada",
+		"Login LDAP de %s recusado: %v\n":                                                  "Inicio de sesión LDAP de %s rechazado: %v\n",
+		"Login SSO de %s (%s)\n":                                                           "Inicio de sesión SSO de %s (%s)\n",
+		"Login SSO de %s recusado: nenhum grupo autorizado\n":                              "Inicio de sesión SSO de %s rechazado: ningún grupo autorizado\n",
+		"match_name inválido na seção [%s], filtro por nome ignorado: %v\n":                "match_name inválido en la sección [%s], filtro por nombre ignorado: %v\n",
+		"max_interval inválido na seção [backoff], usando 10m":                             "max_interval inválido en la sección [backoff], usando 10m",
+		"max_stale inválido na seção [dns], usando 1m":                                     "max_stale inválido en la sección [dns], usando 1m",
+		"max_ttl inválido na seção [dns], usando 5m":                                       "max_ttl inválido en la sección [dns], usando 5m",
+		"Mensagem WebSocket ignorada:":                                                     "Mensaje WebSocket ignorado:",
+		"Mensagem da sonda [%s] ignorada: %s %q\n":                                         "Mensaje de la sonda [%s] ignorado: %s %q\n",
+		"min_ttl inválido na seção [dns], usando 5s":                                       "min_ttl inválido en la sección [dns], usando 5s",
+		"Monitor encerrado.":                                                               "Monitor detenido.",
+		"Monitoramento do grupo [%s] pausado":                                              "Monitoreo del grupo [%s] pausado",
+		"Monitoramento do grupo [%s] retomado":                                             "Monitoreo del grupo [%s] reanudado",
+		"Monitoramento do serviço [%s] pausado":                                            "Monitoreo del servicio [%s] pausado",
+		"Monitoramento do serviço [%s] retomado":                                           "Monitoreo del servicio [%s] reanudado",
+		"Nome de visão inválido [%s], ignorada\n":                                          "Nombre de vista inválido [%s], ignorada\n",
+		"Notificação (%s) do serviço [%s] não enviada: fora do horário do canal\n":         "Notificación (%s) del servicio [%s] no enviada: fuera del horario del canal\n",
+		"Notificação do serviço [%s] (%s) suprimida por um silêncio ativo\n":               "Notificación del servicio [%s] (%s) suprimida por un silencio activo\n",
+		"Nó %s assumiu a liderança\n":                                                      "El nodo %s asumió el liderazgo\n",
+		"Nó %s assumiu a liderança, deixando de verificar os serviços\n":                   "El nodo %s asumió el liderazgo, se dejan de verificar los servicios\n",
+		"Offline since the first check (first failure at %s)":                              "Fuera de línea desde la primera verificación (primera falla a las %s)",
+		"options inválido na seção [kubernetes] (%v), ignorado\n":                          "options inválido en la sección [kubernetes] (%v), ignorado\n",
+		"Papel do usuário [%s] ignorado: %q não é viewer, operator ou admin\n":             "Rol del usuario [%s] ignorado: %q no es viewer, operator ni admin\n",
+		"period inválido na seção [%s], relatório desabilitado\n":                          "period inválido en la sección [%s], informe deshabilitado\n",
+		"Persistência desabilitada ([storage]): o backup conterá apenas estruturas vazias": "Persistencia deshabilitada ([storage]): la copia de seguridad solo contendrá estructuras vacías",
+		"Porta das sondas (mTLS) iniciada em %s\n":                                         "Puerto de las sondas (mTLS) iniciado en %s\n",
+		"Porta das sondas desabilitada, erro ao carregar a CA:":                            "Puerto de las sondas deshabilitado, error al cargar la CA:",
+		"Porta das sondas desabilitada, erro ao carregar o certificado:":                   "Puerto de las sondas deshabilitado, error al cargar el certificado:",
+		"Primeira verificação de %d serviço(s) concluída\n":                                "Primera verificación de %d servicio(s) concluida\n",
+		"proxy inválido na seção [network] (%v), conectando diretamente\n":                 "proxy inválido en la sección [network] (%v), conectando directamente\n",
+		"Push recebido para o serviço [%s]: %s":                                            "Push recibido para el servicio [%s]: %s",
+		"Queda do serviço [%s] reconhecida por %s\n":                                       "Caída del servicio [%s] reconocida por %s\n",
+		"Reconhecimento do serviço [%s] desfeito por %s\n":                                 "Reconocimiento del servicio [%s] deshecho por %s\n",
+		"Rede %q ignorada na seção [access]: %v\n":                                         "Red %q ignorada en la sección [access]: %v\n",
+		"Redirecionamento HTTP → HTTPS na porta :%s\n":                                     "Redirección HTTP → HTTPS en el puerto :%s\n",
+		"Relatório [%s] enviado\n":                                                         "Informe [%s] enviado\n",
+		"Resultado do serviço [%s] descartado: fila de envio à central cheia\n":            "Resultado del servicio [%s] descartado: cola de envío a la central llena\n",
+		"Resultados das verificações gravados em %s (retenção de %s)\n":                    "Resultados de las verificaciones guardados en %s (retención de %s)\n",
+		"schedule inválido na seção [%s], canal acionado a qualquer hora: %v\n":            "schedule inválido en la sección [%s], canal activado a cualquier hora: %v\n",
+		"schedule inválido na seção [%s], relatório desabilitado: %v\n":                    "schedule inválido en la sección [%s], informe deshabilitado: %v\n",
+		"Servidor HTTPS iniciado na porta :%s\n":                                           "Servidor HTTPS iniciado en el puerto :%s\n",
+		"Serviço [%s] fora do ar há %s: verificações espaçadas até %s\n":                   "Servicio [%s] caído hace %s: verificaciones espaciadas hasta %s\n",
+		"Serviço [%s] voltou a ser verificado a cada %s\n":                                 "El servicio [%s] vuelve a verificarse cada %s\n",
+		"Serviços descobertos no Kubernetes alterados, recarregando...":                    "Servicios descubiertos en Kubernetes modificados, recargando...",
+		"Silêncio %s criado por %s até %s\n":                                               "Silencio %s creado por %s hasta %s\n",
+		"Silêncio %s encerrado por %s\n":                                                   "Silencio %s finalizado por %s\n",
+		"Sinal de encerramento recebido, finalizando...":                                   "Señal de terminación recibida, finalizando...",
+		"Sonda [%s] conectada de %s\n":                                                     "Sonda [%s] conectada desde %s\n",
+		"Sonda [%s] desconectada\n":                                                        "Sonda [%s] desconectada\n",
+		"Sonda conectada à central %s\n":                                                   "Sonda conectada a la central %s\n",
+		"Sonda recebeu %d serviço(s) da central\n":                                         "La sonda recibió %d servicio(s) de la central\n",
+		"sparkline_samples inválido (use 0 a %d), usando 30\n":                             "sparkline_samples inválido (use 0 a %d), usando 30\n",
+		"timezone inválido na seção [%s], usando o fuso do servidor: %v\n":                 "timezone inválido en la sección [%s], usando la zona horaria del servidor: %v\n",
+		"Token [%s] (%s) emitido por %s\n":                                                 "Token [%s] (%s) emitido por %s\n",
+		"Token [%s] ignorado: escopo inválido %q\n":                                        "Token [%s] ignorado: alcance inválido %q\n",
+		"Token [%s] ignorado: valor não informado\n":                                       "Token [%s] ignorado: valor no informado\n",
+		"Token [%s] mantido apenas na memória: sem a persistência da seção [storage], será perdido ao reiniciar\n": "Token [%s] mantenido solo en memoria: sin la persistencia de la sección [storage], se perderá al reiniciar\n",
+		"Token [%s] revogado por %s\n": "Token [%s] revocado por %s\n",
+		"Verificação do serviço [%s] levou %s, acima do intervalo de %s (aumente workers ou o intervalo)\n": "La verificación del servicio [%s] tardó %s, más que su intervalo de %s (aumente workers o el intervalo)\n",
+		"Verificação do serviço [%s] voltou a caber no intervalo de %s\n":                                   "La verificación del servicio [%s] vuelve a caber en su intervalo de %s\n",
+		"Página de status pública habilitada sem serviços na seção [public.names]":                          "Página de estado pública habilitada sin servicios en la sección [public.names]",
+		"Página de status pública iniciada em %s\n":                                                         "Página de estado pública iniciada en %s\n",
+		"Erro no servidor da página de status pública:":                                                     "Error en el servidor de la página de estado pública:",
+		"Erro ao carregar public.html:":                                                                     "Error al cargar public.html:",
+		"Erro ao gerar a página de status:":                                                                 "Error al generar la página de estado:",
+		"Erro ao serializar a visão do quiosque:":                                                           "Error al serializar la vista del quiosco:",
+		"Erro ao serializar o estado agregado:":                                                             "Error al serializar el estado agregado:",
+		"Servidor de debug iniciado em %s\n":                                                                "Servidor de debug iniciado en %s\n",
+		"Servidor iniciado na porta :%s\n":                                                                  "Servidor iniciado en el puerto :%s\n",
+		"Serviço [%s] ignorado: %v":                                                                         "Servicio [%s] ignorado: %v",
+		"Serviço [%s] mudou de %s para %s: %s\n":                                                            "Servicio [%s] cambió de %s a %s: %s\n",
+		"Streaming SSE não suportado:":                                                                      "Streaming SSE no soportado:",
+		"downsample_after inválido na seção [storage], agregação desabilitada":                              "downsample_after inválido en [storage], agregación deshabilitada",
+		"downsample_resolution inválido na seção [storage], usando 5m":                                      "downsample_resolution inválido en [storage], usando 5m",
+		"history_retention inválido na seção [storage], usando 90d":                                         "history_retention inválido en [storage], usando 90d",
+		"renotify_every inválido %q, repetição desabilitada\n":                                              "renotify_every inválido %q, repetición deshabilitada\n",
+		"restore inválido na seção [storage], usando 30d":                                                   "restore inválido en [storage], usando 30d",
+		"Webhook [%s] desabilitado, erro ao ler template_file: %v\n":                                        "Webhook [%s] deshabilitado, error al leer template_file: %v\n",
+		"Webhook [%s] desabilitado, template inválido: %v\n":                                                "Webhook [%s] deshabilitado, plantilla inválida: %v\n",
+		"workers inválido (mínimo 1), usando 10":                                                            "workers inválido (mínimo 1), usando 10",
+		"ws_compression inválido na seção [server] (use 0 a 9), usando 1":                                   "ws_compression inválido en [server] (use 0 a 9), usando 1",
+		"[DOWN] %s is still offline":                                                                        "[CAÍDO] %s sigue fuera de línea",
+		"[DOWN] %s is offline":                                                                              "[CAÍDO] %s está fuera de línea",
+		"[UP] %s is back online":                                                                            "[OK] %s volvió a estar en línea",
+		"Offline for %s (first failure at %s)":                                                              "Fuera de línea durante %s (primera falla a las %s)",
+		"Was offline for %s (first failure at %s)":                                                          "Estuvo fuera de línea durante %s (primera falla a las %s)",
+		"Was online for %s":                                                                                 "Estuvo en línea durante %s",
+		"Service":                                                                                           "Servicio",
+		"Group":                                                                                             "Grupo",
+		"Address":                                                                                           "Dirección",
+		"Status":                                                                                            "Estado",
+		"Response time":                                                                                     "Tiempo de respuesta",
+		"Message":                                                                                           "Mensaje",
+		"Time":                                                                                              "Hora",
+		"Open dashboard":                                                                                    "Abrir el panel",
+		"Checked every %s · full refresh every %s":                                                          "Verificado cada %s · actualización completa cada %s",
+		"monitoring paused":                                                                                 "monitoreo pausado",
+		"connection established":                                                                            "conexión establecida",
+		"name resolved":                                                                                     "nombre resuelto",
+		"agent %s has never connected":                                                                      "la sonda %s nunca se conectó",
+		"agent %s disconnected since %s":                                                                    "sonda %s desconectada desde %s",
+		"waiting for the first result from agent %s":                                                        "esperando el primer resultado de la sonda %s",
+		"no location has reported a result":                                                                 "ningún lugar envió resultados",
+		"down only from %s (quorum %d of %d)":                                                               "caído solo desde %s (quórum %d de %d)",
+	},
+}
+
+// Catálogos dos textos do dashboard, indexados pelo texto original em inglês (com os parâmetros entre chaves)
+var dashboardCatalogs = map[string]map[string]string{
+	"pt-BR": {
+		"Signed in as {user} ({role}) · ": "Conectado como {user} ({role}) · ",
+		"Sign out":                        "Sair",
+		"service {name}":                  "serviço {name}",
+		"group {name}":                    "grupo {name}",
+		"tag {name}":                      "tag {name}",
+		"anonymous":                       "anônimo",
+		"🔕 Notifications silenced for {target} until {until} by {user}": "🔕 Notificações silenciadas para {target} até {until} por {user}",
+		"Could not save the incident: {error}":                          "Não foi possível salvar o incidente: {error}",
+		"Incident title:":                                               "Título do incidente:",
+		"Affected services (comma-separated):":                          "Serviços afetados (separados por vírgula):",
+		"What is happening?":                                            "O que está acontecendo?",
+		"Status (investigating, identified, monitoring or resolved):":   "Status (investigating, identified, monitoring ou resolved):",
+		"Resolution note:":                                              "Nota de resolução:",
+		"Update:":                                                       "Atualização:",
+		"Post update":                                                   "Publicar atualização",
+		"Report incident":                                               "Registrar incidente",
+		"down for {duration}":                                           "fora do ar há {duration}",
+		"was down for {duration}":                                       "ficou fora do ar por {duration}",
+		"Paused":                                                        "Pausado",
+		"Response Time: {value}":                                        "Tempo de resposta: {value}",
+		"🔕 silenced":                                                    "🔕 silenciado",
+		"✔ Acknowledged by {user}":                                      "✔ Reconhecido por {user}",
+		"Uptime":                                                        "Disponibilidade",
+		"checks":                                                        "verificações",
+		"Acknowledge {service}? Optional comment:":                      "Reconhecer {service}? Comentário opcional:",
+		"Could not acknowledge {service}: {error}":                      "Não foi possível reconhecer {service}: {error}",
+		"View {position} of {total} · next in {seconds}s":               "Visão {position} de {total} · próxima em {seconds}s",
+		"Last {count} checks":                                           "Últimas {count} verificações",
+		"{up} of {total} up":                                            "{up} de {total} online",
+		" · slowest {service} ({latency} ms)":                           " · mais lento {service} ({latency} ms)",
+		"Acknowledge":                                                   "Reconhecer",
+		"{service} is {status}":                                         "{service} está {status}",
+	},
+	"es": {
+		"Signed in as {user} ({role}) · ": "Conectado como {user} ({role}) · ",
+		"Sign out":                        "Cerrar sesión",
+		"service {name}":                  "servicio {name}",
+		"group {name}":                    "grupo {name}",
+		"tag {name}":                      "etiqueta {name}",
+		"anonymous":                       "anónimo",
+		"🔕 Notifications silenced for {target} until {until} by {user}": "🔕 Notificaciones silenciadas para {target} hasta {until} por {user}",
+		"Could not save the incident: {error}":                          "No se pudo guardar el incidente: {error}",
+		"Incident title:":                                               "Título del incidente:",
+		"Affected services (comma-separated):":                          "Servicios afectados (separados por coma):",
+		"What is happening?":                                            "¿Qué está pasando?",
+		"Status (investigating, identified, monitoring or resolved):":   "Estado (investigating, identified, monitoring o resolved):",
+		"Resolution note:":                                              "Nota de resolución:",
+		"Update:":                                                       "Actualización:",
+		"Post update":                                                   "Publicar actualización",
+		"Report incident":                                               "Reportar incidente",
+		"down for {duration}":                                           "caído hace {duration}",
+		"was down for {duration}":                                       "estuvo caído durante {duration}",
+		"Paused":                                                        "Pausado",
+		"Response Time: {value}":                                        "Tiempo de respuesta: {value}",
+		"🔕 silenced":                                                    "🔕 silenciado",
+		"✔ Acknowledged by {user}":                                      "✔ Reconocido por {user}",
+		"Uptime":                                                        "Disponibilidad",
+		"checks":                                                        "verificaciones",
+		"Acknowledge {service}? Optional comment:":                      "¿Reconocer {service}? Comentario opcional:",
+		"Could not acknowledge {service}: {error}":                      "No se pudo reconocer {service}: {error}",
+		"View {position} of {total} · next in {seconds}s":               "Vista {position} de {total} · siguiente en {seconds}s",
+		"Last {count} checks":                                           "Últimas {count} verificaciones",
+		"{up} of {total} up":                                            "{up} de {total} en línea",
+		" · slowest {service} ({latency} ms)":                           " · más lento {service} ({latency} ms)",
+		"Acknowledge":                                                   "Reconocer",
+		"{service} is {status}":                                         "{service} está {status}",
+	},
+}
+
+// Função para definir o idioma das mensagens (opção language da seção [general]; vazio = textos originais)
+func setLanguage(value string) {
+	if value != "" && !slices.Contains(languages, value) {
+		log.Printf("language inválido na seção [general] (use en, pt-BR ou es): %q, usando os textos originais\n", value)
+		value = ""
+	}
+	currentLanguage.Store(value)
+}
+
+// Função para obter o idioma configurado
+func languageOf() string {
+	value, _ := currentLanguage.Load().(string)
+	return value
+}
+
+// Função para traduzir uma mensagem dos logs ou das notificações para o idioma configurado
+func tr(message string) string {
+	if translated, ok := messageCatalogs[languageOf()][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Função para obter o catálogo dos textos do dashboard no idioma configurado (vazio = textos originais)
+func dashboardCatalog() map[string]string {
+	if catalog, ok := dashboardCatalogs[languageOf()]; ok {
+		return catalog
+	}
+	return map[string]string{}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Status de um incidente, na ordem em que normalmente acontecem
+var incidentStatuses = []string{"investigating", "identified", "monitoring", "resolved"}
+
+// Atualização publicada em um incidente
+type incidentUpdate struct {
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status"`
+	Message string    `json:"message"`
+	User    string    `json:"user,omitempty"`
+}
+
+// Incidente registrado por um operador, para explicar aos usuários o que está acontecendo
+type incident struct {
+	ID         string           `json:"id"`
+	Title      string           `json:"title"`
+	Status     string           `json:"status"`
+	Services   []string         `json:"services"` // Descrições dos serviços afetados
+	CreatedBy  string           `json:"created_by"`
+	CreatedAt  time.Time        `json:"created_at"`
+	StartedAt  time.Time        `json:"started_at"` // Início da queda em andamento mais antiga dos serviços afetados (ou a criação)
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty"`
+	Resolution string           `json:"resolution,omitempty"`
+	Updates    []incidentUpdate `json:"updates"` // Mais recentes primeiro
+}
+
+// Incidente com as quedas registradas automaticamente nos serviços afetados durante o incidente
+type incidentView struct {
+	incident
+	Outages []outage `json:"outages"`
+}
+
+var incidentsMu sync.Mutex             // Mutex para proteger os incidentes
+var incidents = map[string]*incident{} // Incidentes indexados pelo ID
+
+// Função para listar as quedas dos serviços afetados que se sobrepõem ao período do incidente
+func (i incident) view(now time.Time) incidentView {
+	end := now
+	if i.ResolvedAt != nil {
+		end = *i.ResolvedAt
+	}
+	linked := []outage{}
+	outagesMu.Lock()
+	for _, o := range outages {
+		if !slices.Contains(i.Services, o.Service) || o.Start.After(end) || (o.End != nil && o.End.Before(i.StartedAt)) {
+			continue
+		}
+		linked = append(linked, *o)
+		if o.End == nil {
+			linked[len(linked)-1].DurationSeconds = now.Sub(o.Start).Seconds()
+		}
+	}
+	outagesMu.Unlock()
+	return incidentView{incident: i, Outages: linked}
+}
+
+// Função para obter o início do impacto: a queda em andamento mais antiga dos serviços afetados
+func incidentStart(services []string, at time.Time) time.Time {
+	outagesMu.Lock()
+	defer outagesMu.Unlock()
+	for _, service := range services {
+		if o, ok := openOutages[service]; ok && o.Start.Before(at) {
+			at = o.Start
+		}
+	}
+	return at
+}
+
+// Função para verificar se todos os serviços informados existem
+func unknownService(services []string) string {
+	known := map[string]bool{}
+	for _, service := range snapshotServices() {
+		known[service.Description] = true
+	}
+	for _, service := range services {
+		if !known[service] {
+			return service
+		}
+	}
+	return ""
+}
+
+// Handler para listar os incidentes (mais recentes primeiro), com os filtros status=open|resolved e limit
+func listIncidentsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "Parâmetro limit inválido", http.StatusBadRequest)
+			return
+		}
+	}
+	status := query.Get("status")
+	if status != "" && status != "open" && status != "resolved" {
+		http.Error(w, "Parâmetro status inválido (use open ou resolved)", http.StatusBadRequest)
+		return
+	}
+
+	selected := []incident{}
+	incidentsMu.Lock()
+	for _, i := range incidents {
+		if (status == "open" && i.ResolvedAt != nil) || (status == "resolved" && i.ResolvedAt == nil) {
+			continue
+		}
+		selected = append(selected, *i)
+	}
+	incidentsMu.Unlock()
+	sort.Slice(selected, func(a, b int) bool { return selected[a].CreatedAt.After(selected[b].CreatedAt) })
+	if len(selected) > limit {
+		selected = selected[:limit]
+	}
+
+	now := time.Now()
+	result := []incidentView{}
+	for _, i := range selected {
+		result = append(result, i.view(now))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Handler para consultar um incidente
+func getIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	incidentsMu.Lock()
+	i, ok := incidents[r.PathValue("id")]
+	var copied incident
+	if ok {
+		copied = *i
+	}
+	incidentsMu.Unlock()
+	if !ok {
+		http.Error(w, "Incidente não encontrado", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, copied.view(time.Now()))
+}
+
+// Handler para abrir um incidente: {"title": ..., "services": [...], "status": "investigating", "message": ...}
+func createIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Title    string   `json:"title"`
+		Services []string `json:"services"`
+		Status   string   `json:"status"`
+		Message  string   `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Title == "" {
+		http.Error(w, "Informe title", http.StatusBadRequest)
+		return
+	}
+	if request.Status == "" {
+		request.Status = "investigating"
+	}
+	if !slices.Contains(incidentStatuses, request.Status) || request.Status == "resolved" {
+		http.Error(w, "Parâmetro status inválido (use investigating, identified ou monitoring)", http.StatusBadRequest)
+		return
+	}
+	if service := unknownService(request.Services); service != "" {
+		http.Error(w, "Serviço não encontrado: "+service, http.StatusBadRequest)
+		return
+	}
+	if request.Services == nil {
+		request.Services = []string{}
+	}
+
+	now := time.Now()
+	i := &incident{
+		ID:        generateToken()[:16],
+		Title:     request.Title,
+		Status:    request.Status,
+		Services:  request.Services,
+		CreatedBy: currentUser(r),
+		CreatedAt: now,
+		StartedAt: incidentStart(request.Services, now),
+		Updates:   []incidentUpdate{},
+	}
+	if request.Message != "" {
+		i.Updates = append(i.Updates, incidentUpdate{Time: now, Status: request.Status, Message: request.Message, User: i.CreatedBy})
+	}
+	incidentsMu.Lock()
+	incidents[i.ID] = i
+	copied := *i
+	incidentsMu.Unlock()
+	saveIncident(copied)
+
+	log.Printf(tr("Incidente %s aberto por %s: %s\n"), i.ID, i.CreatedBy, i.Title)
+	auditRequest(r, "incident.create", i.ID, nil, copied)
+	writeJSON(w, http.StatusCreated, copied.view(now))
+}
+
+// Handler para alterar o título ou os serviços afetados de um incidente: {"title": ..., "services": [...]}
+func editIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Title    *string   `json:"title"`
+		Services *[]string `json:"services"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Title != nil && *request.Title == "" {
+		http.Error(w, "Parâmetro title inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Services != nil {
+		if service := unknownService(*request.Services); service != "" {
+			http.Error(w, "Serviço não encontrado: "+service, http.StatusBadRequest)
+			return
+		}
+	}
+
+	incidentsMu.Lock()
+	i, ok := incidents[r.PathValue("id")]
+	if !ok {
+		incidentsMu.Unlock()
+		http.Error(w, "Incidente não encontrado", http.StatusNotFound)
+		return
+	}
+	before := *i
+	if request.Title != nil {
+		i.Title = *request.Title
+	}
+	if request.Services != nil {
+		i.Services = *request.Services
+	}
+	copied := *i
+	incidentsMu.Unlock()
+	saveIncident(copied)
+
+	auditRequest(r, "incident.edit", copied.ID, before, copied)
+	writeJSON(w, http.StatusOK, copied.view(time.Now()))
+}
+
+// Handler para publicar uma atualização no incidente: {"status": "monitoring", "message": ...};
+// com status resolved, a mensagem é a nota de resolução e o incidente é encerrado
+func updateIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Message == "" {
+		http.Error(w, "Informe message", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	incidentsMu.Lock()
+	i, ok := incidents[r.PathValue("id")]
+	if !ok {
+		incidentsMu.Unlock()
+		http.Error(w, "Incidente não encontrado", http.StatusNotFound)
+		return
+	}
+	if request.Status == "" {
+		request.Status = i.Status
+	}
+	if !slices.Contains(incidentStatuses, request.Status) {
+		incidentsMu.Unlock()
+		http.Error(w, "Parâmetro status inválido (use investigating, identified, monitoring ou resolved)", http.StatusBadRequest)
+		return
+	}
+	update := incidentUpdate{Time: now, Status: request.Status, Message: request.Message, User: currentUser(r)}
+	i.Updates = append([]incidentUpdate{update}, i.Updates...)
+	i.Status = request.Status
+	if request.Status == "resolved" {
+		i.ResolvedAt = &now
+		i.Resolution = request.Message
+	} else {
+		i.ResolvedAt = nil // Reaberto
+		i.Resolution = ""
+	}
+	copied := *i
+	incidentsMu.Unlock()
+	saveIncident(copied)
+
+	log.Printf(tr("Incidente %s atualizado por %s: %s\n"), copied.ID, update.User, copied.Status)
+	auditRequest(r, "incident.update", copied.ID, nil, update)
+	writeJSON(w, http.StatusOK, copied.view(now))
+}
+
+// Handler para apagar um incidente registrado por engano
+func deleteIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	incidentsMu.Lock()
+	i, ok := incidents[id]
+	delete(incidents, id)
+	incidentsMu.Unlock()
+	if !ok {
+		http.Error(w, "Incidente não encontrado", http.StatusNotFound)
+		return
+	}
+	deleteIncident(id)
+
+	auditRequest(r, "incident.delete", id, *i, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações do modo quiosque (seção [kiosk]): o servidor alterna as visões exibidas em /kiosk
+type KioskConfig struct {
+	Views    []string      // Visões ([view.<nome>]) exibidas em sequência
+	Interval time.Duration // Tempo de exibição de cada visão
+}
+
+// Mensagem de controle enviada pelo WebSocket aos quiosques ao trocar de visão (e ao conectar):
+// {"type": "kiosk", "seq": ..., "view": {...}, "views": [...], "position": 0, "interval_seconds": 30, "next_at": ...}.
+// Em seguida o quiosque recebe um snapshot apenas com os serviços da visão.
+type kioskMessage struct {
+	Type     string      `json:"type"`
+	Seq      uint64      `json:"seq"`
+	View     *ViewConfig `json:"view"` // nil = todos os serviços (nenhuma visão configurada)
+	Views    []string    `json:"views"`
+	Position int         `json:"position"` // Posição da visão atual em views
+	Interval float64     `json:"interval_seconds"`
+	NextAt   time.Time   `json:"next_at"` // Horário previsto da próxima troca
+}
+
+var kioskMu sync.Mutex  // Mutex para proteger a visão atual do quiosque
+var kioskPosition int   // Posição da visão exibida em views
+var kioskNext time.Time // Horário previsto da próxima troca
+
+// Função para ler a seção [kiosk] do config.ini
+func loadKioskConfig(cfg *ini.File) KioskConfig {
+	section := cfg.Section("kiosk")
+	config := KioskConfig{
+		Views:    splitList(section.Key("views").String()),
+		Interval: section.Key("interval").MustDuration(30 * time.Second),
+	}
+	if config.Interval < 5*time.Second {
+		log.Println(tr("interval inválido na seção [kiosk] (mínimo 5s), usando 30s"))
+		config.Interval = 30 * time.Second
+	}
+	return config
+}
+
+// Função para montar a mensagem com a visão atual do quiosque
+func kioskState(seq uint64) kioskMessage {
+	config := getConfig().Kiosk
+	kioskMu.Lock()
+	defer kioskMu.Unlock()
+	message := kioskMessage{Type: "kiosk", Seq: seq, Views: config.Views, Interval: config.Interval.Seconds(), NextAt: kioskNext}
+	if message.Views == nil {
+		message.Views = []string{}
+	}
+	if len(config.Views) > 0 {
+		message.Position = kioskPosition % len(config.Views)
+		if view, ok := findView(config.Views[message.Position]); ok {
+			message.View = &view
+		}
+	}
+	return message
+}
+
+// Função para obter a assinatura com os serviços da visão atual do quiosque (nil = todos)
+func kioskFilter() *wsFilter {
+	if view := kioskState(0).View; view != nil {
+		return viewFilter(view.Name)
+	}
+	return nil
+}
+
+// Função para alternar as visões dos quiosques no intervalo configurado. As alterações em views e interval
+// valem a partir da próxima troca.
+func runKiosk() {
+	kioskMu.Lock()
+	kioskNext = time.Now().Add(getConfig().Kiosk.Interval)
+	kioskMu.Unlock()
+	for {
+		kioskMu.Lock()
+		wait := time.Until(kioskNext)
+		kioskMu.Unlock()
+		time.Sleep(wait)
+
+		config := getConfig().Kiosk
+		kioskMu.Lock()
+		kioskNext = time.Now().Add(config.Interval)
+		if len(config.Views) > 0 {
+			kioskPosition = (kioskPosition + 1) % len(config.Views)
+		}
+		kioskMu.Unlock()
+		if len(config.Views) > 0 {
+			hub.rotate()
+		}
+	}
+}
+
+// Função para marcar o cliente como quiosque e enviar a visão atual, seguida do snapshot da visão
+func (h *wsHub) joinKiosk(client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	client.kiosk = true
+	h.showKiosk(client, kioskState(h.seq))
+}
+
+// Função para exibir a nova visão em todos os quiosques conectados
+func (h *wsHub) rotate() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := kioskState(h.seq)
+	for client := range h.clients {
+		if client.kiosk {
+			h.showKiosk(client, state)
+		}
+	}
+}
+
+// Função para trocar a assinatura do quiosque e enfileirar a mensagem de controle e o snapshot (chamada com h.mu travado)
+func (h *wsHub) showKiosk(client *wsClient, state kioskMessage) {
+	var filter *wsFilter
+	if state.View != nil {
+		filter = viewFilter(state.View.Name)
+	}
+	h.setFilter(client, restrictFilter(filter, client.allowed))
+	data, err := json.Marshal(state)
+	if err != nil {
+		log.Println(tr("Erro ao serializar a visão do quiosque:"), err)
+		return
+	}
+	h.enqueue(client, data)
+	h.enqueue(client, nil)
+}
+
+// Handler para o dashboard do modo quiosque, para telas sem operador em salas de NOC
+func kioskHandler(w http.ResponseWriter, r *http.Request) {
+	page := brandingPage(getConfig().Branding)
+	page.Kiosk = true
+	renderDashboard(w, page)
+}
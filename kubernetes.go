@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	kubeServiceAccount = "/var/run/secrets/kubernetes.io/serviceaccount/" // Credenciais montadas nos pods
+	kubeRetryDelay     = 10 * time.Second                                 // Espera antes de repetir a listagem após um erro
+	kubeWatchTimeout   = 5 * time.Minute                                  // Duração de cada watch antes de ser renovado
+)
+
+// Configurações da seção [kubernetes]: descoberta dos serviços monitorados pela API do cluster
+type KubernetesConfig struct {
+	Enabled    bool
+	APIServer  string   // Endereço da API (vazio = a do cluster em que o processo roda)
+	TokenFile  string   // Token da service account, relido a cada requisição (tokens projetados são renovados)
+	CAFile     string   // CA da API
+	Namespaces []string // Namespaces observados (vazio = todos)
+	Selector   string   // Label selector dos Services (ex.: monitor=true)
+	Target     string   // service (ClusterIP de cada porta) ou endpoints (cada pod de cada porta)
+	Group      string   // Grupo dos serviços descobertos (vazio = o namespace)
+	Options    string   // Opções aplicadas a todos os serviços descobertos (ex.: interval=30s tags=k8s)
+}
+
+// Função para ler a seção [kubernetes] do config.ini
+func loadKubernetesConfig(cfg *ini.File) KubernetesConfig {
+	section := cfg.Section("kubernetes")
+	config := KubernetesConfig{
+		Enabled:   section.Key("enabled").MustBool(false),
+		APIServer: strings.TrimSuffix(section.Key("api_server").String(), "/"),
+		TokenFile: section.Key("token_file").MustString(kubeServiceAccount + "token"),
+		CAFile:    section.Key("ca_file").MustString(kubeServiceAccount + "ca.crt"),
+		Selector:  section.Key("label_selector").String(),
+		Target:    section.Key("target").In("service", []string{"service", "endpoints"}),
+		Group:     section.Key("group").String(),
+		Options:   section.Key("options").String(),
+	}
+	for _, namespace := range strings.Split(section.Key("namespaces").String(), ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			config.Namespaces = append(config.Namespaces, namespace)
+		}
+	}
+	if config.APIServer == "" {
+		if host := os.Getenv("KUBERNETES_SERVICE_HOST"); host != "" {
+			config.APIServer = "https://" + net.JoinHostPort(host, os.Getenv("KUBERNETES_SERVICE_PORT"))
+		}
+	}
+	if _, err := parseService("kubernetes", "127.0.0.1:1 "+config.Options); err != nil {
+		log.Printf(tr("options inválido na seção [kubernetes] (%v), ignorado\n"), err)
+		config.Options = ""
+	}
+	return config
+}
+
+// Objeto retornado pela API: um Service ou um Endpoints, com os campos usados na descoberta
+type kubeObject struct {
+	Metadata struct {
+		Name            string `json:"name"`
+		Namespace       string `json:"namespace"`
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Spec struct {
+		ClusterIP string     `json:"clusterIP"`
+		Ports     []kubePort `json:"ports"`
+	} `json:"spec"`
+	Subsets []struct {
+		Addresses []struct {
+			IP        string `json:"ip"`
+			TargetRef *struct {
+				Name string `json:"name"`
+			} `json:"targetRef"`
+		} `json:"addresses"`
+		Ports []kubePort `json:"ports"`
+	} `json:"subsets"`
+}
+
+// Porta de um Service ou de um Endpoints
+type kubePort struct {
+	Name     string `json:"name"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+var kubeMu sync.Mutex                                // Mutex para proteger os serviços descobertos
+var kubeServices = map[string]map[string][]Service{} // Serviços descobertos por namespace observado e objeto
+var kubeVersion atomic.Int64                         // Incrementado a cada mudança nos serviços descobertos
+
+// Função para listar os serviços descobertos no Kubernetes, em ordem de descrição, acrescentados aos do config.ini
+func discoveredServices() []Service {
+	kubeMu.Lock()
+	defer kubeMu.Unlock()
+	list := []Service{}
+	for _, objects := range kubeServices {
+		for _, services := range objects {
+			list = append(list, services...)
+		}
+	}
+	slices.SortFunc(list, func(a, b Service) int { return strings.Compare(a.Description, b.Description) })
+	return list
+}
+
+// Função para iniciar a descoberta: lista e acompanha (watch) os Services ou Endpoints com o label selector em
+// cada namespace, reconectando após erros. Cada mudança recarrega os serviços monitorados, como uma alteração do
+// config.ini. Alterações na seção [kubernetes] exigem reiniciar o processo.
+func startKubernetesDiscovery(ctx context.Context, config KubernetesConfig) {
+	if !config.Enabled {
+		return
+	}
+	if config.APIServer == "" {
+		log.Println(tr("Descoberta do Kubernetes desabilitada: informe api_server fora do cluster"))
+		return
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if pool, err := loadCAPool(config.CAFile); err == nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	} else if !os.IsNotExist(err) {
+		log.Println(tr("Erro ao carregar a CA da API do Kubernetes:"), err)
+	}
+	client := &http.Client{Transport: transport}
+
+	namespaces := config.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""} // Todos os namespaces
+	}
+	log.Printf(tr("Descobrindo serviços do Kubernetes em %s (%s, selector %q)\n"), config.APIServer, config.Target, config.Selector)
+	for _, namespace := range namespaces {
+		go func() {
+			for {
+				err := watchKubernetes(ctx, client, config, namespace)
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf(tr("Erro na descoberta do Kubernetes (namespace %q): %v\n"), namespace, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(kubeRetryDelay):
+				}
+			}
+		}()
+	}
+}
+
+// Função para fazer uma requisição à API com o token da service account
+func kubeRequest(ctx context.Context, client *http.Client, config KubernetesConfig, namespace string, query url.Values) (*http.Response, error) {
+	resource := "services"
+	if config.Target == "endpoints" {
+		resource = "endpoints"
+	}
+	path := "/api/v1/" + resource
+	if namespace != "" {
+		path = "/api/v1/namespaces/" + url.PathEscape(namespace) + "/" + resource
+	}
+	if config.Selector != "" {
+		query.Set("labelSelector", config.Selector)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.APIServer+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if token, err := os.ReadFile(config.TokenFile); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// Função para listar os objetos de um namespace e acompanhar as mudanças a partir da versão listada, renovando o
+// watch até ocorrer um erro (inclusive a versão expirada, que exige listar de novo)
+func watchKubernetes(ctx context.Context, client *http.Client, config KubernetesConfig, namespace string) error {
+	resp, err := kubeRequest(ctx, client, config, namespace, url.Values{})
+	if err != nil {
+		return err
+	}
+	var list struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Items []kubeObject `json:"items"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&list)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	objects := map[string][]Service{}
+	for _, object := range list.Items {
+		objects[object.Metadata.Namespace+"/"+object.Metadata.Name] = kubeObjectServices(object, config)
+	}
+	setDiscovered(namespace, func(current map[string][]Service) map[string][]Service { return objects })
+
+	version := list.Metadata.ResourceVersion
+	for {
+		query := url.Values{"watch": {"1"}, "resourceVersion": {version}, "allowWatchBookmarks": {"true"},
+			"timeoutSeconds": {strconv.Itoa(int(kubeWatchTimeout.Seconds()))}}
+		resp, err := kubeRequest(ctx, client, config, namespace, query)
+		if err != nil {
+			return err
+		}
+		version, err = applyKubeEvents(resp.Body, config, namespace, version)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Função para aplicar os eventos de um watch até o fim do stream, retornando a última versão vista
+func applyKubeEvents(body io.Reader, config KubernetesConfig, namespace, version string) (string, error) {
+	decoder := json.NewDecoder(body)
+	for {
+		var event struct {
+			Type   string          `json:"type"`
+			Object json.RawMessage `json:"object"`
+		}
+		if err := decoder.Decode(&event); err == io.EOF {
+			return version, nil
+		} else if err != nil {
+			return version, err
+		}
+		if event.Type == "ERROR" {
+			var status struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+			json.Unmarshal(event.Object, &status)
+			return version, fmt.Errorf("watch encerrado pela API (%d): %s", status.Code, status.Message)
+		}
+		var object kubeObject
+		if err := json.Unmarshal(event.Object, &object); err != nil {
+			return version, err
+		}
+		version = object.Metadata.ResourceVersion
+		key := object.Metadata.Namespace + "/" + object.Metadata.Name
+		switch event.Type {
+		case "ADDED", "MODIFIED":
+			services := kubeObjectServices(object, config)
+			setDiscovered(namespace, func(current map[string][]Service) map[string][]Service {
+				current[key] = services
+				return current
+			})
+		case "DELETED":
+			setDiscovered(namespace, func(current map[string][]Service) map[string][]Service {
+				delete(current, key)
+				return current
+			})
+		}
+	}
+}
+
+// Função para atualizar os serviços descobertos em um namespace observado, sinalizando a recarga se mudaram
+func setDiscovered(namespace string, update func(current map[string][]Service) map[string][]Service) {
+	kubeMu.Lock()
+	defer kubeMu.Unlock()
+	previous := kubeServices[namespace]
+	before := kubeServiceKeys(previous)
+	current := map[string][]Service{}
+	for key, services := range previous {
+		current[key] = services
+	}
+	current = update(current)
+	kubeServices[namespace] = current
+	if !slices.Equal(before, kubeServiceKeys(current)) {
+		kubeVersion.Add(1)
+	}
+}
+
+// Função para resumir os serviços descobertos (descrição e endereço), usado para detectar as mudanças
+func kubeServiceKeys(objects map[string][]Service) []string {
+	keys := []string{}
+	for _, services := range objects {
+		for _, service := range services {
+			keys = append(keys, service.Description+"\x00"+net.JoinHostPort(service.IP, service.Port))
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Função para montar os serviços monitorados de um objeto: uma verificação por porta TCP no ClusterIP do Service
+// (<namespace>/<nome>:<porta>) ou em cada pod do Endpoints (<namespace>/<nome>/<pod>:<porta>). Services sem
+// ClusterIP (headless) só são verificados com target=endpoints.
+func kubeObjectServices(object kubeObject, config KubernetesConfig) []Service {
+	group := config.Group
+	if group == "" {
+		group = object.Metadata.Namespace
+	}
+	prefix := object.Metadata.Namespace + "/" + object.Metadata.Name
+	services := []Service{}
+	add := func(description, ip string, port kubePort) {
+		if port.Protocol != "" && port.Protocol != "TCP" {
+			return
+		}
+		name := port.Name
+		if name == "" {
+			name = strconv.Itoa(port.Port)
+		}
+		service, err := parseService(description+":"+name, net.JoinHostPort(ip, strconv.Itoa(port.Port))+" "+config.Options)
+		if err != nil {
+			return
+		}
+		service.Group = group
+		services = append(services, service)
+	}
+	if config.Target == "endpoints" {
+		for _, subset := range object.Subsets {
+			for _, address := range subset.Addresses {
+				pod := address.IP
+				if address.TargetRef != nil && address.TargetRef.Name != "" {
+					pod = address.TargetRef.Name
+				}
+				for _, port := range subset.Ports {
+					add(prefix+"/"+pod, address.IP, port)
+				}
+			}
+		}
+		return services
+	}
+	if ip := object.Spec.ClusterIP; ip != "" && ip != "None" {
+		for _, port := range object.Spec.Ports {
+			add(prefix, ip, port)
+		}
+	}
+	return services
+}
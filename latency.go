@@ -0,0 +1,123 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Janela móvel em que os percentis do tempo de resposta são calculados
+type latencyWindow struct {
+	Name     string // Nome no payload, como informado no config.ini (ex.: 1h)
+	Duration time.Duration
+}
+
+// Percentis do tempo de resposta (em milissegundos) das verificações bem-sucedidas de uma janela
+type latencyPercentiles struct {
+	P50     int64 `json:"p50"`
+	P95     int64 `json:"p95"`
+	P99     int64 `json:"p99"`
+	Samples int   `json:"samples"`
+}
+
+// Função para ler as janelas de latency_windows da seção [general] (padrão 1h e 24h)
+func loadLatencyWindows(cfg *ini.File) []latencyWindow {
+	windows := []latencyWindow{}
+	for _, name := range splitList(cfg.Section("general").Key("latency_windows").MustString("1h,24h")) {
+		duration, err := parseRange(name, 0)
+		if err != nil {
+			log.Printf(tr("Janela %q inválida em latency_windows, ignorada\n"), name)
+			continue
+		}
+		windows = append(windows, latencyWindow{Name: name, Duration: duration})
+	}
+	return windows
+}
+
+// Função para ler sparkline_samples da seção [general]: quantos tempos de resposta recentes de cada serviço
+// são enviados ao dashboard (0 desabilita), limitado às amostras guardadas em memória
+func loadSparklineSamples(cfg *ini.File) int {
+	samples := cfg.Section("general").Key("sparkline_samples").MustInt(30)
+	if samples < 0 || samples > maxSamplesPerService {
+		log.Printf(tr("sparkline_samples inválido (use 0 a %d), usando 30\n"), maxSamplesPerService)
+		return 30
+	}
+	return samples
+}
+
+// Função para obter os últimos n tempos de resposta do serviço, do mais antigo ao mais recente, para o gráfico
+// de tendência do dashboard (-1 = verificação com falha; nil sem amostras)
+func recentLatencies(service Service, n int) []int64 {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	samples := sampleHistory[service.Description]
+	samples = samples[max(len(samples)-n, 0):]
+	if n <= 0 || len(samples) == 0 {
+		return nil
+	}
+	latencies := make([]int64, len(samples))
+	for i, s := range samples {
+		latencies[i] = s.LatencyMs
+		if s.Status != "green" {
+			latencies[i] = -1
+		}
+	}
+	return latencies
+}
+
+// Função para calcular os percentis do tempo de resposta do serviço em cada janela (nil sem amostras)
+func computeLatencyPercentiles(service Service, windows []latencyWindow, at time.Time) map[string]latencyPercentiles {
+	if len(windows) == 0 {
+		return nil
+	}
+	longest := windows[0].Duration
+	for _, window := range windows {
+		longest = max(longest, window.Duration)
+	}
+
+	// Copia as amostras da maior janela; falhas não entram, pois o tempo até a falha não é tempo de resposta
+	type sample struct {
+		time    time.Time
+		latency int64
+	}
+	samples := []sample{}
+	historyMu.Lock()
+	for _, s := range sampleHistory[service.Description] {
+		if s.Status == "green" && !s.Time.Before(at.Add(-longest)) && !s.Time.After(at) {
+			samples = append(samples, sample{s.Time, s.LatencyMs})
+		}
+	}
+	historyMu.Unlock()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	result := map[string]latencyPercentiles{}
+	for _, window := range windows {
+		latencies := []int64{}
+		for _, s := range samples {
+			if !s.time.Before(at.Add(-window.Duration)) {
+				latencies = append(latencies, s.latency)
+			}
+		}
+		if len(latencies) == 0 {
+			continue
+		}
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		result[window.Name] = latencyPercentiles{
+			P50:     percentile(latencies, 50),
+			P95:     percentile(latencies, 95),
+			P99:     percentile(latencies, 99),
+			Samples: len(latencies),
+		}
+	}
+	return result
+}
+
+// Função para obter o percentil p de valores ordenados (método nearest-rank)
+func percentile(sorted []int64, p int) int64 {
+	rank := (p*len(sorted) + 99) / 100 // Arredonda para cima
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [ldap]
+type LDAPConfig struct {
+	Enabled             bool
+	URL                 string // ldap://servidor:389 ou ldaps://servidor:636
+	StartTLS            bool
+	SkipVerify          bool
+	BindDN              string // Conta de serviço usada para localizar os usuários
+	BindPassword        string
+	BaseDN              string
+	UserFilter          string // %s é substituído pelo usuário informado no login
+	GroupFilter         string // Filtro adicional exigido para acessar o monitoramento (vazio = todos os usuários)
+	AdminGroupFilter    string // Filtro adicional que concede o papel admin
+	OperatorGroupFilter string // Filtro adicional que concede o papel operator
+}
+
+// Função para ler a seção [ldap] do config.ini
+func loadLDAPConfig(cfg *ini.File) LDAPConfig {
+	section := cfg.Section("ldap")
+	return LDAPConfig{
+		Enabled:             section.Key("enabled").MustBool(false),
+		URL:                 section.Key("url").String(),
+		StartTLS:            section.Key("start_tls").MustBool(false),
+		SkipVerify:          section.Key("insecure_skip_verify").MustBool(false),
+		BindDN:              section.Key("bind_dn").String(),
+		BindPassword:        section.Key("bind_password").String(),
+		BaseDN:              section.Key("base_dn").String(),
+		UserFilter:          section.Key("user_filter").MustString("(sAMAccountName=%s)"),
+		GroupFilter:         section.Key("group_filter").String(),
+		AdminGroupFilter:    section.Key("admin_group_filter").String(),
+		OperatorGroupFilter: section.Key("operator_group_filter").String(),
+	}
+}
+
+// Logins LDAP já validados, evitando consultar o servidor a cada requisição
+type ldapCacheEntry struct {
+	Role    string
+	Expires time.Time
+}
+
+var ldapCacheMu sync.Mutex
+var ldapCache = map[[32]byte]ldapCacheEntry{}
+
+// Função para validar usuário e senha no LDAP/AD e obter o papel do usuário
+func checkLDAP(config LDAPConfig, user, password string) (string, bool) {
+	if user == "" || password == "" { // Bind sem senha é aceito como anônimo por muitos servidores
+		return "", false
+	}
+
+	key := sha256.Sum256([]byte(config.URL + "\x00" + user + "\x00" + password))
+	ldapCacheMu.Lock()
+	entry, cached := ldapCache[key]
+	ldapCacheMu.Unlock()
+	if cached && time.Now().Before(entry.Expires) {
+		return entry.Role, true
+	}
+
+	role, err := ldapAuthenticate(config, user, password)
+	if err != nil {
+		log.Printf(tr("Login LDAP de %s recusado: %v\n"), user, err)
+		return "", false
+	}
+	ldapCacheMu.Lock()
+	ldapCache[key] = ldapCacheEntry{Role: role, Expires: time.Now().Add(authCacheTTL)}
+	ldapCacheMu.Unlock()
+	return role, true
+}
+
+// Função que localiza o usuário com a conta de serviço, valida a senha com um bind
+// do próprio usuário e verifica os filtros de grupo
+func ldapAuthenticate(config LDAPConfig, user, password string) (string, error) {
+	conn, err := ldap.DialURL(config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}),
+		ldap.DialWithTLSConfig(&tls.Config{InsecureSkipVerify: config.SkipVerify}))
+	if err != nil {
+		return "", fmt.Errorf("conexão com %s: %w", config.URL, err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(5 * time.Second)
+
+	if config.StartTLS {
+		serverName := config.URL
+		if parsed, err := url.Parse(config.URL); err == nil {
+			serverName = parsed.Hostname()
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: serverName, InsecureSkipVerify: config.SkipVerify}); err != nil {
+			return "", fmt.Errorf("StartTLS: %w", err)
+		}
+	}
+
+	if config.BindDN != "" {
+		if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+			return "", fmt.Errorf("bind da conta de serviço: %w", err)
+		}
+	}
+
+	userFilter := strings.ReplaceAll(config.UserFilter, "%s", ldap.EscapeFilter(user))
+	search := func(filter string) ([]*ldap.Entry, error) {
+		result, err := conn.Search(ldap.NewSearchRequest(config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+			2, 5, false, filter, []string{"dn"}, nil))
+		if err != nil {
+			return nil, err
+		}
+		return result.Entries, nil
+	}
+
+	entries, err := search(userFilter)
+	if err != nil {
+		return "", fmt.Errorf("busca do usuário: %w", err)
+	}
+	if len(entries) != 1 {
+		return "", fmt.Errorf("usuário não encontrado ou ambíguo")
+	}
+	if err := conn.Bind(entries[0].DN, password); err != nil {
+		return "", fmt.Errorf("senha inválida")
+	}
+
+	// Os filtros de grupo são avaliados com a conta de serviço (ou com o próprio usuário, sem bind_dn)
+	if config.BindDN != "" {
+		if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+			return "", fmt.Errorf("bind da conta de serviço: %w", err)
+		}
+	}
+	for _, candidate := range []struct{ filter, role string }{
+		{config.AdminGroupFilter, roleAdmin},
+		{config.OperatorGroupFilter, roleOperator},
+	} {
+		if candidate.filter == "" {
+			continue
+		}
+		if entries, err := search("(&" + userFilter + candidate.filter + ")"); err == nil && len(entries) == 1 {
+			return candidate.role, nil
+		}
+	}
+	if config.GroupFilter == "" {
+		return roleViewer, nil
+	}
+	entries, err = search("(&" + userFilter + config.GroupFilter + ")")
+	if err != nil {
+		return "", fmt.Errorf("busca do grupo: %w", err)
+	}
+	if len(entries) != 1 {
+		return "", fmt.Errorf("usuário fora do grupo autorizado")
+	}
+	return roleViewer, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const localLocation = "local" // Nome da própria central na opção agent= (verificação feita por ela)
+
+// Status e tempo de resposta de um serviço em um dos locais que o verificam
+type locationStatus struct {
+	Location     string `json:"Location"`
+	Status       string `json:"Status"`
+	ResponseTime string `json:"ResponseTime,omitempty"`
+	Message      string `json:"Message,omitempty"` // Motivo da falha ou da falta de resultado
+}
+
+// Resultado combinado das verificações de um serviço feitas de um ou mais locais
+type locationsResult struct {
+	Status    string
+	LatencyMs int64
+	Reason    string // Motivo publicado na mudança de status
+	Message   string // Aviso exibido no dashboard (fora do ar em menos locais que o quorum)
+	Locations []locationStatus
+}
+
+// Função para obter os locais que verificam um serviço (opção agent=, com as sondas e local separados por vírgula)
+func serviceLocations(service Service) []string {
+	if value := service.Options["agent"]; value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
+
+// Função para validar as opções agent= e quorum= de um serviço
+func validateLocations(service Service) error {
+	locations := serviceLocations(service)
+	for i, location := range locations {
+		if location == "" || slices.Contains(locations[:i], location) {
+			return fmt.Errorf("agent inválido %q", service.Options["agent"])
+		}
+	}
+	if value, ok := service.Options["quorum"]; ok {
+		if len(locations) == 0 {
+			return fmt.Errorf("quorum exige agent=")
+		}
+		if n, err := strconv.Atoi(value); err != nil && !slices.Contains([]string{"any", "majority", "all"}, value) || err == nil && (n < 1 || n > len(locations)) {
+			return fmt.Errorf("quorum inválido %q (use any, majority, all ou de 1 a %d)", value, len(locations))
+		}
+	}
+	return nil
+}
+
+// Função para calcular quantos locais precisam ver o serviço fora do ar para ele ficar vermelho, entre os que
+// enviaram resultado (opção quorum=: any, majority, o padrão, all ou um número fixo de locais)
+func quorumOf(service Service, reporting int) int {
+	switch value := service.Options["quorum"]; value {
+	case "", "majority":
+		return reporting/2 + 1
+	case "any":
+		return 1
+	case "all":
+		return max(reporting, 1)
+	default:
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+}
+
+// Função para verificar um serviço em cada um dos seus locais (o último resultado de cada sonda e, com local,
+// a verificação feita pela própria central) e combinar os resultados pelo quorum: o serviço só fica vermelho
+// quando o número de locais que o veem fora do ar atinge o quorum, para distinguir a queda do serviço da queda
+// do link de uma filial. Locais sem resultado (sonda desconectada) não entram na conta; sem nenhum resultado o
+// status é "unknown". ok é false quando a verificação local foi interrompida.
+func evaluateLocations(ctx context.Context, service Service) (locationsResult, bool) {
+	result := locationsResult{}
+	var down, up []string
+	var reasons, upReasons []string
+	var latency int64
+	for _, location := range serviceLocations(service) {
+		var status, reason string
+		var ms int64
+		if location == localLocation {
+			var ok bool
+			if status, ms, reason, ok = probeService(ctx, service); !ok {
+				return result, false
+			}
+		} else {
+			status, ms, reason = evaluateAgent(service, location)
+		}
+
+		entry := locationStatus{Location: location, Status: status}
+		switch status {
+		case "green":
+			up = append(up, location)
+			upReasons = append(upReasons, reason)
+			latency += ms
+			entry.ResponseTime = formatResponseTime(ms)
+		case "red":
+			down = append(down, location)
+			reasons = append(reasons, location+": "+reason)
+			entry.ResponseTime = formatResponseTime(ms)
+			entry.Message = reason
+		default:
+			entry.Message = reason
+		}
+		result.Locations = append(result.Locations, entry)
+	}
+
+	reporting := len(down) + len(up)
+	switch {
+	case reporting == 0:
+		result.Status = "unknown"
+		result.Message = result.Locations[0].Message
+		if len(result.Locations) > 1 {
+			result.Message = tr("no location has reported a result")
+		}
+	case len(down) >= quorumOf(service, reporting):
+		result.Status = "red"
+		result.Reason = strings.Join(reasons, "; ")
+		if len(result.Locations) == 1 {
+			result.Reason = result.Locations[0].Message
+		}
+	case len(up) == 0:
+		// Fora do ar em menos locais que o quorum fixo e nenhum local o vê online
+		result.Status = "unknown"
+		result.Message = fmt.Sprintf(tr("down only from %s (quorum %d of %d)"), strings.Join(down, ", "), quorumOf(service, reporting), reporting)
+	default:
+		result.Status = "green"
+		result.LatencyMs = latency / int64(len(up))
+		result.Reason = upReasons[0]
+		if len(down) > 0 {
+			result.Message = fmt.Sprintf(tr("down only from %s (quorum %d of %d)"), strings.Join(down, ", "), quorumOf(service, reporting), reporting)
+		}
+	}
+	return result, true
+}
@@ -1,16 +1,23 @@
 package main
 
 import (
-	"html/template"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"io" // Import adicionado
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -20,111 +27,525 @@ import (
 type Service struct {
 	ID           int    `json:"id"`           // Exportado e incluído no JSON
 	Description  string `json:"Description"`  // Exportado e incluído no JSON
+	Group        string `json:"Group"`        // Grupo definido pela seção [services.<grupo>]
 	IP           string `json:"-"`            // Excluído do JSON
 	Port         string `json:"-"`            // Excluído do JSON
 	Status       string `json:"Status"`       // Exportado e incluído no JSON
 	ResponseTime string `json:"ResponseTime"` // Exportado e incluído no JSON
+	Message      string `json:"Message,omitempty"`
+	LatencyMs    int64  `json:"-"` // Tempo de resposta numérico, usado nas métricas
+
+	Type         string            `json:"-"` // "tcp" (padrão), "push" (status enviado por sistemas externos) ou "dns" (resolução de um nome)
+	PushToken    string            `json:"-"` // Token de /api/push/{token} para serviços do tipo push
+	PushInterval time.Duration     `json:"-"` // Intervalo máximo entre pushes antes de o serviço ficar vermelho
+	Interval     time.Duration     `json:"-"` // Intervalo entre as verificações do serviço (opção interval=), 0 = check_interval
+	Options      map[string]string `json:"-"` // Opções adicionais informadas após o endereço (chave=valor)
+
+	Pinned bool `json:"Pinned,omitempty"` // Fixado no topo do dashboard (opção pinned=true)
+	Order  int  `json:"Order,omitempty"`  // Posição na ordenação manual (opção order=), 0 = depois dos numerados
+
+	DownSince    *time.Time                    `json:"DownSince,omitempty"`    // Primeira falha da queda atual
+	LastDowntime string                        `json:"LastDowntime,omitempty"` // Duração da última queda (ex.: 14m)
+	RecoveredAt  *time.Time                    `json:"RecoveredAt,omitempty"`  // Momento em que o serviço voltou da última queda
+	ChangedAt    *time.Time                    `json:"ChangedAt,omitempty"`    // Última mudança de status
+	Silenced     bool                          `json:"Silenced,omitempty"`     // Notificações suspensas por um silêncio ativo
+	Uptime       *uptimeSummary                `json:"Uptime,omitempty"`       // Disponibilidade nas últimas 24 horas, 7 e 30 dias
+	Latency      map[string]latencyPercentiles `json:"Latency,omitempty"`      // Percentis do tempo de resposta por janela (latency_windows)
+	Sparkline    []int64                       `json:"Sparkline,omitempty"`    // Últimos tempos de resposta em ms (sparkline_samples), -1 = falha
+	Acknowledged *acknowledgment               `json:"Acknowledged,omitempty"` // Reconhecimento da queda atual
+	Locations    []locationStatus              `json:"Locations,omitempty"`    // Status e tempo de resposta em cada local (agent= com vários locais)
+}
+
+// Configurações lidas do config.ini
+type Config struct {
+	Services     []Service
+	Port         string
+	Interval     time.Duration // Intervalo padrão entre as verificações de cada serviço (check_interval)
+	Timeout      time.Duration // Prazo de cada verificação, somando a resolução DNS e a conexão (timeout)
+	PushInterval time.Duration // Intervalo dos snapshots completos enviados ao dashboard (push_interval)
+	PathLog      string
+	PublicURL    string          // Endereço público do dashboard, usado nos links das notificações
+	Latency      []latencyWindow // Janelas dos percentis do tempo de resposta
+	Sparkline    int             // Amostras de tempo de resposta enviadas ao dashboard por serviço (sparkline_samples)
+	Workers      int             // Verificações simultâneas (workers)
+	Jitter       JitterConfig    // Variação aleatória dos intervalos entre as verificações (jitter)
+	Backoff      BackoffConfig
+	HA           HAConfig
+	Kubernetes   KubernetesConfig
+	DNS          DNSConfig
+	Network      NetworkConfig
+	Storage      StorageConfig
+	TSDB         TSDBConfig
+	Alerts       AlertsConfig
+	Escalation   map[string][]EscalationStep // Políticas de escalonamento por grupo
+	Routes       map[string]NotifierRoute    // Filtros dos canais de notificação, pelo nome da seção
+	Schedules    map[string]NotifierSchedule // Horários de funcionamento dos canais, pelo nome da seção
+	Messages     map[string]MessageTemplates // Templates das mensagens dos canais, pelo nome da seção
+	Reports      []ReportConfig              // Relatórios periódicos por e-mail, das seções [report.<nome>]
+	Debug        DebugConfig
+	Server       ServerConfig
+	Branding     BrandingConfig
+	UI           UIConfig
+	Public       PublicConfig
+	Views        []ViewConfig // Visões nomeadas do dashboard, das seções [view.<nome>]
+	Kiosk        KioskConfig
+	Embed        EmbedConfig
+	Auth         AuthConfig
+	OIDC         OIDCConfig
+	LDAP         LDAPConfig
+	TLS          TLSConfig
+	Agents       AgentsConfig
+	Access       AccessConfig
+	Email        EmailConfig
+	Slack        SlackConfig
+	Telegram     TelegramConfig
+	Discord      DiscordConfig
+	Teams        TeamsConfig
+	Webhooks     []WebhookConfig
+	PagerDuty    PagerDutyConfig
+	Opsgenie     OpsgenieConfig
+	SMS          SMSConfig
+	Ntfy         NtfyConfig
+	Gotify       GotifyConfig
 }
 
 var services []Service
 var latestServicesState []Service // Variável global para armazenar o último estado dos serviços
-var upgrader = websocket.Upgrader{}
+var upgrader = websocket.Upgrader{CheckOrigin: checkWSOrigin, Subprotocols: []string{"bearer"}}
 var serverPort string
-var responseTime int          // Variável para armazenar o tempo de resposta
 var mu sync.Mutex             // Mutex para proteger o acesso concorrente à variável latestServicesState
 var configFile = "config.ini" // Nome do arquivo de configuração
 var lastModTime time.Time     // Armazenará a última modificação do arquivo config.ini
 var pathLog string
+var currentConfig atomic.Pointer[Config] // Última configuração carregada
+var mux = http.NewServeMux()             // Rotas do servidor principal (separadas das rotas de debug)
+var checkInterval time.Duration          // Intervalo padrão entre as verificações de cada serviço
 
 // Função para carregar o arquivo de configuração e iniciar o monitoramento
-func loadConfig(filename string) ([]Service, string, int, string, error) {
+func loadConfig(filename string) (*Config, error) {
 	cfg, err := ini.Load(filename)
 	if err != nil {
-		return nil, "", 0, "", err
+		return nil, err
 	}
+	return parseConfig(cfg)
+}
+
+// Função para interpretar as seções do config.ini já carregado
+func parseConfig(cfg *ini.File) (*Config, error) {
+	var err error
+	setLanguage(cfg.Section("general").Key("language").String())
 
 	// Lendo a porta do servidor
 	port := cfg.Section("general").Key("port").String()
 
-	// Lendo o tempo de resposta
-	responseTime, err := strconv.Atoi(cfg.Section("general").Key("response_time").String())
+	// Lendo o intervalo das verificações (check_interval; o nome antigo response_time, em segundos, continua aceito)
+	general := cfg.Section("general")
+	interval := 10 * time.Second
+	if general.HasKey("check_interval") {
+		if interval, err = parseRange(general.Key("check_interval").String(), 10*time.Second); err != nil {
+			log.Println(tr("Erro ao converter check_interval, usando valor padrão de 10 segundos"))
+			interval = 10 * time.Second
+		}
+	} else if seconds, err := strconv.Atoi(general.Key("response_time").String()); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	} else {
+		log.Println(tr("Erro ao converter response_time, usando valor padrão de 10 segundos"))
+	}
+
+	// Lendo o intervalo dos snapshots completos do WebSocket e do SSE (as mudanças são enviadas na hora)
+	pushInterval, err := parseRange(general.Key("push_interval").String(), time.Minute)
+	if err != nil {
+		log.Println(tr("Erro ao converter push_interval, usando valor padrão de 1 minuto"))
+		pushInterval = time.Minute
+	}
+
+	// Lendo o prazo das verificações (resolução DNS e conexão)
+	timeout, err := parseRange(general.Key("timeout").String(), time.Second)
 	if err != nil {
-		log.Println("Erro ao converter response_time, usando valor padrão de 10 segundos")
-		responseTime = 10
+		log.Println(tr("Erro ao converter timeout, usando valor padrão de 1 segundo"))
+		timeout = time.Second
 	}
 
-	// Lendo a seção de serviços
+	// Lendo a seção de serviços e as seções de grupos ([services.<grupo>])
 	services := []Service{}
 	serviceSection := cfg.Section("services")
-	for i, key := range serviceSection.Keys() {
-		serviceData := strings.Split(key.Value(), ":")
-		if len(serviceData) == 2 {
-			services = append(services, Service{
-				ID:          i + 1, // Atribuindo o número da linha como ID
-				Description: key.Name(),
-				IP:          serviceData[0],
-				Port:        serviceData[1],
-				Status:      "unknown", // Status inicial desconhecido
-			})
-		}
+	services = appendServices(services, serviceSection, "")
+	for _, groupSection := range serviceSection.ChildSections() {
+		group := strings.TrimPrefix(groupSection.Name(), "services.")
+		services = appendServices(services, groupSection, group)
+	}
+	// Serviços descobertos no Kubernetes (seção [kubernetes]), depois dos do config.ini
+	for _, service := range discoveredServices() {
+		service.ID = len(services) + 1
+		services = append(services, service)
 	}
 	pathLog := cfg.Section("general").Key("pathlog").String()
-	return services, port, responseTime, pathLog, nil
+
+	return &Config{
+		Services:     services,
+		Port:         port,
+		Interval:     interval,
+		Timeout:      timeout,
+		PushInterval: pushInterval,
+		PathLog:      pathLog,
+		PublicURL:    strings.TrimSuffix(cfg.Section("general").Key("public_url").String(), "/"),
+		Latency:      loadLatencyWindows(cfg),
+		Sparkline:    loadSparklineSamples(cfg),
+		Workers:      loadWorkers(cfg),
+		Jitter:       loadJitter(cfg),
+		Backoff:      loadBackoffConfig(cfg),
+		HA:           loadHAConfig(cfg, timeout),
+		Kubernetes:   loadKubernetesConfig(cfg),
+		DNS:          loadDNSConfig(cfg),
+		Network:      loadNetworkConfig(cfg),
+		Debug:        loadDebugConfig(cfg),
+		Server:       loadServerConfig(cfg),
+		Branding:     loadBrandingConfig(cfg),
+		UI:           loadUIConfig(cfg),
+		Public:       loadPublicConfig(cfg),
+		Views:        loadViewConfigs(cfg),
+		Kiosk:        loadKioskConfig(cfg),
+		Embed:        loadEmbedConfig(cfg),
+		Auth:         loadAuthConfig(cfg),
+		OIDC:         loadOIDCConfig(cfg),
+		LDAP:         loadLDAPConfig(cfg),
+		TLS:          loadTLSConfig(cfg),
+		Agents:       loadAgentsConfig(cfg),
+		Access:       loadAccessConfig(cfg),
+		Storage:      loadStorageConfig(cfg),
+		TSDB:         loadTSDBConfig(cfg),
+		Alerts:       loadAlertsConfig(cfg),
+		Escalation:   loadEscalationConfig(cfg),
+		Routes:       loadNotifierRoutes(cfg),
+		Schedules:    loadNotifierSchedules(cfg),
+		Messages:     loadMessageTemplates(cfg),
+		Reports:      loadReportConfigs(cfg),
+		Email:        loadEmailConfig(cfg),
+		Slack:        loadSlackConfig(cfg),
+		Telegram:     loadTelegramConfig(cfg),
+		Discord:      loadDiscordConfig(cfg),
+		Teams:        loadTeamsConfig(cfg),
+		Webhooks:     loadWebhookConfigs(cfg),
+		PagerDuty:    loadPagerDutyConfig(cfg),
+		Opsgenie:     loadOpsgenieConfig(cfg),
+		SMS:          loadSMSConfig(cfg),
+		Ntfy:         loadNtfyConfig(cfg),
+		Gotify:       loadGotifyConfig(cfg),
+	}, nil
+}
+
+// Função para aplicar a configuração carregada às variáveis globais
+func applyConfig(config *Config) {
+	serverPort = config.Port
+	checkInterval = config.Interval
+	pathLog = config.PathLog
+	currentConfig.Store(config)
+}
+
+// Função para obter a configuração atual
+func getConfig() *Config {
+	return currentConfig.Load()
+}
+
+// Função para adicionar os serviços de uma seção do config.ini à lista, com IDs sequenciais
+func appendServices(services []Service, section *ini.Section, group string) []Service {
+	for _, key := range section.Keys() {
+		service, err := parseService(key.Name(), key.Value())
+		if err != nil {
+			log.Printf(tr("Serviço [%s] ignorado: %v"), key.Name(), err)
+			continue
+		}
+		service.ID = len(services) + 1 // Atribuindo o número da linha como ID
+		service.Group = group
+		services = append(services, service)
+	}
+	return services
+}
+
+// Função para interpretar a linha de um serviço no formato "<ip:porta|push> [opção=valor ...]"
+func parseService(name, value string) (Service, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return Service{}, fmt.Errorf("endereço não informado")
+	}
+
+	service := Service{
+		Description: name,
+		Type:        "tcp",
+		Status:      "unknown", // Status inicial desconhecido
+		Options:     map[string]string{},
+	}
+	for _, option := range fields[1:] {
+		key, val, ok := strings.Cut(option, "=")
+		if !ok {
+			return Service{}, fmt.Errorf("opção inválida %q", option)
+		}
+		service.Options[key] = val
+	}
+	if value, ok := service.Options["pinned"]; ok {
+		pinned, err := strconv.ParseBool(value)
+		if err != nil {
+			return Service{}, fmt.Errorf("pinned inválido %q", value)
+		}
+		service.Pinned = pinned
+	}
+	if value, ok := service.Options["order"]; ok {
+		order, err := strconv.Atoi(value)
+		if err != nil || order <= 0 {
+			return Service{}, fmt.Errorf("order inválido %q", value)
+		}
+		service.Order = order
+	}
+	if value, ok := service.Options["interval"]; ok {
+		interval, err := parseRange(value, 0)
+		if err != nil {
+			return Service{}, fmt.Errorf("interval inválido %q", value)
+		}
+		service.Interval = interval
+	}
+	if value, ok := service.Options["backoff"]; ok {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return Service{}, fmt.Errorf("backoff inválido %q", value)
+		}
+	}
+	if value, ok := service.Options["family"]; ok && !slices.Contains(addressFamilies, value) {
+		return Service{}, fmt.Errorf("family inválido %q", value)
+	}
+	if value, ok := service.Options["proxy"]; ok && value != "none" {
+		if _, err := parseProxy(value); err != nil {
+			return Service{}, fmt.Errorf("proxy inválido: %v", err)
+		}
+	}
+
+	if err := validateLocations(service); err != nil {
+		return Service{}, err
+	}
+
+	if fields[0] == "push" {
+		if service.Options["agent"] != "" {
+			return Service{}, fmt.Errorf("serviço push não pode ser verificado por uma sonda (agent=)")
+		}
+		service.Type = "push"
+		service.PushToken = service.Options["token"]
+		if service.PushToken == "" {
+			return Service{}, fmt.Errorf("serviço push sem token")
+		}
+		if every, ok := service.Options["expect_every"]; ok {
+			interval, err := parseRange(every, 0)
+			if err != nil {
+				return Service{}, fmt.Errorf("expect_every inválido %q", every)
+			}
+			service.PushInterval = interval
+		}
+		return service, nil
+	}
+
+	if fields[0] == "dns" {
+		// Verificação do próprio DNS: resolve name (opções server= e expect=)
+		service.Type = "dns"
+		if service.Options["name"] == "" {
+			return Service{}, fmt.Errorf("serviço dns sem name")
+		}
+		service.IP = service.Options["name"]
+		return service, nil
+	}
+
+	host, port, err := net.SplitHostPort(fields[0])
+	if err != nil {
+		return Service{}, err
+	}
+	service.IP = host
+	service.Port = port
+	return service, nil
 }
 
-// Função para verificar o status de um serviço (online ou offline) e calcular o tempo de resposta
-func checkService(description, ip, port string) (string, string) {
+// Função para verificar o status de um serviço (online ou offline) e calcular o tempo de resposta; o erro da
+// conexão é o motivo da mudança de status publicada quando o serviço cai. A resolução DNS (pelo cache da seção
+// [dns]) e a conexão dividem o mesmo prazo (timeout), e a conexão é interrompida se o contexto for cancelado
+// (recarga do config.ini ou encerramento do processo). Hosts com IPv6 e IPv4 são conectados conforme a
+// família da seção [network] ou da opção family= do serviço, diretamente ou pelo proxy configurado, a partir
+// da origem configurada (source).
+func checkService(ctx context.Context, service Service) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, getConfig().Timeout)
+	defer cancel()
+
 	start := time.Now() // Início do cálculo do tempo de resposta
-	timeout := time.Second
-	conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip, port), timeout)
-	responseTime := time.Since(start).Milliseconds() // Calcula o tempo de resposta em milissegundos
+	conn, err := connectService(ctx, service)
+	latency := time.Since(start).Milliseconds() // Calcula o tempo de resposta em milissegundos
 
 	if err != nil {
 		// Se houver erro, retornamos "red" como offline e incluímos a descrição do serviço no log
-		// log.Printf("Erro ao verificar serviço [%s] %s:%s - %v", description, ip, port, err)
-		return "red", strconv.FormatInt(responseTime, 10) + " ms"
+		// log.Printf("Erro ao verificar serviço [%s] %s:%s - %v", service.Description, service.IP, service.Port, err)
+		return "red", latency, err
 	}
 	defer conn.Close()
 
 	// Retorna "green" se o serviço está online
-	// log.Printf("Serviço [%s] %s:%s está online. Tempo de resposta: %d ms", description, ip, port, responseTime)
-	return "green", strconv.FormatInt(responseTime, 10) + " ms"
+	// log.Printf("Serviço [%s] %s:%s está online. Tempo de resposta: %d ms", service.Description, service.IP, service.Port, latency)
+	return "green", latency, nil
 }
 
-func monitorServices(services *[]Service) {
-	for {
-		for i := range *services {
-			// Verifica se o arquivo de configuração foi alterado durante a execução
-			if hasConfigFileChanged() {
-				log.Println("Arquivo config.ini modificado, recarregando configurações...")
-				restartServices(services) // Passa o ponteiro de services para a função
-				break
-			}
+// Função para verificar se o serviço possui a tag informada (opção tags=db,producao na linha do serviço)
+func hasTag(service Service, tag string) bool {
+	for _, value := range strings.Split(service.Options["tags"], ",") {
+		if strings.TrimSpace(value) == tag {
+			return true
+		}
+	}
+	return false
+}
 
-			// Verifica o status atual do serviço e calcula o tempo de resposta
-			currentStatus, responseTime := checkService((*services)[i].Description, (*services)[i].IP, (*services)[i].Port)
+// Função para formatar o tempo de resposta exibido no dashboard
+func formatResponseTime(ms int64) string {
+	return strconv.FormatInt(ms, 10) + " ms"
+}
 
-			// Atualiza o status e tempo de resposta apenas se houver mudanças
-			if currentStatus != (*services)[i].Status || responseTime != (*services)[i].ResponseTime {
-				(*services)[i].Status = currentStatus
-				(*services)[i].ResponseTime = responseTime
-			}
+// Função para formatar durações de forma compacta (ex.: 45s, 14m, 2h5m)
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	text := strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// Função para registrar o início da queda e, quando o serviço volta, a duração da queda exibida no dashboard
+func trackOutage(service *Service, status string, at time.Time) {
+	switch {
+	case status == "red" && service.DownSince == nil:
+		service.DownSince = &at
+		openOutage(*service, at)
+	case status == "green" && service.DownSince != nil:
+		service.LastDowntime = formatDuration(at.Sub(*service.DownSince))
+		service.RecoveredAt = &at
+		service.DownSince = nil
+	}
+	if status == "green" {
+		closeOutage(service.Description, at) // Inclui quedas restauradas do banco que estavam em andamento
+	}
+}
+
+// Função para verificar um serviço e publicar o novo estado. Cada serviço é atualizado apenas pelo próprio
+// agendamento, na própria posição da lista. Verificações interrompidas pelo cancelamento do contexto são
+// descartadas, para que o serviço não fique vermelho por causa da recarga ou do encerramento; as que excedem o
+// prazo do contexto contam como falha.
+func checkAndUpdate(ctx context.Context, services []Service, i int) {
+	// Serviços pausados não são verificados; o histórico, a queda e o alerta só mudam ao entrar na pausa
+	if isPaused(services[i]) {
+		previousStatus := services[i].Status
+		if previousStatus != "paused" {
+			services[i].Status = "paused"
+			services[i].ResponseTime = ""
+			recordHistory(services[i], "paused", 0, time.Now())
+			resetAlert(services[i].Description)
+			closeOutage(services[i].Description, time.Now())
+			services[i].DownSince = nil
+			services[i].Acknowledged = nil
+		}
+		services[i].Uptime = computeUptime(services[i], time.Now())
+		services[i].ChangedAt = statusChangedAt(services[i].Description)
+		mu.Lock()
+		latestServicesState[i] = services[i]
+		mu.Unlock()
+		hub.update(services[i])
+		publishTransition(services[i], previousStatus, "paused", tr("monitoring paused"), time.Now())
+		return
+	}
 
-			// Atualiza o último estado dos serviços na variável global
-			mu.Lock()
-			latestServicesState[i] = (*services)[i]
-			mu.Unlock()
+	// Verifica o status atual do serviço e calcula o tempo de resposta
+	var currentStatus, reason string
+	var latency int64
+	if services[i].Type == "push" {
+		// Serviços push não são verificados ativamente: usa o último status recebido
+		var message string
+		currentStatus, latency, message = evaluatePush(services[i])
+		services[i].Message = message
+		if currentStatus == "unknown" {
+			return // Nenhum push recebido ainda
+		}
+		reason = message
+	} else if services[i].Options["agent"] != "" {
+		// Serviços verificados por sondas remotas (e, com local, pela central): combina os resultados dos locais
+		result, ok := evaluateLocations(ctx, services[i])
+		if !ok {
+			return
+		}
+		var locations []locationStatus
+		if len(result.Locations) > 1 {
+			locations = result.Locations
+		}
+		if result.Status == "unknown" {
+			showAgentWaiting(services, i, result.Message, locations) // Sondas desconectadas ou sem resultados ainda
+			return
+		}
+		currentStatus, latency, reason = result.Status, result.LatencyMs, result.Reason
+		services[i].Message = result.Message
+		services[i].Locations = locations
+	} else {
+		var ok bool
+		if currentStatus, latency, reason, ok = probeService(ctx, services[i]); !ok {
+			return
 		}
+	}
+	previousStatus := services[i].Status
+	responseTime := formatResponseTime(latency)
+	recordCheckMetrics(services[i], currentStatus)
+	recordHistory(services[i], currentStatus, latency, time.Now())
+
+	// Atualiza o status e tempo de resposta apenas se houver mudanças
+	if currentStatus != services[i].Status || responseTime != services[i].ResponseTime {
+		services[i].Status = currentStatus
+		services[i].ResponseTime = responseTime
+		services[i].LatencyMs = latency
+	}
+
+	trackOutage(&services[i], currentStatus, time.Now())
+	services[i].Uptime = computeUptime(services[i], time.Now())
+	services[i].ChangedAt = statusChangedAt(services[i].Description)
+	services[i].Latency = computeLatencyPercentiles(services[i], getConfig().Latency, time.Now())
+	services[i].Sparkline = recentLatencies(services[i], getConfig().Sparkline)
+	services[i].Silenced = isSilenced(services[i], time.Now())
+
+	// Notifica os canais configurados quando o serviço cai (após alert_after_failures falhas) ou volta
+	trackAlert(services[i], currentStatus, time.Now())
+	services[i].Acknowledged = acknowledgmentOf(services[i].Description)
+
+	// Atualiza o último estado dos serviços na variável global e envia a mudança aos clientes WebSocket
+	mu.Lock()
+	latestServicesState[i] = services[i]
+	mu.Unlock()
+	hub.update(services[i])
+	publishTransition(services[i], previousStatus, currentStatus, reason, time.Now())
+}
 
-		// Espera antes de realizar a próxima verificação
-		time.Sleep(time.Duration(responseTime) * time.Second)
+// Função para verificar ativamente um serviço tcp ou dns, retornando o status, o tempo de resposta e o motivo;
+// ok é false quando a verificação foi interrompida (recarga do config.ini ou encerramento do processo). Usada
+// pela instância central e pelas sondas remotas.
+func probeService(ctx context.Context, service Service) (status string, latency int64, reason string, ok bool) {
+	var err error
+	if service.Type == "dns" {
+		status, latency, err = checkDNS(ctx, service)
+		reason = tr("name resolved")
+	} else {
+		status, latency, err = checkService(ctx, service)
+		reason = tr("connection established")
 	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return "", 0, "", false
+	}
+	if err != nil {
+		reason = err.Error()
+	}
+	return status, latency, reason, true
 }
 
 func hasConfigFileChanged() bool {
 	info, err := os.Stat(configFile)
 	if err != nil {
-		log.Println("Erro ao verificar arquivo de configuração:", err)
+		log.Println(tr("Erro ao verificar arquivo de configuração:"), err)
 		return false
 	}
 
@@ -142,11 +563,15 @@ func restartServices(services *[]Service) {
 	defer mu.Unlock()
 
 	// Recarregar as configurações
-	var err error
-	*services, serverPort, responseTime, pathLog, err = loadConfig(configFile)
+	config, err := loadConfig(configFile)
 	if err != nil {
-		log.Fatalf("Erro ao recarregar arquivo de configuração: %v", err)
+		log.Fatalf(tr("Erro ao recarregar arquivo de configuração: %v"), err)
 	}
+	auditServiceChanges(*services, config.Services)
+	restoreServiceStates(config.Services, *services, config)
+	*services = config.Services
+	applyConfig(config)
+	recordAudit("system", "", "config.reload", configFile, nil, nil)
 
 	// Atualiza o último tempo de modificação
 	info, _ := os.Stat(configFile)
@@ -155,64 +580,49 @@ func restartServices(services *[]Service) {
 	latestServicesState = make([]Service, len(*services))
 	copy(latestServicesState, *services)
 
-	log.Println("Configurações recarregadas com sucesso!")
+	log.Println(tr("Configurações recarregadas com sucesso!"))
 }
 
 // WebSocket handler para enviar dados para o front-end
 func wsHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	// Recusa a conexão educadamente se o limite de clientes foi atingido
+	if !acquireWSClient() {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "Limite de clientes WebSocket atingido", http.StatusTooManyRequests)
+		return
+	}
+	defer releaseWSClient()
+
+	// Compressão permessage-deflate, usada quando o navegador também a suporta
+	level := getConfig().Server.WSCompress
+	wsUpgrader := upgrader
+	wsUpgrader.EnableCompression = level > 0
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Println("Erro ao abrir WebSocket:", err)
+		log.Println(tr("Erro ao abrir WebSocket:"), err)
 		return
 	}
 	defer conn.Close()
-
-	// Envia o último estado dos serviços armazenado em memória inicialmente
-	mu.Lock()
-	if len(latestServicesState) > 0 {
-		// log.Println("Enviando último estado armazenado para o WebSocket")
-		if err := conn.WriteJSON(latestServicesState); err != nil {
-			log.Println("Erro ao enviar último estado:", err)
-			mu.Unlock()
-			return
-		}
+	if level > 0 {
+		conn.SetCompressionLevel(level)
 	}
-	mu.Unlock()
 
-	// Continua enviando atualizações periódicas conforme o intervalo definido no config.ini
-	ticker := time.NewTicker(time.Duration(responseTime) * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			mu.Lock()
-			// Envia o último estado dos serviços armazenado em memória
-			if len(latestServicesState) > 0 {
-				// log.Println("Enviando atualizações periódicas para o WebSocket")
-				if err := conn.WriteJSON(latestServicesState); err != nil {
-					log.Println("Erro ao enviar atualizações periódicas:", err)
-					mu.Unlock()
-					return
-				}
-			}
-			mu.Unlock()
-		case <-r.Context().Done():
-			// O WebSocket foi fechado
-			log.Println("Conexão WebSocket fechada.")
-			return
-		}
+	// Recebe do hub um snapshot completo (ou, ao reconectar com ?since=, os deltas perdidos) e, a seguir, as
+	// mudanças e os snapshots periódicos
+	// Os quiosques (/ws?kiosk) recebem a visão atual e acompanham as trocas de visão feitas pelo servidor
+	filter, kiosk := queryFilter(r), r.URL.Query().Has("kiosk")
+	if kiosk {
+		filter = restrictFilter(kioskFilter(), visibleGroups(r))
 	}
-}
-
-// Handler para a página inicial
-func indexHandler(w http.ResponseWriter, r *http.Request) {
-	tmpl, err := template.ParseFiles("index.html")
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	client := hub.register(filter, resumeSeq(r))
+	defer hub.unregister(client)
+	if kiosk {
+		hub.joinKiosk(client)
 	}
-	tmpl.Execute(w, nil)
+
+	closed := make(chan struct{})
+	go readPump(conn, client, closed)
+	writePump(conn, client, closed)
 }
 
 // Função para criar um arquivo de log diário e também imprimir no console
@@ -224,14 +634,14 @@ func setupLog(pathLog string) {
 	if _, err := os.Stat(logDir); os.IsNotExist(err) {
 		err := os.MkdirAll(logDir, 0755) // Use MkdirAll para criar diretórios pai, se necessário
 		if err != nil {
-			log.Fatalf("Erro ao criar diretório de logs: %v", err)
+			log.Fatalf(tr("Erro ao criar diretório de logs: %v"), err)
 		}
 	}
 
 	logFile := filepath.Join(logDir, currentTime+".log")
 	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		log.Fatalf("Erro ao abrir arquivo de log: %v", err)
+		log.Fatalf(tr("Erro ao abrir arquivo de log: %v"), err)
 	}
 
 	// Cria um MultiWriter para escrever tanto no arquivo quanto no console
@@ -250,7 +660,7 @@ func setupLog(pathLog string) {
 func cleanupOldLogs(logDir string, maxDays int) {
 	files, err := os.ReadDir(logDir)
 	if err != nil {
-		log.Println("Erro ao ler diretório de logs:", err)
+		log.Println(tr("Erro ao ler diretório de logs:"), err)
 		return
 	}
 
@@ -260,47 +670,220 @@ func cleanupOldLogs(logDir string, maxDays int) {
 		filePath := filepath.Join(logDir, file.Name())
 		info, err := os.Stat(filePath)
 		if err != nil {
-			log.Println("Erro ao obter informações do arquivo:", file.Name())
+			log.Println(tr("Erro ao obter informações do arquivo:"), file.Name())
 			continue
 		}
 
-		// Remove arquivos mais antigos que a data limite
-		if info.ModTime().Before(threshold) {
+		// Remove arquivos mais antigos que a data limite (o log de auditoria é mantido)
+		if info.ModTime().Before(threshold) && file.Name() != "audit.log" {
 			if err := os.Remove(filePath); err != nil {
-				log.Println("Erro ao remover arquivo:", file.Name())
+				log.Println(tr("Erro ao remover arquivo:"), file.Name())
 			} else {
-				log.Println("Arquivo removido:", file.Name())
+				log.Println(tr("Arquivo removido:"), file.Name())
 			}
 		}
 	}
 }
 
 func main() {
+	passwordToHash := flag.String("hash-password", "", "Gera o hash bcrypt da senha informada para a seção [users] e encerra")
+	caInit := flag.Bool("ca-init", false, "Cria a CA interna usada no mTLS das sondas (em -pki-dir) e encerra")
+	issueCert := flag.String("issue-cert", "", "Emite um certificado com o nome informado, assinado pela CA interna, e encerra")
+	certHosts := flag.String("cert-hosts", "", "Nomes/IPs do certificado emitido por -issue-cert, separados por vírgula")
+	serverCert := flag.Bool("server-cert", false, "Emite com -issue-cert o certificado do servidor central (porta das sondas) em vez do de uma sonda")
+	pkiDir := flag.String("pki-dir", "pki", "Diretório da CA interna e dos certificados emitidos")
+	backupFile := flag.String("backup", "", "Grava no arquivo informado (zip) o backup do histórico gravado no banco e encerra")
+	agentMode := flag.Bool("agent", false, "Executa como sonda remota: verifica os serviços atribuídos pela central (-server) e envia os resultados")
+	agentServer := flag.String("server", "", "Endereço da porta das sondas da central usado por -agent (wss://central:8443)")
+	agentCert := flag.String("agent-cert", "pki/agent.pem", "Certificado da sonda emitido por -issue-cert, usado por -agent")
+	agentKey := flag.String("agent-key", "pki/agent.key", "Chave do certificado da sonda, usada por -agent")
+	restoreFile := flag.String("restore", "", "Restaura o backup informado (zip) ao iniciar, substituindo o histórico desta instância no banco")
+	flag.Parse()
+
+	if *caInit {
+		if err := createCA(*pkiDir); err != nil {
+			log.Fatal("Erro ao criar a CA:", err)
+		}
+		fmt.Println("CA criada em", *pkiDir)
+		return
+	}
+
+	if *issueCert != "" {
+		if err := issueCertificate(*pkiDir, *issueCert, strings.Split(*certHosts, ","), *serverCert); err != nil {
+			log.Fatal("Erro ao emitir o certificado:", err)
+		}
+		fmt.Printf("Certificado emitido: %s/%s.pem e %s/%s.key\n", *pkiDir, *issueCert, *pkiDir, *issueCert)
+		return
+	}
+
+	if *agentMode {
+		runAgent(*agentServer, *agentCert, *agentKey, filepath.Join(*pkiDir, "ca.pem"))
+		return
+	}
+
+	if *passwordToHash != "" {
+		hash, err := hashPassword(*passwordToHash)
+		if err != nil {
+			log.Fatal("Erro ao gerar hash da senha:", err)
+		}
+		fmt.Println(hash)
+		return
+	}
 
 	// Carregar a configuração inicialmente
-	var err error
-	services, serverPort, responseTime, pathLog, err = loadConfig(configFile)
+	config, err := loadConfig(configFile)
 	if err != nil {
 		log.Fatal("Erro ao carregar arquivo de configuração:", err)
 	}
 
+	if *backupFile != "" {
+		if err := backupFromStorage(*backupFile, config.Storage); err != nil {
+			log.Fatal(tr("Erro ao gravar o backup:"), err)
+		}
+		fmt.Println("Backup gravado em", *backupFile)
+		return
+	}
+
+	services = config.Services
+	applyConfig(config)
+
+	configLoaded.Store(true)
+
 	// Configurar logs diários
 	setupLog(pathLog)
+	setupAuditLog(pathLog)
+	if *restoreFile != "" {
+		if err := restoreBackup(*restoreFile, config.Storage); err != nil {
+			log.Fatal(tr("Erro ao restaurar o backup:"), err)
+		}
+	}
+	haStandby.Store(config.HA.Enabled) // Até a eleição, a instância do par não verifica nem mantém o banco
+	setupStorage(config.Storage)
+	restoreServiceStates(services, nil, config)
+	go runTSDBExporter()
+	go runReports()
+	go runHub()
+	go runKiosk()
+	setupTransitions()
 
 	// Inicializa o estado mais recente dos serviços em memória
 	latestServicesState = make([]Service, len(services))
 	copy(latestServicesState, services)
+	// Registra no hub o estado inicial, base dos resumos dos grupos e do estado agregado enviados nos deltas
+	hub.publish(snapshotServices())
 
 	// Armazena o tempo de modificação inicial do arquivo config.ini
 	info, _ := os.Stat(configFile)
 	lastModTime = info.ModTime()
 
-	// Iniciar o monitoramento dos serviços em uma goroutine
-	go monitorServices(&services) // Passa o ponteiro de services para o monitoramento
+	// Encerrar de forma ordenada ao receber SIGTERM/SIGINT; um segundo sinal encerra imediatamente
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Eleger o líder do par de alta disponibilidade (seção [ha]) antes de iniciar as verificações
+	setupHA(ctx, config.HA)
+	startKubernetesDiscovery(ctx, config.Kubernetes)
+
+	// Iniciar o agendamento dos serviços e o acompanhamento do config.ini em uma goroutine
+	schedulerStarted.Store(time.Now().UnixNano())
+	monitorDone := make(chan struct{})
+	go func() {
+		monitorServices(ctx, &services) // Passa o ponteiro de services para o monitoramento
+		close(monitorDone)
+	}()
+
+	// Montar o schema do endpoint GraphQL
+	graphqlSchema, err = buildGraphQLSchema()
+	if err != nil {
+		log.Fatal(tr("Erro ao montar schema GraphQL:"), err)
+	}
 
 	// Iniciar o servidor na porta definida no arquivo .ini
-	http.HandleFunc("/ws", wsHandler)
-	http.HandleFunc("/", indexHandler)
-	log.Printf("Servidor iniciado na porta :%s\n", serverPort)
-	log.Fatal(http.ListenAndServe(":"+serverPort, nil))
+	mux.HandleFunc("/ws", wsHandler)
+	handleAPI("GET", "/events", "Stream Server-Sent Events com o snapshot e os deltas do WebSocket", eventsHandler, "view", "group", "tag", "service")
+	handleAPI("GET", "/status.json", "Último estado dos serviços (o mesmo do WebSocket)", statusJSONHandler, "pretty", "sort", "fields")
+	handleAPI("GET", "/api/summary", "Resumo compacto dos serviços (nome, status e última mudança)", summaryHandler, "view", "group", "tag", "service")
+	handleAPI("GET", "/metrics", "Métricas no formato do Prometheus", metricsHandler)
+	handleAPI("POST", "/api/services/{id}/pause", "Pausa o monitoramento de um serviço", pauseServiceHandler)
+	handleAPI("POST", "/api/services/{id}/resume", "Retoma o monitoramento de um serviço", resumeServiceHandler)
+	handleAPI("GET", "/api/outages", "Quedas registradas, com início, fim e duração", listOutagesHandler, "service", "group", "from", "to", "open", "limit")
+	handleAPI("POST", "/api/services/{id}/ack", "Reconhece a queda de um serviço vermelho (com comentário opcional)", ackServiceHandler)
+	handleAPI("DELETE", "/api/services/{id}/ack", "Desfaz o reconhecimento da queda de um serviço", unackServiceHandler)
+	handleAPI("POST", "/api/groups/{group}/pause", "Pausa o monitoramento de um grupo", pauseGroupHandler)
+	handleAPI("POST", "/api/groups/{group}/resume", "Retoma o monitoramento de um grupo", resumeGroupHandler)
+	handleAPI("GET", "/api/services/{id}/sla", "Disponibilidade, quedas, MTTR e tempo fora do ar de um serviço", slaHandler, "range")
+	handleAPI("GET", "/api/services/{id}/history", "Transições de status e amostras de tempo de resposta agregadas", historyHandler, "from", "to", "resolution")
+	handleAPI("GET", "/api/services/{id}/timeseries", "Série de tempo de resposta e status em intervalos fixos, pronta para gráficos", timeseriesHandler, "range", "step")
+	handleAPI("GET", "/api/services/{id}/history/export", "Exporta o histórico de um serviço em CSV ou XLSX", exportHistoryHandler, "from", "to", "resolution", "format")
+	handleAPI("GET", "/api/export/status", "Exporta o estado atual dos serviços em CSV ou XLSX", exportStatusHandler, "format")
+	handleAPI("GET", "/favicon.svg", "Favicon na cor do estado agregado dos serviços (verde, amarelo ou vermelho)", statusFaviconHandler, "view", "group", "tag", "service")
+	handleAPI("GET", "/badge/{file}", "Badge SVG com o status de um serviço (ID ou descrição, ex.: /badge/DBAccess.svg)", badgeHandler)
+	handleAPI("GET", "/feed.xml", "Feed RSS (ou Atom com ?format=atom) das mudanças de status", feedHandler, "format")
+	handleAPI("POST", "/graphql", "Consulta GraphQL sobre serviços, grupos e histórico", graphqlHandler)
+	handleAPI("GET", "/graphql", "Consulta GraphQL via query string", graphqlHandler, "query", "operationName")
+	handleAPI("GET", "/grafana/{$}", "Teste de conexão do datasource JSON do Grafana", grafanaTestHandler)
+	handleAPI("POST", "/grafana/search", "Alvos disponíveis para o datasource JSON do Grafana", grafanaSearchHandler)
+	handleAPI("POST", "/grafana/query", "Séries de tempo de resposta/status para o Grafana", grafanaQueryHandler)
+	handleAPI("POST", "/grafana/annotations", "Quedas dos serviços como anotações do Grafana", grafanaAnnotationsHandler)
+	handleAPI("POST", "/api/push/{token}", "Recebe o status de um serviço do tipo push", pushHandler, "status")
+	handleAPI("GET", "/api/agents", "Sondas remotas conectadas à central e o estado de cada conexão", listAgentsHandler)
+	handleAPI("GET", "/api/views", "Visões nomeadas do dashboard (seções [view.<nome>]), exibidas em /view/<nome>", listViewsHandler)
+	handleAPI("GET", "/api/groups", "Grupos com o resumo de cada um: x de y online, pior status e pior tempo de resposta", groupsHandler)
+	handleAPI("GET", "/api/overall", "Status consolidado (pior status) e contagens por status", overallHandler, "group", "service", "strict")
+	handleAPI("GET", "/api/reports/sla", "Relatório de disponibilidade por grupo em HTML ou PDF", slaReportHandler, "range", "group", "format")
+	handleAPI("GET", "/api/reports/{name}", "Pré-visualiza o texto de um relatório periódico com os dados atuais", previewReportHandler)
+	handleAPI("POST", "/api/reports/{name}/send", "Envia um relatório periódico imediatamente", sendReportHandler)
+	handleAPI("GET", "/api/incidents", "Incidentes (mais recentes primeiro), com as quedas dos serviços afetados", listIncidentsHandler, "status", "limit")
+	handleAPI("GET", "/api/incidents/{id}", "Consulta um incidente", getIncidentHandler)
+	handleAPI("POST", "/api/incidents", "Abre um incidente com título, serviços afetados e mensagem inicial", createIncidentHandler)
+	handleAPI("PUT", "/api/incidents/{id}", "Altera o título ou os serviços afetados de um incidente", editIncidentHandler)
+	handleAPI("POST", "/api/incidents/{id}/updates", "Publica uma atualização no incidente (status resolved encerra o incidente)", updateIncidentHandler)
+	handleAPI("DELETE", "/api/incidents/{id}", "Apaga um incidente registrado por engano", deleteIncidentHandler)
+	handleAPI("GET", "/api/annotations", "Anotações de deploys e manutenções (mais recentes primeiro)", listAnnotationsHandler, "service", "group", "tag", "from", "to", "limit")
+	handleAPI("POST", "/api/annotations", "Registra uma anotação (deploy, manutenção) para correlacionar com as quedas", createAnnotationHandler)
+	handleAPI("DELETE", "/api/annotations/{id}", "Apaga uma anotação", deleteAnnotationHandler)
+	handleAPI("GET", "/api/silences", "Silêncios ativos (notificações suspensas)", listSilencesHandler)
+	handleAPI("POST", "/api/silences", "Silencia as notificações de um serviço, grupo ou tag por um período", createSilenceHandler)
+	handleAPI("DELETE", "/api/silences/{id}", "Encerra um silêncio antes do prazo", deleteSilenceHandler)
+	handleAPI("GET", "/api/ui-config", "Tema, cores e escala das fontes do dashboard (seção [ui])", uiConfigHandler)
+	handleAPI("GET", "/api/me", "Usuário, papel e permissões da sessão atual", meHandler)
+	handleAPI("GET", "/api/tokens", "Lista os tokens de API (sem os valores)", listTokensHandler)
+	handleAPI("POST", "/api/tokens", "Emite um token de API com escopo read, write ou admin", createTokenHandler)
+	handleAPI("DELETE", "/api/tokens/{name}", "Revoga um token de API emitido pela API", deleteTokenHandler)
+	handleAPI("GET", "/api/audit", "Log de auditoria das ações administrativas (mais recentes primeiro)", auditHandler, "user", "action", "from", "to", "limit")
+	handleAPI("GET", "/api/backup", "Backup do histórico e do estado do monitor (zip), restaurado com a opção -restore", backupHandler)
+	handleAPI("GET", "/healthz", "Liveness do processo de monitoramento", healthzHandler)
+	handleAPI("GET", "/readyz", "Readiness: configuração carregada e ciclos de verificação recentes", readyzHandler)
+	mux.HandleFunc("GET /api/openapi.json", openAPIHandler)
+	mux.HandleFunc("GET /api/docs", swaggerUIHandler)
+	mux.HandleFunc("GET /auth/login", oidcLoginHandler)
+	mux.HandleFunc("GET /auth/callback", oidcCallbackHandler)
+	mux.HandleFunc("/auth/logout", logoutHandler)
+	mux.HandleFunc("GET /view/{name}", viewHandler)
+	mux.HandleFunc("GET /kiosk", kioskHandler)
+	mux.HandleFunc("GET /embed", embedHandler)
+	mux.HandleFunc("GET /static/", staticHandler)
+	mux.HandleFunc("GET /favicon.ico", faviconHandler)
+	mux.HandleFunc("/", indexHandler)
+	// Registrar a página de status pública (sem autenticação) e a sua porta separada, se habilitada
+	startPublicServer(config.Public)
+	// Iniciar o servidor de debug (expvar/pprof) em uma porta administrativa separada, se habilitado
+	startDebugServer(config.Debug)
+	// Iniciar a porta com TLS mútuo para as sondas remotas, se habilitada
+	startAgentServer(config.Agents)
+
+	go cleanupLimiters()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- listenAndServe(config.TLS, corsMiddleware(accessMiddleware(rateLimitMiddleware(authMiddleware(mux)))))
+	}()
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	case <-ctx.Done():
+		stop()
+		gracefulShutdown(monitorDone)
+	}
 }
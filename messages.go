@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/ini.v1"
+)
+
+// Templates das mensagens de um canal (title_template e message_template)
+type MessageTemplates struct {
+	Title   *template.Template // Substitui o título (ex.: assunto do e-mail)
+	Message *template.Template // Substitui o texto principal da mensagem
+}
+
+// Dados disponíveis nos templates das mensagens: os campos do payload dos webhooks e os textos padrão
+type messageData struct {
+	webhookPayload
+	Duration string // Tempo no status anterior, formatado (ex.: 14m)
+	Title    string // Título padrão
+	Text     string // Texto principal padrão
+}
+
+// Função para ler os templates das seções dos canais (title_template, message_template ou message_template_file)
+func loadMessageTemplates(cfg *ini.File) map[string]MessageTemplates {
+	templates := map[string]MessageTemplates{}
+	for _, name := range notifierSectionNames(cfg) {
+		section, err := cfg.GetSection(name)
+		if err != nil {
+			continue
+		}
+		message := section.Key("message_template").String()
+		if file := section.Key("message_template_file").String(); file != "" {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				log.Printf(tr("Erro ao ler message_template_file da seção [%s], usando a mensagem padrão: %v\n"), name, err)
+			} else {
+				message = string(data)
+			}
+		}
+
+		var config MessageTemplates
+		config.Title = parseMessageTemplate(name, "title_template", section.Key("title_template").String())
+		config.Message = parseMessageTemplate(name, "message_template", message)
+		if config.Title != nil || config.Message != nil {
+			templates[name] = config
+		}
+	}
+	return templates
+}
+
+// Função para compilar um template de mensagem (nil se vazio ou inválido)
+func parseMessageTemplate(section, key, text string) *template.Template {
+	if text == "" {
+		return nil
+	}
+	// Permite quebras de linha escritas como \n no config.ini
+	text = strings.ReplaceAll(text, `\n`, "\n")
+	tmpl, err := template.New(section + "." + key).Funcs(webhookTemplateFuncs).Parse(text)
+	if err != nil {
+		log.Printf(tr("%s inválido na seção [%s], usando a mensagem padrão: %v\n"), key, section, err)
+		return nil
+	}
+	return tmpl
+}
+
+// Função para aplicar os templates do canal à mudança de status; em caso de erro, mantém o texto padrão
+func (templates MessageTemplates) apply(change stateChange) stateChange {
+	data := messageData{
+		webhookPayload: newWebhookPayload(change),
+		Duration:       formatDuration(change.Duration),
+		Title:          change.title(),
+		Text:           change.durationText(),
+	}
+	render := func(tmpl *template.Template) (string, bool) {
+		if tmpl == nil {
+			return "", false
+		}
+		buffer := &bytes.Buffer{}
+		if err := tmpl.Execute(buffer, data); err != nil {
+			log.Printf(tr("Erro no template %s, usando a mensagem padrão: %v\n"), tmpl.Name(), err)
+			return "", false
+		}
+		return strings.TrimSpace(buffer.String()), true
+	}
+
+	if title, ok := render(templates.Title); ok {
+		change.CustomTitle = title
+	}
+	if text, ok := render(templates.Message); ok {
+		change.CustomText = text
+	}
+	return change
+}
+
+// Função para aplicar os templates configurados para o canal, se houver
+func applyMessageTemplates(config *Config, n notifier, change stateChange) stateChange {
+	if templates, ok := config.Messages[channelName(n)]; ok {
+		return templates.apply(change)
+	}
+	return change
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Contadores de verificações de um serviço
+type checkCounters struct {
+	Group       string
+	Success     int64
+	Failure     int64
+	Duration    time.Duration // Duração da última verificação, incluindo a espera por um worker
+	Overruns    int64         // Verificações que levaram mais que o intervalo do serviço
+	Overrunning bool          // A última verificação levou mais que o intervalo
+}
+
+var metricsMu sync.Mutex                      // Mutex para proteger os contadores das métricas
+var checkCounts = map[string]*checkCounters{} // Contadores por serviço, indexados pela descrição
+var checkCycles int64                         // Quantidade de verificações de serviços concluídas
+var lastCycleDuration time.Duration           // Duração da última verificação, incluindo a espera por um worker
+var checkOverruns int64                       // Verificações que levaram mais que o intervalo do serviço
+var wsClients atomic.Int64                    // Quantidade de clientes WebSocket conectados
+var startTime = time.Now()                    // Momento em que o processo foi iniciado
+
+// Função para obter os contadores de um serviço, criando-os na primeira verificação (deve ser chamada com
+// metricsMu bloqueado)
+func countersOf(service Service) *checkCounters {
+	counters, ok := checkCounts[service.Description]
+	if !ok {
+		counters = &checkCounters{}
+		checkCounts[service.Description] = counters
+	}
+	counters.Group = service.Group
+	return counters
+}
+
+// Função para registrar o resultado de uma verificação nos contadores
+func recordCheckMetrics(service Service, status string) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	counters := countersOf(service)
+	if status == "green" {
+		counters.Success++
+	} else {
+		counters.Failure++
+	}
+}
+
+// Função para registrar a conclusão da verificação de um serviço (cada serviço tem o próprio agendamento); a
+// duração inclui a espera por um worker livre
+func recordCycleMetrics(duration time.Duration) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	checkCycles++
+	lastCycleDuration = duration
+	lastCycleAt.Store(time.Now().UnixNano())
+}
+
+// Função para registrar a duração da verificação de um serviço, retornando se ela excedeu o intervalo e se isso
+// mudou em relação à verificação anterior
+func recordCheckDuration(service Service, duration, interval time.Duration) (overrun, changed bool) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	counters := countersOf(service)
+	counters.Duration = duration
+	overrun = duration > interval
+	if overrun {
+		counters.Overruns++
+		checkOverruns++
+	}
+	changed = overrun != counters.Overrunning
+	counters.Overrunning = overrun
+	return overrun, changed
+}
+
+// Função para escapar valores de labels no formato de exposição do Prometheus
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// Handler para exportar as métricas no formato texto do Prometheus
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	snapshot := snapshotServices()
+	var sb strings.Builder
+
+	sb.WriteString("# HELP service_up Indica se o serviço está online (1) ou offline (0).\n")
+	sb.WriteString("# TYPE service_up gauge\n")
+	for _, service := range snapshot {
+		if service.Status != "green" && service.Status != "red" {
+			continue // Serviços pausados ou ainda não verificados não são exportados
+		}
+		up := 0
+		if service.Status == "green" {
+			up = 1
+		}
+		fmt.Fprintf(&sb, "service_up{name=\"%s\",group=\"%s\"} %d\n", escapeLabel(service.Description), escapeLabel(service.Group), up)
+	}
+
+	sb.WriteString("# HELP service_response_time_ms Tempo de resposta da última verificação em milissegundos.\n")
+	sb.WriteString("# TYPE service_response_time_ms gauge\n")
+	for _, service := range snapshot {
+		if service.Status != "green" && service.Status != "red" {
+			continue
+		}
+		fmt.Fprintf(&sb, "service_response_time_ms{name=\"%s\",group=\"%s\"} %d\n", escapeLabel(service.Description), escapeLabel(service.Group), service.LatencyMs)
+	}
+
+	sb.WriteString("# HELP service_location_up Indica se o serviço está online (1) ou offline (0) em cada local que o verifica.\n")
+	sb.WriteString("# TYPE service_location_up gauge\n")
+	for _, service := range snapshot {
+		for _, location := range service.Locations {
+			if location.Status != "green" && location.Status != "red" {
+				continue // Sonda desconectada ou sem resultados
+			}
+			up := 0
+			if location.Status == "green" {
+				up = 1
+			}
+			fmt.Fprintf(&sb, "service_location_up{name=\"%s\",group=\"%s\",location=\"%s\"} %d\n", escapeLabel(service.Description), escapeLabel(service.Group), escapeLabel(location.Location), up)
+		}
+	}
+
+	sb.WriteString("# HELP service_paused Indica se o monitoramento do serviço está pausado.\n")
+	sb.WriteString("# TYPE service_paused gauge\n")
+	for _, service := range snapshot {
+		paused := 0
+		if service.Status == "paused" {
+			paused = 1
+		}
+		fmt.Fprintf(&sb, "service_paused{name=\"%s\",group=\"%s\"} %d\n", escapeLabel(service.Description), escapeLabel(service.Group), paused)
+	}
+
+	metricsMu.Lock()
+	names := make([]string, 0, len(checkCounts))
+	for name := range checkCounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sb.WriteString("# HELP service_checks_total Quantidade de verificações realizadas por resultado.\n")
+	sb.WriteString("# TYPE service_checks_total counter\n")
+	for _, name := range names {
+		counters := checkCounts[name]
+		fmt.Fprintf(&sb, "service_checks_total{name=\"%s\",group=\"%s\",result=\"success\"} %d\n", escapeLabel(name), escapeLabel(counters.Group), counters.Success)
+		fmt.Fprintf(&sb, "service_checks_total{name=\"%s\",group=\"%s\",result=\"failure\"} %d\n", escapeLabel(name), escapeLabel(counters.Group), counters.Failure)
+	}
+
+	sb.WriteString("# HELP service_check_duration_seconds Duração da última verificação, incluindo a espera por um worker livre.\n")
+	sb.WriteString("# TYPE service_check_duration_seconds gauge\n")
+	for _, name := range names {
+		counters := checkCounts[name]
+		fmt.Fprintf(&sb, "service_check_duration_seconds{name=\"%s\",group=\"%s\"} %g\n", escapeLabel(name), escapeLabel(counters.Group), counters.Duration.Seconds())
+	}
+
+	sb.WriteString("# HELP service_check_overruns_total Verificações que levaram mais que o intervalo do serviço.\n")
+	sb.WriteString("# TYPE service_check_overruns_total counter\n")
+	for _, name := range names {
+		counters := checkCounts[name]
+		fmt.Fprintf(&sb, "service_check_overruns_total{name=\"%s\",group=\"%s\"} %d\n", escapeLabel(name), escapeLabel(counters.Group), counters.Overruns)
+	}
+
+	sb.WriteString("# HELP monitor_check_overruns_total Verificações de todos os serviços que levaram mais que o intervalo.\n")
+	sb.WriteString("# TYPE monitor_check_overruns_total counter\n")
+	fmt.Fprintf(&sb, "monitor_check_overruns_total %d\n", checkOverruns)
+
+	sb.WriteString("# HELP monitor_check_cycles_total Quantidade de verificações de serviços concluídas.\n")
+	sb.WriteString("# TYPE monitor_check_cycles_total counter\n")
+	fmt.Fprintf(&sb, "monitor_check_cycles_total %d\n", checkCycles)
+
+	sb.WriteString("# HELP monitor_last_cycle_duration_seconds Duração da última verificação, incluindo a espera por um worker livre.\n")
+	sb.WriteString("# TYPE monitor_last_cycle_duration_seconds gauge\n")
+	fmt.Fprintf(&sb, "monitor_last_cycle_duration_seconds %g\n", lastCycleDuration.Seconds())
+	metricsMu.Unlock()
+
+	sb.WriteString("# HELP monitor_services Quantidade de serviços configurados.\n")
+	sb.WriteString("# TYPE monitor_services gauge\n")
+	fmt.Fprintf(&sb, "monitor_services %d\n", len(snapshot))
+
+	sb.WriteString("# HELP monitor_leader Indica se esta instância é a líder do par de alta disponibilidade (sempre 1 sem a seção [ha]).\n")
+	sb.WriteString("# TYPE monitor_leader gauge\n")
+	leader := 0
+	if isLeader() {
+		leader = 1
+	}
+	fmt.Fprintf(&sb, "monitor_leader %d\n", leader)
+
+	sb.WriteString("# HELP monitor_websocket_clients Quantidade de clientes WebSocket conectados.\n")
+	sb.WriteString("# TYPE monitor_websocket_clients gauge\n")
+	fmt.Fprintf(&sb, "monitor_websocket_clients %d\n", wsClients.Load())
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	sb.WriteString("# HELP monitor_goroutines Quantidade de goroutines do processo.\n")
+	sb.WriteString("# TYPE monitor_goroutines gauge\n")
+	fmt.Fprintf(&sb, "monitor_goroutines %d\n", runtime.NumGoroutine())
+
+	sb.WriteString("# HELP monitor_memory_alloc_bytes Memória alocada no heap.\n")
+	sb.WriteString("# TYPE monitor_memory_alloc_bytes gauge\n")
+	fmt.Fprintf(&sb, "monitor_memory_alloc_bytes %d\n", mem.Alloc)
+
+	sb.WriteString("# HELP monitor_start_time_seconds Momento de início do processo (unix).\n")
+	sb.WriteString("# TYPE monitor_start_time_seconds gauge\n")
+	fmt.Fprintf(&sb, "monitor_start_time_seconds %d\n", startTime.Unix())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(sb.String()))
+}
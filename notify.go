@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Mudança de status de um serviço enviada aos canais de notificação
+type stateChange struct {
+	Service  Service       // Serviço já com o novo status e tempo de resposta
+	From     string        // Status anterior (green ou red)
+	To       string        // Novo status (green ou red)
+	Time     time.Time     // Momento da mudança
+	Duration time.Duration // Tempo em que o serviço permaneceu no status anterior
+
+	FirstFailure time.Time // Primeira falha da queda (na recuperação, o início do tempo fora do ar)
+	Repeat       bool      // Repetição da notificação de uma queda ainda não reconhecida (ou escalonamento)
+	Channels     []string  // Canais acionados pela política de escalonamento (nil = todos os habilitados)
+
+	CustomTitle string // Título gerado pelo title_template do canal (vazio = padrão)
+	CustomText  string // Texto principal gerado pelo message_template do canal (vazio = padrão)
+}
+
+// Canal de notificação (e-mail, chat, etc.)
+type notifier interface {
+	Name() string
+	Notify(change stateChange) error
+}
+
+// Função para montar a lista de canais de notificação habilitados na configuração
+func enabledNotifiers(config *Config) []notifier {
+	notifiers := []notifier{}
+	if config.Email.Enabled {
+		notifiers = append(notifiers, emailNotifier{config: config.Email})
+	}
+	if config.Slack.Enabled {
+		notifiers = append(notifiers, slackNotifier{config: config.Slack})
+	}
+	if config.Telegram.Enabled {
+		notifiers = append(notifiers, telegramNotifier{config: config.Telegram})
+	}
+	if config.Discord.Enabled {
+		notifiers = append(notifiers, discordNotifier{config: config.Discord})
+	}
+	if config.Teams.Enabled {
+		notifiers = append(notifiers, teamsNotifier{config: config.Teams})
+	}
+	if config.PagerDuty.Enabled {
+		notifiers = append(notifiers, pagerDutyNotifier{config: config.PagerDuty})
+	}
+	if config.Opsgenie.Enabled {
+		notifiers = append(notifiers, opsgenieNotifier{config: config.Opsgenie})
+	}
+	if config.SMS.Enabled {
+		notifiers = append(notifiers, smsNotifier{config: config.SMS})
+	}
+	if config.Ntfy.Enabled {
+		notifiers = append(notifiers, ntfyNotifier{config: config.Ntfy})
+	}
+	if config.Gotify.Enabled {
+		notifiers = append(notifiers, gotifyNotifier{config: config.Gotify})
+	}
+	for _, webhook := range config.Webhooks {
+		if webhook.Enabled {
+			notifiers = append(notifiers, webhookNotifier{config: webhook})
+		}
+	}
+	return notifiers
+}
+
+// Função para enviar a mudança de status aos canais habilitados (ou aos da etapa de escalonamento)
+// cujos filtros aceitam o serviço, sem bloquear o monitoramento (retorna false quando a notificação é suprimida por um silêncio)
+func notifyStateChange(change stateChange) bool {
+	if isSilenced(change.Service, change.Time) {
+		log.Printf(tr("Notificação do serviço [%s] (%s) suprimida por um silêncio ativo\n"), change.Service.Description, change.To)
+		return false
+	}
+	config := getConfig()
+	for _, n := range enabledNotifiers(config) {
+		if change.Channels != nil && !slices.Contains(change.Channels, channelName(n)) {
+			continue
+		}
+		if !routeAllows(config, n, change.Service) {
+			continue
+		}
+		if !scheduleAllows(config, n, change.Time) {
+			log.Printf(tr("Notificação (%s) do serviço [%s] não enviada: fora do horário do canal\n"), n.Name(), change.Service.Description)
+			continue
+		}
+		go func(n notifier) {
+			if err := n.Notify(applyMessageTemplates(config, n, change)); err != nil {
+				log.Printf(tr("Erro ao enviar notificação (%s) do serviço [%s]: %v\n"), n.Name(), change.Service.Description, err)
+			}
+		}(n)
+	}
+	return true
+}
+
+// Função para descrever a mudança de status em uma linha (ex.: assunto do e-mail)
+func (change stateChange) title() string {
+	if change.CustomTitle != "" {
+		return change.CustomTitle
+	}
+	if change.Repeat {
+		return fmt.Sprintf(tr("[DOWN] %s is still offline"), change.Service.Description)
+	}
+	if change.To == "red" {
+		return fmt.Sprintf(tr("[DOWN] %s is offline"), change.Service.Description)
+	}
+	return fmt.Sprintf(tr("[UP] %s is back online"), change.Service.Description)
+}
+
+// Função para descrever há quanto tempo o serviço estava no status anterior
+// (na recuperação, o tempo total fora do ar e o momento da primeira falha), ou o texto do message_template
+func (change stateChange) durationText() string {
+	if change.CustomText != "" {
+		return change.CustomText
+	}
+	if change.Repeat {
+		return fmt.Sprintf(tr("Offline for %s (first failure at %s)"), formatDuration(change.Duration), change.FirstFailure.Format("2006-01-02 15:04:05"))
+	}
+	if change.From == "red" {
+		return fmt.Sprintf(tr("Was offline for %s (first failure at %s)"), formatDuration(change.Duration), change.FirstFailure.Format("2006-01-02 15:04:05"))
+	}
+	if change.From == "unknown" {
+		return fmt.Sprintf(tr("Offline since the first check (first failure at %s)"), change.FirstFailure.Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Sprintf(tr("Was online for %s"), formatDuration(change.Duration))
+}
+
+// Função para obter o endereço verificado do serviço
+func (change stateChange) address() string {
+	if change.Service.Type == "push" {
+		return "push"
+	}
+	if change.Service.Type == "dns" {
+		return "dns " + change.Service.IP
+	}
+	return change.Service.IP + ":" + change.Service.Port
+}
+
+// Função para obter o link do dashboard incluído nas notificações (vazio se public_url não foi informado)
+func dashboardURL() string {
+	return getConfig().PublicURL
+}
+
+var notifyClient = &http.Client{Timeout: 10 * time.Second} // Cliente HTTP dos canais de notificação
+
+// Função para enviar um JSON a um canal de notificação, retornando o corpo da resposta
+func postJSON(url string, headers map[string]string, payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return postBody(url, headers, data)
+}
+
+// Função para enviar um corpo JSON já serializado a um canal de notificação
+func postBody(url string, headers map[string]string, data []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return body, fmt.Errorf("resposta %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [ntfy]
+type NtfyConfig struct {
+	Enabled      bool
+	Server       string // Ex.: https://ntfy.sh ou o servidor próprio
+	Topic        string
+	Token        string // Access token, para tópicos protegidos
+	PriorityDown string // Prioridade das quedas (min, low, default, high, urgent ou 1 a 5)
+	PriorityUp   string // Prioridade das recuperações
+}
+
+// Função para ler a seção [ntfy] do config.ini
+func loadNtfyConfig(cfg *ini.File) NtfyConfig {
+	section := cfg.Section("ntfy")
+	return NtfyConfig{
+		Enabled:      section.Key("enabled").MustBool(false),
+		Server:       strings.TrimSuffix(section.Key("server").MustString("https://ntfy.sh"), "/"),
+		Topic:        section.Key("topic").String(),
+		Token:        section.Key("token").String(),
+		PriorityDown: section.Key("priority_down").MustString("urgent"),
+		PriorityUp:   section.Key("priority_up").MustString("default"),
+	}
+}
+
+// Notificação por push do ntfy
+type ntfyNotifier struct {
+	config NtfyConfig
+}
+
+func (n ntfyNotifier) Name() string { return "ntfy" }
+
+// Função para publicar a mudança de status no tópico, com a prioridade do novo status
+func (n ntfyNotifier) Notify(change stateChange) error {
+	if n.config.Topic == "" {
+		return fmt.Errorf("informe topic na seção [ntfy]")
+	}
+	message := change.durationText() + "\nResponse time: " + change.Service.ResponseTime
+	if change.Service.Message != "" {
+		message += "\n" + change.Service.Message
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.config.Server+"/"+n.config.Topic, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", change.title())
+	if change.To == "red" {
+		req.Header.Set("Priority", n.config.PriorityDown)
+		req.Header.Set("Tags", "red_circle")
+	} else {
+		req.Header.Set("Priority", n.config.PriorityUp)
+		req.Header.Set("Tags", "white_check_mark")
+	}
+	if url := dashboardURL(); url != "" {
+		req.Header.Set("Click", url)
+	}
+	if n.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.Token)
+	}
+
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("resposta %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [oidc]
+type OIDCConfig struct {
+	Enabled        bool
+	Issuer         string
+	ClientID       string
+	ClientSecret   string
+	RedirectURL    string
+	Scopes         []string
+	GroupsClaim    string
+	AdminGroups    []string
+	OperatorGroups []string
+	ViewerGroups   []string // Vazio: qualquer usuário autenticado no provedor é viewer
+	SessionTTL     time.Duration
+}
+
+// Função para ler a seção [oidc] do config.ini
+func loadOIDCConfig(cfg *ini.File) OIDCConfig {
+	section := cfg.Section("oidc")
+	config := OIDCConfig{
+		Enabled:        section.Key("enabled").MustBool(false),
+		Issuer:         section.Key("issuer").String(),
+		ClientID:       section.Key("client_id").String(),
+		ClientSecret:   section.Key("client_secret").String(),
+		RedirectURL:    section.Key("redirect_url").String(),
+		Scopes:         []string{oidc.ScopeOpenID, "profile", "email"},
+		GroupsClaim:    section.Key("groups_claim").MustString("groups"),
+		AdminGroups:    section.Key("admin_groups").Strings(","),
+		OperatorGroups: section.Key("operator_groups").Strings(","),
+		ViewerGroups:   section.Key("viewer_groups").Strings(","),
+		SessionTTL:     12 * time.Hour,
+	}
+	if section.HasKey("scopes") {
+		config.Scopes = section.Key("scopes").Strings(",")
+	}
+	if ttl, err := parseRange(section.Key("session_ttl").String(), config.SessionTTL); err == nil {
+		config.SessionTTL = ttl
+	} else {
+		log.Println(tr("Erro ao converter session_ttl, usando valor padrão de 12 horas"))
+	}
+	return config
+}
+
+// Sessão de um usuário autenticado pelo SSO
+type session struct {
+	User    string
+	Role    string
+	Expires time.Time
+}
+
+// Login em andamento, aguardando o retorno do provedor em /auth/callback
+type pendingLogin struct {
+	Nonce   string
+	Next    string
+	Expires time.Time
+}
+
+const sessionCookie = "wcs_session"
+
+var sessionsMu sync.Mutex                     // Mutex para proteger as sessões e os logins em andamento
+var sessions = map[string]session{}           // Sessões ativas, indexadas pelo valor do cookie
+var pendingLogins = map[string]pendingLogin{} // Logins em andamento, indexados pelo parâmetro state
+
+// Cliente OIDC criado a partir da descoberta do provedor (refeito se a configuração mudar)
+type oidcClient struct {
+	config   OIDCConfig
+	verifier *oidc.IDTokenVerifier
+	oauth2   oauth2.Config
+}
+
+var oidcMu sync.Mutex
+var oidcCurrent *oidcClient
+
+// Função para obter o cliente OIDC, fazendo a descoberta do provedor na primeira utilização
+func getOIDCClient(ctx context.Context, config OIDCConfig) (*oidcClient, error) {
+	oidcMu.Lock()
+	defer oidcMu.Unlock()
+
+	if oidcCurrent != nil && oidcCurrent.config.Issuer == config.Issuer && oidcCurrent.config.ClientID == config.ClientID &&
+		oidcCurrent.config.ClientSecret == config.ClientSecret && oidcCurrent.config.RedirectURL == config.RedirectURL &&
+		strings.Join(oidcCurrent.config.Scopes, ",") == strings.Join(config.Scopes, ",") {
+		return oidcCurrent, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("descoberta do provedor %s: %w", config.Issuer, err)
+	}
+	oidcCurrent = &oidcClient{
+		config:   config,
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       config.Scopes,
+		},
+	}
+	return oidcCurrent, nil
+}
+
+// Função para obter a sessão do cookie da requisição, se ainda for válida
+func lookupSession(r *http.Request) (session, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return session{}, false
+	}
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	s, ok := sessions[cookie.Value]
+	if !ok || time.Now().After(s.Expires) {
+		delete(sessions, cookie.Value)
+		return session{}, false
+	}
+	return s, true
+}
+
+// Função para remover as sessões e os logins expirados (deve ser chamada com sessionsMu bloqueado)
+func pruneSessions() {
+	now := time.Now()
+	for id, s := range sessions {
+		if now.After(s.Expires) {
+			delete(sessions, id)
+		}
+	}
+	for state, login := range pendingLogins {
+		if now.After(login.Expires) {
+			delete(pendingLogins, state)
+		}
+	}
+}
+
+// Função para verificar se a requisição vem de um navegador (que deve ser redirecionado ao login)
+func wantsHTML(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// Handler que inicia o login redirecionando para o provedor OIDC
+func oidcLoginHandler(w http.ResponseWriter, r *http.Request) {
+	config := getConfig().OIDC
+	if !config.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	client, err := getOIDCClient(r.Context(), config)
+	if err != nil {
+		log.Println(tr("Erro no SSO:"), err)
+		http.Error(w, "Provedor de SSO indisponível", http.StatusBadGateway)
+		return
+	}
+
+	next := r.URL.Query().Get("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") { // Evita redirecionamento para outros sites
+		next = "/"
+	}
+	state, nonce := generateToken(), generateToken()
+	sessionsMu.Lock()
+	pruneSessions()
+	pendingLogins[state] = pendingLogin{Nonce: nonce, Next: next, Expires: time.Now().Add(10 * time.Minute)}
+	sessionsMu.Unlock()
+
+	http.Redirect(w, r, client.oauth2.AuthCodeURL(state, oidc.Nonce(nonce)), http.StatusFound)
+}
+
+// Handler de retorno do provedor: valida o ID token, mapeia os grupos em papéis e cria a sessão
+func oidcCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	config := getConfig().OIDC
+	if !config.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	sessionsMu.Lock()
+	login, ok := pendingLogins[state]
+	delete(pendingLogins, state)
+	sessionsMu.Unlock()
+	if !ok || time.Now().After(login.Expires) {
+		http.Error(w, "Login expirado ou inválido, tente novamente", http.StatusBadRequest)
+		return
+	}
+	if errorCode := r.URL.Query().Get("error"); errorCode != "" {
+		http.Error(w, "Login recusado pelo provedor: "+errorCode, http.StatusForbidden)
+		return
+	}
+
+	client, err := getOIDCClient(r.Context(), config)
+	if err != nil {
+		log.Println(tr("Erro no SSO:"), err)
+		http.Error(w, "Provedor de SSO indisponível", http.StatusBadGateway)
+		return
+	}
+	token, err := client.oauth2.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		log.Println(tr("Erro ao trocar o código de autorização:"), err)
+		http.Error(w, "Falha no login", http.StatusUnauthorized)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		http.Error(w, "O provedor não retornou um ID token", http.StatusUnauthorized)
+		return
+	}
+	idToken, err := client.verifier.Verify(r.Context(), rawIDToken)
+	if err != nil || idToken.Nonce != login.Nonce {
+		log.Println(tr("ID token inválido:"), err)
+		http.Error(w, "Falha no login", http.StatusUnauthorized)
+		return
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		http.Error(w, "Falha no login", http.StatusUnauthorized)
+		return
+	}
+	user := claimString(claims, "preferred_username", "email", "sub")
+	role, ok := mapGroupsToRole(config, claimStrings(claims[config.GroupsClaim]))
+	if !ok {
+		log.Printf(tr("Login SSO de %s recusado: nenhum grupo autorizado\n"), user)
+		recordAudit(user, clientIP(r), "login.failed", "oidc", nil, nil)
+		http.Error(w, "Usuário sem permissão para acessar o monitoramento", http.StatusForbidden)
+		return
+	}
+
+	id := generateToken()
+	sessionsMu.Lock()
+	sessions[id] = session{User: user, Role: role, Expires: time.Now().Add(config.SessionTTL)}
+	sessionsMu.Unlock()
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(config.SessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	log.Printf(tr("Login SSO de %s (%s)\n"), user, role)
+	recordAudit(user, clientIP(r), "login", "oidc", nil, map[string]string{"role": role})
+	http.Redirect(w, r, login.Next, http.StatusFound)
+}
+
+// Handler para encerrar a sessão do SSO
+func logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		sessionsMu.Lock()
+		delete(sessions, cookie.Value)
+		sessionsMu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true})
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// Função para definir o papel do usuário a partir dos grupos do ID token
+func mapGroupsToRole(config OIDCConfig, groups []string) (string, bool) {
+	if matchesGroup(groups, config.AdminGroups) {
+		return roleAdmin, true
+	}
+	if matchesGroup(groups, config.OperatorGroups) {
+		return roleOperator, true
+	}
+	if len(config.ViewerGroups) == 0 || matchesGroup(groups, config.ViewerGroups) {
+		return roleViewer, true
+	}
+	return "", false
+}
+
+// Função para verificar se algum dos grupos do usuário está na lista configurada
+// (o "/" inicial dos grupos do Keycloak é ignorado)
+func matchesGroup(groups, allowed []string) bool {
+	for _, group := range groups {
+		for _, candidate := range allowed {
+			if strings.EqualFold(strings.TrimPrefix(group, "/"), strings.TrimPrefix(candidate, "/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Função para obter a primeira claim de texto preenchida entre as informadas
+func claimString(claims map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if value, ok := claims[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Função para converter a claim de grupos (lista ou texto) em uma lista de textos
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Rota da API registrada, usada para gerar a especificação OpenAPI
+type apiRoute struct {
+	Method  string
+	Path    string
+	Summary string
+	Query   []string // Parâmetros de query aceitos pela rota
+}
+
+var apiRoutes []apiRoute
+var pathParamRegex = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// Função para registrar um handler da API e documentá-lo na especificação OpenAPI
+func handleAPI(method, path, summary string, handler http.HandlerFunc, query ...string) {
+	mux.HandleFunc(method+" "+path, handler)
+	apiRoutes = append(apiRoutes, apiRoute{Method: method, Path: path, Summary: summary, Query: query})
+}
+
+// Função para gerar a especificação OpenAPI 3 a partir das rotas registradas
+func buildOpenAPISpec() map[string]interface{} {
+	paths := map[string]interface{}{}
+	for _, route := range apiRoutes {
+		pattern := route.Method + " " + route.Path
+		route.Path = strings.TrimSuffix(route.Path, "{$}") // Marcador de caminho exato do ServeMux
+		parameters := []interface{}{}
+		for _, match := range pathParamRegex.FindAllStringSubmatch(route.Path, -1) {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     match[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]string{"type": "string"},
+			})
+		}
+		for _, name := range route.Query {
+			parameters = append(parameters, map[string]interface{}{
+				"name":   name,
+				"in":     "query",
+				"schema": map[string]string{"type": "string"},
+			})
+		}
+
+		path := pathParamRegex.ReplaceAllString(route.Path, "{$1}")
+		operations, ok := paths[path].(map[string]interface{})
+		if !ok {
+			operations = map[string]interface{}{}
+			paths[path] = operations
+		}
+		operations[strings.ToLower(route.Method)] = map[string]interface{}{
+			"summary":         route.Summary,
+			"parameters":      parameters,
+			"x-required-role": routeRole(route.Method, pattern),
+			"responses": map[string]interface{}{
+				"200": map[string]string{"description": "OK"},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":   "Web Check Status Services API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Service": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":           map[string]string{"type": "integer"},
+						"Description":  map[string]string{"type": "string"},
+						"Group":        map[string]string{"type": "string"},
+						"Status":       map[string]interface{}{"type": "string", "enum": []string{"green", "red", "paused", "unknown"}},
+						"ResponseTime": map[string]string{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Handler para servir a especificação OpenAPI
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, buildOpenAPISpec())
+}
+
+// Página do Swagger UI apontando para a especificação servida em /api/openapi.json
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>API - Service Monitoring</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        window.ui = SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+    </script>
+</body>
+</html>`
+
+// Handler para servir o Swagger UI
+func swaggerUIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [opsgenie]
+type OpsgenieConfig struct {
+	Enabled  bool
+	APIKey   string            // Chave da integração API no Opsgenie
+	APIURL   string            // https://api.opsgenie.com ou https://api.eu.opsgenie.com
+	Priority string            // Prioridade padrão dos alertas (P1 a P5)
+	Tags     []string          // Tags adicionadas a todos os alertas
+	Groups   map[string]string // Prioridade por grupo de serviços, da seção [opsgenie.priorities]
+}
+
+var opsgeniePriorities = []string{"P1", "P2", "P3", "P4", "P5"}
+
+// Função para ler as seções [opsgenie] e [opsgenie.priorities] do config.ini
+func loadOpsgenieConfig(cfg *ini.File) OpsgenieConfig {
+	section := cfg.Section("opsgenie")
+	config := OpsgenieConfig{
+		Enabled:  section.Key("enabled").MustBool(false),
+		APIKey:   section.Key("api_key").String(),
+		APIURL:   strings.TrimSuffix(section.Key("api_url").MustString("https://api.opsgenie.com"), "/"),
+		Priority: section.Key("priority").In("P3", opsgeniePriorities),
+		Tags:     section.Key("tags").Strings(","),
+		Groups:   map[string]string{},
+	}
+	for _, key := range cfg.Section("opsgenie.priorities").Keys() {
+		config.Groups[key.Name()] = key.In(config.Priority, opsgeniePriorities)
+	}
+	return config
+}
+
+// Notificação no Opsgenie: cria o alerta quando o serviço cai e o fecha quando ele volta
+type opsgenieNotifier struct {
+	config OpsgenieConfig
+}
+
+func (n opsgenieNotifier) Name() string { return "opsgenie" }
+
+// Função para criar ou fechar o alerta, identificado pelo alias do serviço
+func (n opsgenieNotifier) Notify(change stateChange) error {
+	if n.config.APIKey == "" {
+		return fmt.Errorf("informe api_key na seção [opsgenie]")
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + n.config.APIKey}
+	alias := "web-check-status-services/" + change.Service.Description
+
+	if change.To != "red" {
+		closeURL := n.config.APIURL + "/v2/alerts/" + url.PathEscape(alias) + "/close?identifierType=alias"
+		_, err := postJSON(closeURL, headers, map[string]string{
+			"source": "Service Monitoring",
+			"note":   change.durationText(),
+		})
+		return err
+	}
+
+	priority := n.config.Priority
+	if groupPriority, ok := n.config.Groups[change.Service.Group]; ok && change.Service.Group != "" {
+		priority = groupPriority
+	}
+	tags := append([]string{}, n.config.Tags...)
+	if change.Service.Group != "" {
+		tags = append(tags, change.Service.Group)
+	}
+	details := map[string]string{
+		"address":       change.address(),
+		"response_time": change.Service.ResponseTime,
+	}
+	if url := dashboardURL(); url != "" {
+		details["dashboard"] = url
+	}
+	description := change.durationText()
+	if change.Service.Message != "" {
+		description += "\n" + change.Service.Message
+	}
+
+	_, err := postJSON(n.config.APIURL+"/v2/alerts", headers, map[string]interface{}{
+		"message":     change.title(),
+		"alias":       alias,
+		"description": description,
+		"priority":    priority,
+		"tags":        tags,
+		"entity":      change.Service.Description,
+		"source":      "Service Monitoring",
+		"details":     details,
+	})
+	return err
+}
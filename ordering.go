@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// Ordenações aceitas no dashboard (sort da seção [ui] e das visões, ?sort= na URL) e no /status.json
+var sortModes = []string{"config", "name", "group", "status", "manual"}
+
+// Função para ordenar os serviços: os fixados (pinned=true) primeiro e, depois, conforme mode: config (ordem do
+// config.ini), name, group (grupo e nome), status (fora do ar primeiro) ou manual (opção order=, os demais em
+// seguida na ordem do config.ini)
+func sortServices(services []Service, mode string) {
+	slices.SortStableFunc(services, func(a, b Service) int {
+		if a.Pinned != b.Pinned {
+			if a.Pinned {
+				return -1
+			}
+			return 1
+		}
+		switch mode {
+		case "name":
+			return strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
+		case "group":
+			if c := strings.Compare(a.Group, b.Group); c != 0 {
+				return c
+			}
+			return strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
+		case "status":
+			if c := statusSeverity[b.Status] - statusSeverity[a.Status]; c != 0 {
+				return c
+			}
+			return strings.Compare(strings.ToLower(a.Description), strings.ToLower(b.Description))
+		case "manual":
+			return manualOrder(a) - manualOrder(b)
+		}
+		return 0
+	})
+}
+
+// Função para obter a posição do serviço na ordenação manual (sem a opção order=, depois dos numerados)
+func manualOrder(service Service) int {
+	if service.Order == 0 {
+		return math.MaxInt
+	}
+	return service.Order
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Registro de uma queda: aberto quando o serviço fica vermelho e fechado quando volta (ou é pausado)
+type outage struct {
+	Service         string     `json:"service"`
+	Group           string     `json:"group,omitempty"`
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end,omitempty"` // nil enquanto a queda estiver em andamento
+	DurationSeconds float64    `json:"duration_seconds"`
+}
+
+const maxOutages = 50000 // Quedas mantidas em memória
+
+var outagesMu sync.Mutex               // Mutex para proteger as quedas
+var outages = []*outage{}              // Quedas em ordem de início
+var openOutages = map[string]*outage{} // Queda em andamento por serviço, indexada pela descrição
+
+// Função para abrir o registro de queda do serviço (se ainda não houver um em andamento)
+func openOutage(service Service, at time.Time) {
+	outagesMu.Lock()
+	defer outagesMu.Unlock()
+	if _, ok := openOutages[service.Description]; ok {
+		return
+	}
+	o := &outage{Service: service.Description, Group: service.Group, Start: at}
+	appendOutage(o)
+	saveOutage(*o)
+}
+
+// Função para fechar o registro de queda em andamento do serviço, se houver
+func closeOutage(description string, at time.Time) {
+	outagesMu.Lock()
+	defer outagesMu.Unlock()
+	o, ok := openOutages[description]
+	if !ok {
+		return
+	}
+	o.End = &at
+	o.DurationSeconds = at.Sub(o.Start).Seconds()
+	delete(openOutages, description)
+	saveOutage(*o)
+}
+
+// Função para incluir uma queda na memória, descartando as mais antigas além do limite
+// (chamada com outagesMu travado)
+func appendOutage(o *outage) {
+	outages = append(outages, o)
+	if len(outages) > maxOutages {
+		outages = outages[len(outages)-maxOutages:]
+	}
+	if o.End == nil {
+		openOutages[o.Service] = o
+	}
+}
+
+// Handler para listar as quedas, com filtros service, group, from, to (início da queda), open e limit
+func listOutagesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	to, err := parseTime(query.Get("to"), time.Now())
+	if err != nil {
+		http.Error(w, "Parâmetro to inválido", http.StatusBadRequest)
+		return
+	}
+	from, err := parseTime(query.Get("from"), time.Time{})
+	if err != nil {
+		http.Error(w, "Parâmetro from inválido", http.StatusBadRequest)
+		return
+	}
+	limit := 1000
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, "Parâmetro limit inválido", http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := time.Now()
+	result := []outage{}
+	count := 0
+	var downtime float64
+	outagesMu.Lock()
+	for i := len(outages) - 1; i >= 0; i-- {
+		o := *outages[i]
+		if o.Start.Before(from) || o.Start.After(to) {
+			continue
+		}
+		if service := query.Get("service"); service != "" && o.Service != service {
+			continue
+		}
+		if group := query.Get("group"); group != "" && o.Group != group {
+			continue
+		}
+		if open := query.Get("open"); open != "" && (o.End == nil) != (open == "true") {
+			continue
+		}
+		if o.End == nil {
+			o.DurationSeconds = now.Sub(o.Start).Seconds()
+		}
+		count++
+		downtime += o.DurationSeconds
+		if len(result) < limit {
+			result = append(result, o)
+		}
+	}
+	outagesMu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":            count,
+		"downtime_seconds": downtime,
+		"outages":          result,
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [pagerduty] (Events API v2)
+type PagerDutyConfig struct {
+	Enabled    bool
+	RoutingKey string            // Integration key do serviço no PagerDuty
+	Severity   string            // critical, error, warning ou info
+	APIURL     string            // Endpoint da Events API (ex.: https://events.eu.pagerduty.com/v2/enqueue)
+	Groups     map[string]string // Routing key por grupo de serviços, da seção [pagerduty.groups]
+}
+
+// Função para ler as seções [pagerduty] e [pagerduty.groups] do config.ini
+func loadPagerDutyConfig(cfg *ini.File) PagerDutyConfig {
+	section := cfg.Section("pagerduty")
+	config := PagerDutyConfig{
+		Enabled:    section.Key("enabled").MustBool(false),
+		RoutingKey: section.Key("routing_key").String(),
+		Severity:   section.Key("severity").In("critical", []string{"critical", "error", "warning", "info"}),
+		APIURL:     section.Key("api_url").MustString("https://events.pagerduty.com/v2/enqueue"),
+		Groups:     map[string]string{},
+	}
+	for _, key := range cfg.Section("pagerduty.groups").Keys() {
+		config.Groups[key.Name()] = key.String()
+	}
+	return config
+}
+
+// Notificação no PagerDuty: abre o incidente quando o serviço cai e o resolve quando ele volta
+type pagerDutyNotifier struct {
+	config PagerDutyConfig
+}
+
+func (n pagerDutyNotifier) Name() string { return "pagerduty" }
+
+// Função para enviar o evento trigger/resolve, com a mesma dedup key para cada serviço
+func (n pagerDutyNotifier) Notify(change stateChange) error {
+	routingKey := n.config.RoutingKey
+	if key, ok := n.config.Groups[change.Service.Group]; ok && change.Service.Group != "" {
+		routingKey = key
+	}
+	if routingKey == "" {
+		return fmt.Errorf("informe routing_key na seção [pagerduty]")
+	}
+
+	event := map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "resolve",
+		"dedup_key":    "web-check-status-services/" + change.Service.Description,
+	}
+	if change.To == "red" {
+		details := map[string]interface{}{
+			"address":       change.address(),
+			"response_time": change.Service.ResponseTime,
+		}
+		if change.Service.Message != "" {
+			details["message"] = change.Service.Message
+		}
+		event["event_action"] = "trigger"
+		event["payload"] = map[string]interface{}{
+			"summary":        change.title(),
+			"source":         change.address(),
+			"severity":       n.config.Severity,
+			"timestamp":      change.Time.Format(time.RFC3339),
+			"component":      change.Service.Description,
+			"group":          change.Service.Group,
+			"class":          "availability",
+			"custom_details": details,
+		}
+		event["client"] = "Service Monitoring"
+		if url := dashboardURL(); url != "" {
+			event["client_url"] = url
+			event["links"] = []map[string]string{{"href": url, "text": "Dashboard"}}
+		}
+	}
+
+	_, err := postJSON(n.config.APIURL, nil, event)
+	return err
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [agents]: porta com TLS mútuo para as sondas remotas
+type AgentsConfig struct {
+	Enabled  bool
+	Listen   string
+	CAFile   string // CA que assina os certificados das sondas
+	CertFile string // Certificado do servidor central, assinado pela mesma CA
+	KeyFile  string
+}
+
+// Função para ler a seção [agents] do config.ini
+func loadAgentsConfig(cfg *ini.File) AgentsConfig {
+	section := cfg.Section("agents")
+	return AgentsConfig{
+		Enabled:  section.Key("enabled").MustBool(false),
+		Listen:   section.Key("listen").MustString(":8443"),
+		CAFile:   section.Key("ca_file").MustString("pki/ca.pem"),
+		CertFile: section.Key("cert_file").MustString("pki/server.pem"),
+		KeyFile:  section.Key("key_file").MustString("pki/server.key"),
+	}
+}
+
+// Função para gravar uma chave ECDSA em PEM
+func writeKey(path string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+}
+
+// Função para gerar um número de série aleatório para os certificados
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// Função para criar a CA interna (ca.pem e ca.key) no diretório informado
+func createCA(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, "ca.key")); err == nil {
+		return fmt.Errorf("já existe uma CA em %s", dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "web-check-status-services CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	if err := writeKey(filepath.Join(dir, "ca.key"), key); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "ca.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// Função para emitir um certificado assinado pela CA interna (<nome>.pem e <nome>.key): de servidor, para a porta
+// das sondas na central, ou de cliente, para uma sonda. Cada certificado vale só para o seu uso, para que o
+// certificado do servidor não seja aceito como o de uma sonda.
+func issueCertificate(dir, name string, hosts []string, server bool) error {
+	caPair, err := tls.LoadX509KeyPair(filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca.key"))
+	if err != nil {
+		return fmt.Errorf("CA não encontrada em %s (crie com -ca-init): %w", dir, err)
+	}
+	caCert, err := x509.ParseCertificate(caPair.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(2, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	for _, host := range hosts {
+		if host = strings.TrimSpace(host); host == "" {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caPair.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if err := writeKey(filepath.Join(dir, name+".key"), key); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// Função para carregar o pool de certificados da CA
+func loadCAPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("nenhum certificado válido em %s", path)
+	}
+	return pool, nil
+}
+
+// Middleware que identifica a sonda pelo certificado de cliente (papel operator). Certificados que também valem
+// para servidor (como o da própria central, ou os emitidos por versões antigas para os dois usos) são recusados.
+func agentAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "Certificado de cliente obrigatório", http.StatusUnauthorized)
+			return
+		}
+		cert := r.TLS.VerifiedChains[0][0]
+		if slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageServerAuth) || slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageAny) {
+			http.Error(w, "Certificado de servidor não é aceito como certificado de sonda", http.StatusForbidden)
+			return
+		}
+		agent := cert.Subject.CommonName
+		serveWithRole(next, w, withPrincipal(r, "agent:"+agent, roleOperator))
+	})
+}
+
+// Função para iniciar a porta das sondas remotas, que exige certificado de cliente assinado pela CA e serve
+// apenas o WebSocket das sondas (o dashboard e a API ficam na porta principal, com as regras de acesso)
+func startAgentServer(config AgentsConfig) {
+	if !config.Enabled {
+		return
+	}
+	pool, err := loadCAPool(config.CAFile)
+	if err != nil {
+		log.Println(tr("Porta das sondas desabilitada, erro ao carregar a CA:"), err)
+		return
+	}
+	cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		log.Println(tr("Porta das sondas desabilitada, erro ao carregar o certificado:"), err)
+		return
+	}
+
+	agentMux := http.NewServeMux()
+	agentMux.HandleFunc("GET "+agentPath, agentWSHandler)
+	server := registerServer(&http.Server{
+		Addr:    config.Listen,
+		Handler: agentAuthMiddleware(agentMux),
+		TLSConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+			ClientCAs:    pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		},
+	})
+	go func() {
+		log.Printf(tr("Porta das sondas (mTLS) iniciada em %s\n"), config.Listen)
+		if err := server.ListenAndServeTLS("", ""); err != nil && !serverClosed(err) {
+			log.Println(tr("Erro na porta das sondas:"), err)
+		}
+	}()
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da página de status pública (seção [public])
+type PublicConfig struct {
+	Enabled bool              // Habilita a página de status pública
+	Path    string            // Caminho no servidor principal, liberado sem autenticação (vazio = apenas em listen)
+	Listen  string            // Endereço de uma porta separada só com a página pública (ex.: :8080)
+	Title   string            // Título da página pública
+	Names   map[string]string // Nome exibido de cada serviço publicado, da seção [public.names]
+}
+
+// Serviço como exibido na página pública: sem endereço, porta, grupo ou descrição interna
+type publicService struct {
+	Name   string         `json:"name"`
+	Status string         `json:"status"` // up, down, maintenance ou unknown
+	Uptime *uptimeSummary `json:"uptime,omitempty"`
+}
+
+// Atualização de um incidente na página pública (sem o operador que a publicou)
+type publicUpdate struct {
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status"`
+	Message string    `json:"message"`
+}
+
+// Incidente em andamento na página pública, com os nomes públicos dos serviços afetados
+type publicIncident struct {
+	Title     string         `json:"title"`
+	Status    string         `json:"status"`
+	Services  []string       `json:"services"`
+	StartedAt time.Time      `json:"started_at"`
+	Updates   []publicUpdate `json:"updates"`
+}
+
+// Resposta de status.json na página pública
+type publicStatus struct {
+	Title     string           `json:"title"`
+	Status    string           `json:"status"` // operational, degraded (parte fora do ar) ou outage (todos fora do ar)
+	Services  []publicService  `json:"services"`
+	Incidents []publicIncident `json:"incidents"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Status interno -> status exibido na página pública
+var publicStatuses = map[string]string{"green": "up", "red": "down", "paused": "maintenance", "unknown": "unknown"}
+
+var publicRoute string // Caminho da página pública registrado no servidor principal (vazio = não registrado)
+
+// Função para ler a seção [public] do config.ini
+func loadPublicConfig(cfg *ini.File) PublicConfig {
+	section := cfg.Section("public")
+	config := PublicConfig{
+		Enabled: section.Key("enabled").MustBool(false),
+		Path:    section.Key("path").MustString("/status"),
+		Listen:  section.Key("listen").String(),
+		Title:   section.Key("title").MustString("Service Status"),
+		Names:   map[string]string{},
+	}
+	for _, key := range cfg.Section("public.names").Keys() {
+		if key.String() != "" {
+			config.Names[key.Name()] = key.String()
+		}
+	}
+	if config.Enabled && len(config.Names) == 0 {
+		log.Println(tr("Página de status pública habilitada sem serviços na seção [public.names]"))
+	}
+	return config
+}
+
+// Função para montar o status público: apenas os serviços da seção [public.names], pelo nome público, e os
+// incidentes em andamento que afetam algum deles
+func buildPublicStatus(config *Config) publicStatus {
+	status := publicStatus{Title: config.Public.Title, Status: "operational", Services: []publicService{}, Incidents: []publicIncident{}, UpdatedAt: time.Now()}
+	down := 0
+	for _, service := range snapshotServices() {
+		name, ok := config.Public.Names[service.Description]
+		if !ok {
+			continue
+		}
+		status.Services = append(status.Services, publicService{Name: name, Status: publicStatuses[service.Status], Uptime: service.Uptime})
+		if service.Status == "red" {
+			down++
+		}
+	}
+	if down > 0 {
+		status.Status = "degraded"
+		if down == len(status.Services) {
+			status.Status = "outage"
+		}
+	}
+
+	incidentsMu.Lock()
+	for _, i := range incidents {
+		if i.ResolvedAt != nil {
+			continue
+		}
+		item := publicIncident{Title: i.Title, Status: i.Status, Services: []string{}, StartedAt: i.StartedAt, Updates: []publicUpdate{}}
+		for _, service := range i.Services {
+			if name, ok := config.Public.Names[service]; ok && !slices.Contains(item.Services, name) {
+				item.Services = append(item.Services, name)
+			}
+		}
+		if len(item.Services) == 0 {
+			continue
+		}
+		for _, update := range i.Updates {
+			item.Updates = append(item.Updates, publicUpdate{Time: update.Time, Status: update.Status, Message: update.Message})
+		}
+		status.Incidents = append(status.Incidents, item)
+	}
+	incidentsMu.Unlock()
+	sort.Slice(status.Incidents, func(a, b int) bool { return status.Incidents[a].StartedAt.After(status.Incidents[b].StartedAt) })
+	return status
+}
+
+// Handler para a página de status pública, que consulta o status em statusURL
+func publicPageHandler(statusURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tmpl, err := template.ParseFS(assets(), "public.html")
+		if err != nil {
+			log.Println(tr("Erro ao carregar public.html:"), err)
+			http.Error(w, "Erro ao carregar a página de status", http.StatusInternalServerError)
+			return
+		}
+		page := struct{ Title, StatusURL, Language string }{getConfig().Public.Title, statusURL, languageOf()}
+		if page.Language == "" {
+			page.Language = "en"
+		}
+		if err := tmpl.Execute(w, page); err != nil {
+			log.Println(tr("Erro ao gerar a página de status:"), err)
+		}
+	}
+}
+
+// Handler para o status público em JSON, consultado pela página pública
+func publicStatusHandler(w http.ResponseWriter, r *http.Request) {
+	config := getConfig()
+	if !config.Public.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, buildPublicStatus(config))
+}
+
+// Função para verificar se a requisição é da página pública registrada no servidor principal
+func isPublicStatusPath(path string) bool {
+	return publicRoute != "" && (path == publicRoute || path == publicRoute+"/status.json")
+}
+
+// Função para registrar a página pública no servidor principal e iniciar a porta separada, se configurada.
+// Alterações em enabled, path e listen só têm efeito após reiniciar o processo.
+func startPublicServer(config PublicConfig) {
+	if !config.Enabled {
+		return
+	}
+
+	if path := strings.Trim(config.Path, "/"); path != "" {
+		publicRoute = "/" + path
+		mux.HandleFunc("GET "+publicRoute, publicPageHandler(publicRoute+"/status.json"))
+		mux.HandleFunc("GET "+publicRoute+"/status.json", publicStatusHandler)
+	}
+
+	if config.Listen == "" {
+		return
+	}
+	publicMux := http.NewServeMux()
+	publicMux.HandleFunc("GET /{$}", publicPageHandler("/status.json"))
+	publicMux.HandleFunc("GET /status.json", publicStatusHandler)
+	server := registerServer(&http.Server{Addr: config.Listen, Handler: rateLimitMiddleware(publicMux)})
+	go func() {
+		log.Printf(tr("Página de status pública iniciada em %s\n"), config.Listen)
+		if err := server.ListenAndServe(); err != nil && !serverClosed(err) {
+			log.Println(tr("Erro no servidor da página de status pública:"), err)
+		}
+	}()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Último status recebido por um serviço do tipo push
+type pushState struct {
+	Status    string
+	LatencyMs int64
+	Message   string
+	Time      time.Time
+}
+
+var pushStates = map[string]pushState{} // Estado dos serviços push, indexado pela descrição (protegido por mu)
+
+// Status aceitos no push e o status correspondente no dashboard
+var pushStatusAliases = map[string]string{
+	"up": "green", "green": "green", "ok": "green", "success": "green",
+	"down": "red", "red": "red", "fail": "red", "failure": "red", "error": "red",
+}
+
+// Função para avaliar o status atual de um serviço push; fica vermelho se o último push
+// for mais antigo que expect_every e "unknown" enquanto nenhum push for recebido
+func evaluatePush(service Service) (string, int64, string) {
+	mu.Lock()
+	state, ok := pushStates[service.Description]
+	mu.Unlock()
+
+	if !ok {
+		return "unknown", 0, ""
+	}
+	if service.PushInterval > 0 && time.Since(state.Time) > service.PushInterval {
+		return "red", 0, "Nenhum push recebido desde " + state.Time.Format("2006-01-02 15:04:05")
+	}
+	return state.Status, state.LatencyMs, state.Message
+}
+
+// Handler que recebe o status enviado por sistemas externos (CI, backups, etc.).
+// Aceita JSON {"status": "up|down", "message": "...", "response_time_ms": 123}, ?status=
+// ou corpo vazio (considerado "up").
+func pushHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+
+	var payload struct {
+		Status         string `json:"status"`
+		Message        string `json:"message"`
+		ResponseTimeMs int64  `json:"response_time_ms"`
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64*1024))
+	if err != nil {
+		http.Error(w, "Erro ao ler requisição", http.StatusBadRequest)
+		return
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "JSON inválido", http.StatusBadRequest)
+			return
+		}
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		payload.Status = status
+	}
+	if payload.Status == "" {
+		payload.Status = "up"
+	}
+	status, ok := pushStatusAliases[strings.ToLower(payload.Status)]
+	if !ok {
+		http.Error(w, "Status inválido (use up ou down)", http.StatusBadRequest)
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range latestServicesState {
+		service := &latestServicesState[i]
+		if service.Type != "push" || subtle.ConstantTimeCompare([]byte(service.PushToken), []byte(token)) != 1 {
+			continue
+		}
+
+		pushStates[service.Description] = pushState{Status: status, LatencyMs: payload.ResponseTimeMs, Message: payload.Message, Time: time.Now()}
+
+		// Atualiza o dashboard imediatamente (o histórico é registrado no próximo ciclo)
+		if service.Status != "paused" {
+			service.Status = status
+			service.LatencyMs = payload.ResponseTimeMs
+			service.ResponseTime = formatResponseTime(payload.ResponseTimeMs)
+			service.Message = payload.Message
+			hub.update(*service)
+		}
+		log.Printf(tr("Push recebido para o serviço [%s]: %s"), service.Description, status)
+		writeJSON(w, http.StatusOK, *service)
+		return
+	}
+	http.Error(w, "Token inválido", http.StatusNotFound)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+)
+
+// Papéis de acesso, do menor para o maior privilégio
+const (
+	roleViewer   = "viewer"   // Apenas consulta o status
+	roleOperator = "operator" // Também pausa/retoma, reverifica e reconhece alertas
+	roleAdmin    = "admin"    // Também altera serviços, configurações e tokens
+)
+
+var roleRank = map[string]int{roleViewer: 1, roleOperator: 2, roleAdmin: 3}
+
+// Papel mínimo exigido pelas rotas que fogem da regra geral
+// (consultas exigem viewer e alterações exigem admin)
+var routeRoles = map[string]string{
+	"POST /graphql":                    roleViewer,
+	"POST /grafana/search":             roleViewer,
+	"POST /grafana/query":              roleViewer,
+	"POST /grafana/annotations":        roleViewer,
+	"POST /api/services/{id}/pause":    roleOperator,
+	"POST /api/services/{id}/resume":   roleOperator,
+	"POST /api/groups/{group}/pause":   roleOperator,
+	"POST /api/groups/{group}/resume":  roleOperator,
+	"POST /api/push/{token}":           roleOperator,
+	"POST /api/silences":               roleOperator,
+	"POST /api/services/{id}/ack":      roleOperator,
+	"DELETE /api/services/{id}/ack":    roleOperator,
+	"DELETE /api/silences/{id}":        roleOperator,
+	"POST /api/incidents":              roleOperator,
+	"PUT /api/incidents/{id}":          roleOperator,
+	"POST /api/incidents/{id}/updates": roleOperator,
+	"POST /api/annotations":            roleOperator,
+	"DELETE /api/annotations/{id}":     roleOperator,
+	"GET /api/tokens":                  roleAdmin,
+	"GET /api/audit":                   roleAdmin,
+	"GET /api/backup":                  roleAdmin,
+}
+
+// Função para verificar se o papel atende ao papel exigido
+func roleAllows(role, required string) bool {
+	return roleRank[role] >= roleRank[required]
+}
+
+// Função para converter o escopo de um token de API no papel equivalente
+func scopeRole(scope string) string {
+	switch scope {
+	case scopeAdmin:
+		return roleAdmin
+	case scopeWrite:
+		return roleOperator
+	}
+	return roleViewer
+}
+
+// Função para obter o papel exigido por uma rota do ServeMux ("MÉTODO /caminho")
+func routeRole(method, pattern string) string {
+	if role, ok := routeRoles[pattern]; ok {
+		return role
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return roleViewer
+	}
+	return roleAdmin
+}
+
+// Função para obter o papel exigido pela rota que atenderá a requisição
+func requiredRole(r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	return routeRole(r.Method, pattern)
+}
+
+// Função para obter o papel do usuário autenticado (admin quando a autenticação está desabilitada)
+func currentRole(r *http.Request) string {
+	if role, ok := r.Context().Value(roleContextKey).(string); ok {
+		return role
+	}
+	return roleAdmin
+}
+
+// Handler que informa o usuário, o papel e as permissões da sessão atual, usado pelo dashboard
+func meHandler(w http.ResponseWriter, r *http.Request) {
+	role := currentRole(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user": currentUser(r),
+		"role": role,
+		"permissions": map[string]bool{
+			"view":    true,
+			"operate": roleAllows(role, roleOperator),
+			"admin":   roleAllows(role, roleAdmin),
+		},
+	})
+}
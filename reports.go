@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gopkg.in/ini.v1"
+)
+
+// Relatório periódico enviado por e-mail, de uma seção [report.<nome>]
+type ReportConfig struct {
+	Name     string
+	Enabled  bool
+	Schedule cron.Schedule // Agenda no formato cron (minuto hora dia mês dia-da-semana) ou @daily/@weekly
+	Spec     string        // Agenda como informada no config.ini
+	Period   time.Duration // Período coberto pelo relatório, até o momento do envio
+	To       []string      // Destinatários; vazio = destinatários padrão da seção [email]
+	Top      int           // Quantidade de serviços na lista dos piores
+	Groups   []string      // Grupos incluídos; vazio = todos
+}
+
+// Função para ler as seções [report.<nome>] do config.ini
+func loadReportConfigs(cfg *ini.File) []ReportConfig {
+	reports := []ReportConfig{}
+	for _, section := range cfg.Sections() {
+		name, ok := strings.CutPrefix(section.Name(), "report.")
+		if !ok || name == "" {
+			continue
+		}
+		report := ReportConfig{
+			Name:    name,
+			Enabled: section.Key("enabled").MustBool(true),
+			Spec:    section.Key("schedule").MustString("@daily"),
+			To:      splitList(section.Key("to").String()),
+			Top:     section.Key("top").MustInt(5),
+			Groups:  splitList(section.Key("groups").String()),
+		}
+		spec := report.Spec
+		if timezone := section.Key("timezone").String(); timezone != "" {
+			spec = "CRON_TZ=" + timezone + " " + spec
+		}
+		var err error
+		if report.Schedule, err = cron.ParseStandard(spec); err != nil {
+			log.Printf(tr("schedule inválido na seção [%s], relatório desabilitado: %v\n"), section.Name(), err)
+			continue
+		}
+		if report.Period, err = parseRange(section.Key("period").String(), defaultReportPeriod(report.Schedule)); err != nil {
+			log.Printf(tr("period inválido na seção [%s], relatório desabilitado\n"), section.Name())
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// Função para usar como período padrão o intervalo entre dois envios (ex.: 24h para @daily, 7d para @weekly)
+func defaultReportPeriod(schedule cron.Schedule) time.Duration {
+	next := schedule.Next(time.Now())
+	return schedule.Next(next).Sub(next)
+}
+
+// Linha de um serviço ou grupo no relatório
+type reportLine struct {
+	Name            string
+	UptimePercent   float64
+	Outages         int
+	DowntimeSeconds float64
+	monitored       float64
+}
+
+// Função para montar o assunto e o texto do relatório do período [from, to]
+func buildReport(report ReportConfig, services []Service, from, to time.Time) (string, string) {
+	total := reportLine{Name: "All services"}
+	groups := map[string]*reportLine{}
+	lines := []reportLine{}
+	for _, service := range services {
+		if len(report.Groups) > 0 && !slices.Contains(report.Groups, service.Group) {
+			continue
+		}
+		sla := computeSLA(service, from, to)
+		if sla.MonitoredSeconds == 0 {
+			continue
+		}
+		line := reportLine{Name: service.Description, UptimePercent: sla.UptimePercent, Outages: sla.Outages, DowntimeSeconds: sla.DowntimeSeconds, monitored: sla.MonitoredSeconds}
+		lines = append(lines, line)
+
+		group := service.Group
+		if group == "" {
+			group = "(no group)"
+		}
+		if groups[group] == nil {
+			groups[group] = &reportLine{Name: group}
+		}
+		for _, sum := range []*reportLine{groups[group], &total} {
+			sum.Outages += line.Outages
+			sum.DowntimeSeconds += line.DowntimeSeconds
+			sum.monitored += line.monitored
+		}
+	}
+	uptime := func(line *reportLine) {
+		line.UptimePercent = 100
+		if line.monitored > 0 {
+			line.UptimePercent = (line.monitored - line.DowntimeSeconds) / line.monitored * 100
+		}
+	}
+	uptime(&total)
+
+	// Piores serviços: menor uptime e, no empate, maior tempo fora do ar
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].UptimePercent != lines[j].UptimePercent {
+			return lines[i].UptimePercent < lines[j].UptimePercent
+		}
+		return lines[i].DowntimeSeconds > lines[j].DowntimeSeconds
+	})
+
+	body := &strings.Builder{}
+	fmt.Fprintf(body, "Period: %s to %s\r\n\r\n", from.Format("2006-01-02 15:04 MST"), to.Format("2006-01-02 15:04 MST"))
+	fmt.Fprintf(body, "Uptime: %.3f%%\r\n", total.UptimePercent)
+	fmt.Fprintf(body, "Outages: %d (%s offline in total)\r\n", total.Outages, formatDuration(time.Duration(total.DowntimeSeconds*float64(time.Second))))
+	fmt.Fprintf(body, "Services monitored: %d\r\n", len(lines))
+
+	body.WriteString("\r\nUptime per group:\r\n")
+	names := []string{}
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group := groups[name]
+		uptime(group)
+		fmt.Fprintf(body, "  %-30s %8.3f%%  %d outages\r\n", group.Name, group.UptimePercent, group.Outages)
+	}
+
+	fmt.Fprintf(body, "\r\nWorst performers:\r\n")
+	for i, line := range lines {
+		if i >= report.Top || line.UptimePercent == 100 {
+			break
+		}
+		fmt.Fprintf(body, "  %-30s %8.3f%%  %d outages, %s offline\r\n", line.Name, line.UptimePercent, line.Outages, formatDuration(time.Duration(line.DowntimeSeconds*float64(time.Second))))
+	}
+	if len(lines) == 0 || lines[0].UptimePercent == 100 {
+		body.WriteString("  None: every service was up for the whole period\r\n")
+	}
+	if url := getConfig().PublicURL; url != "" {
+		fmt.Fprintf(body, "\r\nDashboard: %s\r\n", url)
+	}
+
+	subject := fmt.Sprintf("[REPORT] %s: %.2f%% uptime, %d outages", report.Name, total.UptimePercent, total.Outages)
+	return subject, body.String()
+}
+
+// Função para enviar o relatório pelo servidor SMTP da seção [email]
+func sendReport(report ReportConfig, at time.Time) error {
+	config := getConfig().Email
+	to := report.To
+	if len(to) == 0 {
+		to = config.To
+	}
+	if config.Host == "" || len(to) == 0 {
+		return fmt.Errorf("configure host e destinatários na seção [email] (ou to= na seção [report.%s])", report.Name)
+	}
+	subject, body := buildReport(report, snapshotServices(), at.Add(-report.Period), at)
+	return sendMail(config, to, subject, body)
+}
+
+// Função para enviar os relatórios nos horários agendados. As alterações das seções [report.<nome>]
+// valem sem reiniciar o processo; um relatório com a agenda alterada é reagendado.
+func runReports() {
+	next := map[string]time.Time{} // Próximo envio por relatório, indexado pelo nome e pela agenda
+	for {
+		now := time.Now()
+		for _, report := range getConfig().Reports {
+			key := report.Name + "\x00" + report.Spec
+			at, ok := next[key]
+			if !ok {
+				next[key] = report.Schedule.Next(now)
+				continue
+			}
+			if now.Before(at) {
+				continue
+			}
+			next[key] = report.Schedule.Next(now)
+			if !report.Enabled || !isLeader() {
+				continue // No par de alta disponibilidade, só o líder envia os relatórios
+			}
+			go func() {
+				if err := sendReport(report, now); err != nil {
+					log.Printf(tr("Erro ao enviar o relatório [%s]: %v\n"), report.Name, err)
+					return
+				}
+				log.Printf(tr("Relatório [%s] enviado\n"), report.Name)
+			}()
+		}
+		time.Sleep(30 * time.Second)
+	}
+}
+
+// Handler para pré-visualizar um relatório (texto do e-mail) com os dados atuais
+func previewReportHandler(w http.ResponseWriter, r *http.Request) {
+	for _, report := range getConfig().Reports {
+		if report.Name == r.PathValue("name") {
+			now := time.Now()
+			subject, body := buildReport(report, snapshotServices(), now.Add(-report.Period), now)
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprintf(w, "Subject: %s\r\n\r\n%s", subject, body)
+			return
+		}
+	}
+	http.Error(w, "Relatório não encontrado", http.StatusNotFound)
+}
+
+// Handler para enviar um relatório imediatamente (ex.: para testar a configuração)
+func sendReportHandler(w http.ResponseWriter, r *http.Request) {
+	for _, report := range getConfig().Reports {
+		if report.Name == r.PathValue("name") {
+			if err := sendReport(report, time.Now()); err != nil {
+				http.Error(w, "Erro ao enviar o relatório: "+err.Error(), http.StatusBadGateway)
+				return
+			}
+			auditRequest(r, "report.send", report.Name, nil, nil)
+			writeJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+			return
+		}
+	}
+	http.Error(w, "Relatório não encontrado", http.StatusNotFound)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Seções dos canais de notificação que aceitam filtros (além das seções [webhook.<nome>])
+var notifierSections = []string{"email", "slack", "telegram", "discord", "teams", "pagerduty", "opsgenie", "sms", "ntfy", "gotify"}
+
+// Filtros de um canal de notificação: o canal só recebe os serviços que atendem a todos os filtros informados
+type NotifierRoute struct {
+	Groups []string       // match_group: grupos aceitos, separados por vírgula
+	Tags   []string       // match_tag: tags aceitas (opção tags= do serviço), separadas por vírgula
+	Name   *regexp.Regexp // match_name: expressão regular aplicada à descrição do serviço
+}
+
+// Função para listar as seções de canais presentes no config.ini, incluindo as [webhook.<nome>]
+func notifierSectionNames(cfg *ini.File) []string {
+	sections := slices.Clone(notifierSections)
+	for _, section := range cfg.Section("webhook").ChildSections() {
+		sections = append(sections, section.Name())
+	}
+	return sections
+}
+
+// Função para ler os filtros match_group, match_tag e match_name das seções dos canais
+func loadNotifierRoutes(cfg *ini.File) map[string]NotifierRoute {
+	routes := map[string]NotifierRoute{}
+	for _, name := range notifierSectionNames(cfg) {
+		section, err := cfg.GetSection(name)
+		if err != nil {
+			continue
+		}
+		route := NotifierRoute{
+			Groups: splitList(section.Key("match_group").String()),
+			Tags:   splitList(section.Key("match_tag").String()),
+		}
+		if pattern := section.Key("match_name").String(); pattern != "" {
+			if route.Name, err = regexp.Compile(pattern); err != nil {
+				log.Printf(tr("match_name inválido na seção [%s], filtro por nome ignorado: %v\n"), name, err)
+			}
+		}
+		if route.Groups != nil || route.Tags != nil || route.Name != nil {
+			routes[name] = route
+		}
+	}
+	return routes
+}
+
+// Função para separar uma lista por vírgulas, descartando itens vazios (nil se não houver itens)
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Função para verificar se o serviço atende aos filtros do canal
+func (route NotifierRoute) matches(service Service) bool {
+	if route.Groups != nil && !slices.Contains(route.Groups, service.Group) {
+		return false
+	}
+	if route.Tags != nil && !slices.ContainsFunc(route.Tags, func(tag string) bool { return hasTag(service, tag) }) {
+		return false
+	}
+	if route.Name != nil && !route.Name.MatchString(service.Description) {
+		return false
+	}
+	return true
+}
+
+// Função para verificar se o canal deve receber as notificações do serviço (canais sem filtros recebem todas)
+func routeAllows(config *Config, n notifier, service Service) bool {
+	route, ok := config.Routes[channelName(n)]
+	return !ok || route.matches(service)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	_ "time/tzdata" // Fusos horários embutidos, para servidores sem a base do sistema (ex.: Windows)
+
+	"gopkg.in/ini.v1"
+)
+
+// Janela de horário em que um canal pode ser acionado
+type scheduleWindow struct {
+	Days  [7]bool // Dias da semana aceitos, indexados por time.Weekday
+	Start int     // Início, em minutos desde a meia-noite
+	End   int     // Fim, em minutos desde a meia-noite (menor que o início = atravessa a meia-noite)
+}
+
+// Horário de funcionamento de um canal de notificação
+type NotifierSchedule struct {
+	Windows  []scheduleWindow
+	Location *time.Location // Fuso horário das janelas (timezone=, padrão o do servidor)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Função para ler as chaves schedule= e timezone= das seções dos canais
+func loadNotifierSchedules(cfg *ini.File) map[string]NotifierSchedule {
+	schedules := map[string]NotifierSchedule{}
+	for _, name := range notifierSectionNames(cfg) {
+		section, err := cfg.GetSection(name)
+		if err != nil || section.Key("schedule").String() == "" {
+			continue
+		}
+		schedule := NotifierSchedule{Location: time.Local}
+		if schedule.Windows, err = parseSchedule(section.Key("schedule").String()); err != nil {
+			log.Printf(tr("schedule inválido na seção [%s], canal acionado a qualquer hora: %v\n"), name, err)
+			continue
+		}
+		if zone := section.Key("timezone").String(); zone != "" {
+			if schedule.Location, err = time.LoadLocation(zone); err != nil {
+				log.Printf(tr("timezone inválido na seção [%s], usando o fuso do servidor: %v\n"), name, err)
+				schedule.Location = time.Local
+			}
+		}
+		schedules[name] = schedule
+	}
+	return schedules
+}
+
+// Função para interpretar janelas no formato "mon-fri 08:00-18:00; sat 09:00-12:00"
+// (sem dias, a janela vale para todos os dias)
+func parseSchedule(value string) ([]scheduleWindow, error) {
+	windows := []scheduleWindow{}
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("janela inválida %q", part)
+		}
+
+		window := scheduleWindow{}
+		hours := fields[len(fields)-1]
+		if len(fields) == 2 {
+			if err := parseWeekdays(fields[0], &window.Days); err != nil {
+				return nil, err
+			}
+		} else {
+			window.Days = [7]bool{true, true, true, true, true, true, true}
+		}
+
+		start, end, ok := strings.Cut(hours, "-")
+		if !ok {
+			return nil, fmt.Errorf("horário inválido %q (use 08:00-18:00)", hours)
+		}
+		var err error
+		if window.Start, err = parseClock(start); err != nil {
+			return nil, err
+		}
+		if window.End, err = parseClock(end); err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("nenhuma janela informada")
+	}
+	return windows, nil
+}
+
+// Função para interpretar os dias da janela: "mon-fri", "sat,sun" ou "mon"
+func parseWeekdays(value string, days *[7]bool) error {
+	for _, item := range strings.Split(value, ",") {
+		from, to, isRange := strings.Cut(strings.ToLower(item), "-")
+		first, ok := weekdayNames[from]
+		if !ok {
+			return fmt.Errorf("dia da semana inválido %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdayNames[to]; !ok {
+				return fmt.Errorf("dia da semana inválido %q", to)
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Função para converter "HH:MM" em minutos desde a meia-noite ("24:00" é aceito como fim do dia)
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("horário inválido %q (use HH:MM)", value)
+	}
+	return clock.Hour()*60 + clock.Minute(), nil
+}
+
+// Função para verificar se o momento está dentro de alguma janela do horário
+func (s NotifierSchedule) allows(at time.Time) bool {
+	local := at.In(s.Location)
+	minute := local.Hour()*60 + local.Minute()
+	yesterday := (local.Weekday() + 6) % 7
+	for _, w := range s.Windows {
+		if w.Start < w.End {
+			if w.Days[local.Weekday()] && minute >= w.Start && minute < w.End {
+				return true
+			}
+			continue
+		}
+		// Janela que atravessa a meia-noite: vale a partir do início no dia e até o fim no dia seguinte
+		if (w.Days[local.Weekday()] && minute >= w.Start) || (w.Days[yesterday] && minute < w.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// Função para verificar se o canal pode ser acionado no momento (canais sem schedule= podem sempre)
+func scheduleAllows(config *Config, n notifier, at time.Time) bool {
+	schedule, ok := config.Schedules[channelName(n)]
+	return !ok || schedule.allows(at)
+}
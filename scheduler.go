@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+const configWatchInterval = 2 * time.Second // Intervalo entre as verificações de alteração do config.ini
+
+// Função que verifica o serviço i e registra o resultado: checkAndUpdate na instância central e o envio do
+// resultado à central nas sondas remotas
+type checkFunc func(ctx context.Context, services []Service, i int)
+
+// Função para ler workers da seção [general]: quantas verificações rodam ao mesmo tempo, para que alguns hosts
+// fora do ar (cada um esperando o timeout da conexão) não atrasem a verificação dos demais
+func loadWorkers(cfg *ini.File) int {
+	workers := cfg.Section("general").Key("workers").MustInt(10)
+	if workers < 1 {
+		log.Println(tr("workers inválido (mínimo 1), usando 10"))
+		workers = 10
+	}
+	return workers
+}
+
+// Variação aleatória aplicada aos intervalos das verificações (jitter), em fração do intervalo ou fixa
+type JitterConfig struct {
+	Fraction float64       // Fração do intervalo (jitter=10%)
+	Fixed    time.Duration // Variação fixa (jitter=2s), usada quando Fraction é 0
+}
+
+// Função para ler jitter da seção [general]: quanto cada intervalo entre verificações varia para mais ou para
+// menos, para que centenas de serviços não sejam verificados ao mesmo tempo
+func loadJitter(cfg *ini.File) JitterConfig {
+	value := strings.TrimSpace(cfg.Section("general").Key("jitter").MustString("10%"))
+	if value == "0" || value == "0%" {
+		return JitterConfig{}
+	}
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		fraction, err := strconv.ParseFloat(percent, 64)
+		if err == nil && fraction >= 0 && fraction <= 50 {
+			return JitterConfig{Fraction: fraction / 100}
+		}
+	} else if fixed, err := parseRange(value, 0); err == nil {
+		return JitterConfig{Fixed: fixed}
+	}
+	log.Printf(tr("jitter inválido %q (use 0%% a 50%% ou uma duração), usando 10%%\n"), value)
+	return JitterConfig{Fraction: 0.1}
+}
+
+// Função para calcular a variação máxima de um intervalo, limitada à metade dele
+func (j JitterConfig) spread(interval time.Duration) time.Duration {
+	spread := j.Fixed
+	if j.Fraction > 0 {
+		spread = time.Duration(float64(interval) * j.Fraction)
+	}
+	return min(spread, interval/2)
+}
+
+// Função para sortear o atraso da primeira verificação de um serviço, entre 0 e a variação do intervalo, para
+// espalhar as verificações que começariam juntas
+func (j JitterConfig) initialDelay(interval time.Duration) time.Duration {
+	if spread := j.spread(interval); spread > 0 {
+		return rand.N(spread)
+	}
+	return 0
+}
+
+// Função para sortear o próximo intervalo de um serviço, entre interval-variação e interval+variação
+func (j JitterConfig) next(interval time.Duration) time.Duration {
+	if spread := j.spread(interval); spread > 0 {
+		return interval - spread + rand.N(2*spread)
+	}
+	return interval
+}
+
+// Espaçamento das verificações dos serviços fora do ar há muito tempo (seção [backoff])
+type BackoffConfig struct {
+	After  time.Duration // Tempo fora do ar antes de espaçar as verificações (0 desabilita)
+	Factor float64       // Multiplicador do intervalo a cada nova falha
+	Max    time.Duration // Intervalo máximo entre as verificações espaçadas
+}
+
+// Função para ler a seção [backoff] do config.ini
+func loadBackoffConfig(cfg *ini.File) BackoffConfig {
+	section := cfg.Section("backoff")
+	config := BackoffConfig{Factor: section.Key("factor").MustFloat64(2)}
+	if value := section.Key("after").MustString("0"); value != "0" {
+		var err error
+		if config.After, err = parseRange(value, 0); err != nil {
+			log.Printf(tr("after inválido %q na seção [backoff], espaçamento desabilitado\n"), value)
+		}
+	}
+	if config.Factor <= 1 {
+		log.Println(tr("factor inválido na seção [backoff] (deve ser maior que 1), usando 2"))
+		config.Factor = 2
+	}
+	var err error
+	if config.Max, err = parseRange(section.Key("max_interval").String(), 10*time.Minute); err != nil {
+		log.Println(tr("max_interval inválido na seção [backoff], usando 10m"))
+		config.Max = 10 * time.Minute
+	}
+	return config
+}
+
+// Função para calcular o próximo intervalo espaçado de um serviço fora do ar há mais de after: o intervalo
+// anterior multiplicado por factor, até max_interval. Serviços online, pausados, fora do ar há menos tempo ou
+// com a opção backoff=false voltam ao intervalo normal (0).
+func (b BackoffConfig) next(service Service, interval, previous time.Duration) time.Duration {
+	if b.After == 0 || service.Status != "red" || service.DownSince == nil || time.Since(*service.DownSince) < b.After {
+		return 0
+	}
+	if enabled, err := strconv.ParseBool(service.Options["backoff"]); err == nil && !enabled {
+		return 0
+	}
+	return min(time.Duration(float64(max(previous, interval))*b.Factor), max(b.Max, interval))
+}
+
+// Função para acompanhar o espaçamento das verificações de um serviço, avisando no log quando ele começa e
+// quando o serviço volta ao intervalo normal
+func trackBackoff(service Service, interval, previous time.Duration) time.Duration {
+	delay := getConfig().Backoff.next(service, interval, previous)
+	if previous == 0 && delay > 0 {
+		log.Printf(tr("Serviço [%s] fora do ar há %s: verificações espaçadas até %s\n"), service.Description, formatDuration(time.Since(*service.DownSince)), formatDuration(getConfig().Backoff.Max))
+	} else if previous > 0 && delay == 0 {
+		log.Printf(tr("Serviço [%s] voltou a ser verificado a cada %s\n"), service.Description, interval)
+	}
+	return delay
+}
+
+// Função para acompanhar o config.ini (e os serviços descobertos no Kubernetes) e reiniciar o agendamento dos
+// serviços quando ele muda, até o contexto ser cancelado (encerramento do processo); retorna depois que as verificações em andamento terminam. No par
+// de alta disponibilidade, os serviços só são verificados enquanto esta instância é a líder.
+func monitorServices(ctx context.Context, services *[]Service) {
+	leading := isLeader()
+	stop := func() {}
+	if leading {
+		stop = startScheduler(ctx, *services, nil, checkAndUpdate)
+	}
+	defer func() { stop() }()
+	ticker := time.NewTicker(configWatchInterval)
+	defer ticker.Stop()
+	discovered := kubeVersion.Load()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if leader := isLeader(); leader != leading {
+			leading = leader
+			stop()
+			stop = func() {}
+			if leading {
+				log.Println(tr("Assumindo a verificação dos serviços"))
+				takeOver(services)
+				stop = startScheduler(ctx, *services, nil, checkAndUpdate)
+			} else {
+				haSyncedAt = time.Now() // O histórico passa a ser gravado pelo novo líder
+			}
+		}
+		if hasConfigFileChanged() {
+			log.Println(tr("Arquivo config.ini modificado, recarregando configurações..."))
+		} else if kubeVersion.Load() != discovered {
+			log.Println(tr("Serviços descobertos no Kubernetes alterados, recarregando..."))
+		} else {
+			continue
+		}
+		discovered = kubeVersion.Load()
+		stop() // Interrompe as verificações em andamento antes de trocar a lista de serviços
+		known := map[string]bool{}
+		for _, service := range *services {
+			known[service.Description] = true
+		}
+		restartServices(services)
+		hub.publish(snapshotServices())
+		if leading {
+			stop = startScheduler(ctx, *services, known, checkAndUpdate)
+		}
+	}
+}
+
+// Função para agendar cada serviço de forma independente, com o próprio timer, limitando as verificações
+// simultâneas a workers e registrando cada resultado com check; retorna a função que encerra o agendamento e
+// espera as verificações em andamento. Os serviços novos (todos na inicialização, quando known é nil, ou os
+// que não estão em known, adicionados na recarga) são verificados imediatamente, em vez de exibidos como
+// unknown por um intervalo inteiro, e o snapshot é publicado assim que essas primeiras verificações terminam;
+// os demais começam após o atraso aleatório do jitter.
+func startScheduler(parent context.Context, services []Service, known map[string]bool, check checkFunc) func() {
+	ctx, cancel := context.WithCancel(parent)
+	slots := make(chan struct{}, getConfig().Workers)
+	jitter := getConfig().Jitter
+	var wg, initial sync.WaitGroup
+	pending := 0
+	for i := range services {
+		delay := jitter.initialDelay(serviceInterval(services[i]))
+		checked := func() {}
+		if known == nil || !known[services[i].Description] {
+			delay = 0
+			pending++
+			initial.Add(1)
+			checked = sync.OnceFunc(initial.Done)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer checked() // Agendamento encerrado antes da primeira verificação
+			scheduleService(ctx, services, i, slots, delay, check, checked)
+		}()
+	}
+	if pending > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			initial.Wait()
+			if ctx.Err() == nil {
+				log.Printf(tr("Primeira verificação de %d serviço(s) concluída\n"), pending)
+				hub.publish(snapshotServices())
+			}
+		}()
+	}
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// Função para verificar um serviço a cada intervalo (opção interval= ou check_interval, com a variação de jitter),
+// contado a partir do fim da verificação anterior, até o agendamento ser encerrado. Cada verificação tem o prazo
+// de timeout, limitado ao intervalo, para que um host que não responde não prenda o worker além dele. Um serviço
+// fora do ar há muito tempo é verificado com intervalos crescentes (seção [backoff]). A primeira verificação
+// acontece após delay, e checked é chamada ao fim dela.
+func scheduleService(ctx context.Context, services []Service, i int, slots chan struct{}, delay time.Duration, check checkFunc, checked func()) {
+	jitter := getConfig().Jitter
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var backoff time.Duration // Intervalo espaçado atual (0 = intervalo normal)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// Espera um worker livre
+		start := time.Now()
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+		interval := serviceInterval(services[i])
+		checkCtx, cancel := context.WithTimeout(ctx, min(getConfig().Timeout, interval))
+		check(checkCtx, services, i)
+		cancel()
+		<-slots
+		checked()
+		duration := time.Since(start)
+		recordCycleMetrics(duration)
+		trackOverrun(services[i], duration, interval)
+
+		backoff = trackBackoff(services[i], interval, backoff)
+		timer.Reset(jitter.next(max(interval, backoff)))
+	}
+}
+
+// Função para registrar a duração da verificação (incluindo a espera por um worker) e avisar no log quando ela
+// passa a exceder o intervalo do serviço, e quando volta a caber nele
+func trackOverrun(service Service, duration, interval time.Duration) {
+	overrun, changed := recordCheckDuration(service, duration, interval)
+	if !changed {
+		return
+	}
+	if overrun {
+		log.Printf(tr("Verificação do serviço [%s] levou %s, acima do intervalo de %s (aumente workers ou o intervalo)\n"), service.Description, duration.Round(time.Millisecond), interval)
+	} else {
+		log.Printf(tr("Verificação do serviço [%s] voltou a caber no intervalo de %s\n"), service.Description, interval)
+	}
+}
+
+// Função para obter o intervalo entre as verificações de um serviço
+func serviceInterval(service Service) time.Duration {
+	if service.Interval > 0 {
+		return service.Interval
+	}
+	return checkInterval
+}
+
+// Função para obter o menor intervalo entre as verificações dos serviços (check_interval sem serviços), base da
+// idade máxima aceitável da última verificação na readiness
+func shortestInterval() time.Duration {
+	var shortest time.Duration
+	for _, service := range snapshotServices() {
+		if interval := serviceInterval(service); shortest == 0 || interval < shortest {
+			shortest = interval
+		}
+	}
+	if shortest == 0 {
+		return checkInterval
+	}
+	return shortest
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [server]
+type ServerConfig struct {
+	RateLimit    float64  // Requisições por segundo permitidas por IP (0 desabilita)
+	RateBurst    int      // Rajada máxima de requisições por IP
+	MaxWSClients int      // Máximo de clientes WebSocket simultâneos (0 = ilimitado)
+	CORSOrigins  []string // Origens externas autorizadas (CORS e WebSocket); "*" libera todas
+	WSCompress   int      // Nível da compressão permessage-deflate do WebSocket (1 a 9; 0 desabilita)
+	AssetsDir    string   // Diretório com arquivos do front-end que substituem os incluídos no binário (vazio = apenas os do binário)
+}
+
+// Função para ler a seção [server] do config.ini
+func loadServerConfig(cfg *ini.File) ServerConfig {
+	section := cfg.Section("server")
+	config := ServerConfig{
+		RateLimit:    section.Key("rate_limit").MustFloat64(0),
+		RateBurst:    section.Key("rate_burst").MustInt(20),
+		MaxWSClients: section.Key("max_ws_clients").MustInt(0),
+		CORSOrigins:  section.Key("cors_origins").Strings(","),
+		WSCompress:   section.Key("ws_compression").MustInt(1),
+		AssetsDir:    section.Key("assets_dir").String(),
+	}
+	if config.RateBurst < 1 {
+		config.RateBurst = 1
+	}
+	if config.WSCompress < 0 || config.WSCompress > 9 {
+		log.Println(tr("ws_compression inválido na seção [server] (use 0 a 9), usando 1"))
+		config.WSCompress = 1
+	}
+	return config
+}
+
+// Limitador de requisições de um IP
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+var limitersMu sync.Mutex
+var limiters = map[string]*ipLimiter{} // Limitadores por IP de origem
+
+// Função para obter o IP de origem da requisição
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Função para verificar se o IP ainda pode fazer requisições
+func allowRequest(ip string, config ServerConfig) bool {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	entry, ok := limiters[ip]
+	limit := rate.Limit(config.RateLimit)
+	if !ok || entry.limiter.Limit() != limit || entry.limiter.Burst() != config.RateBurst {
+		// Novo IP ou limites alterados no config.ini
+		entry = &ipLimiter{limiter: rate.NewLimiter(limit, config.RateBurst)}
+		limiters[ip] = entry
+	}
+	entry.lastSeen = time.Now()
+	return entry.limiter.Allow()
+}
+
+// Função para remover periodicamente os limitadores de IPs inativos
+func cleanupLimiters() {
+	for {
+		time.Sleep(time.Minute)
+		limitersMu.Lock()
+		for ip, entry := range limiters {
+			if time.Since(entry.lastSeen) > 3*time.Minute {
+				delete(limiters, ip)
+			}
+		}
+		limitersMu.Unlock()
+	}
+}
+
+// Middleware de limitação de requisições por IP (responde 429 quando excedido)
+func rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config := getConfig().Server
+		if config.RateLimit > 0 && !allowRequest(clientIP(r), config) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Muitas requisições, tente novamente mais tarde", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Função para reservar uma vaga de cliente WebSocket, respeitando max_ws_clients
+func acquireWSClient() bool {
+	max := int64(getConfig().Server.MaxWSClients)
+	if n := wsClients.Add(1); max > 0 && n > max {
+		wsClients.Add(-1)
+		log.Println(tr("Limite de clientes WebSocket atingido, conexão recusada"))
+		return false
+	}
+	return true
+}
+
+// Função para liberar a vaga de um cliente WebSocket
+func releaseWSClient() {
+	wsClients.Add(-1)
+}
+
+// Função para verificar se a origem está autorizada em cors_origins
+func originAllowed(origin string, config ServerConfig) bool {
+	for _, allowed := range config.CORSOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware que aplica os cabeçalhos CORS para as origens autorizadas e responde aos preflights
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		config := getConfig().Server
+		if origin != "" && originAllowed(origin, config) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if !slices.Contains(config.CORSOrigins, "*") { // Credenciais apenas para origens listadas explicitamente
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			w.Header().Add("Vary", "Origin")
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Função usada pelo upgrader para validar a origem das conexões WebSocket:
+// aceita a mesma origem do dashboard e as origens de cors_origins
+func checkWSOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true // Clientes que não são navegadores não enviam Origin
+	}
+	u, err := url.Parse(origin)
+	if err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	if originAllowed(origin, getConfig().Server) {
+		return true
+	}
+	log.Println(tr("Conexão WebSocket recusada para a origem:"), origin)
+	return false
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const shutdownTimeout = 10 * time.Second // Prazo para as requisições em andamento terminarem ao encerrar
+
+var serversMu sync.Mutex
+var servers []*http.Server // Servidores HTTP iniciados (principal, redirecionamento, página pública, debug, sondas)
+
+// Função para registrar um servidor HTTP encerrado com Server.Shutdown ao finalizar o processo
+func registerServer(server *http.Server) *http.Server {
+	serversMu.Lock()
+	servers = append(servers, server)
+	serversMu.Unlock()
+	return server
+}
+
+// Função para verificar se o erro retornado por ListenAndServe vem do encerramento ordenado
+func serverClosed(err error) bool {
+	return errors.Is(err, http.ErrServerClosed)
+}
+
+// Função para encerrar o processo de forma ordenada: para as verificações (as em andamento são interrompidas e
+// descartadas), fecha os WebSockets e os streams SSE com uma mensagem de encerramento, aguarda as requisições em
+// andamento, envia os pontos pendentes ao TSDB e grava no banco os resultados ainda enfileirados
+func gracefulShutdown(monitorDone <-chan struct{}) {
+	log.Println(tr("Sinal de encerramento recebido, finalizando..."))
+	<-monitorDone
+	hub.shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	serversMu.Lock()
+	active := servers
+	serversMu.Unlock()
+	var wg sync.WaitGroup
+	for _, server := range active {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				log.Printf(tr("Erro ao encerrar o servidor %s: %v\n"), server.Addr, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	flushTSDB(getConfig().TSDB)
+	releaseLeadership()
+	closeStorage()
+	log.Println(tr("Monitor encerrado."))
+}
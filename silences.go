@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Silêncio: suspende as notificações dos serviços que atendem a todos os critérios informados
+type silence struct {
+	ID        string    `json:"id"`
+	Service   string    `json:"service,omitempty"` // Descrição do serviço
+	Group     string    `json:"group,omitempty"`
+	Tag       string    `json:"tag,omitempty"` // Tag da opção tags= do serviço
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+var silencesMu sync.Mutex           // Mutex para proteger os silêncios
+var silences = map[string]silence{} // Silêncios indexados pelo ID
+
+// Função para verificar se o silêncio se aplica ao serviço
+func (s silence) matches(service Service) bool {
+	if s.Service != "" && s.Service != service.Description {
+		return false
+	}
+	if s.Group != "" && s.Group != service.Group {
+		return false
+	}
+	if s.Tag != "" && !hasTag(service, s.Tag) {
+		return false
+	}
+	return true
+}
+
+// Função para verificar se as notificações do serviço estão silenciadas no momento
+func isSilenced(service Service, at time.Time) bool {
+	silencesMu.Lock()
+	defer silencesMu.Unlock()
+	for _, s := range silences {
+		if !at.Before(s.StartsAt) && at.Before(s.EndsAt) && s.matches(service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Função para listar os silêncios ativos, descartando os expirados (os que terminam antes primeiro)
+func activeSilences() []silence {
+	silencesMu.Lock()
+	defer silencesMu.Unlock()
+	now := time.Now()
+	result := []silence{}
+	for id, s := range silences {
+		if !now.Before(s.EndsAt) {
+			delete(silences, id)
+			continue
+		}
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].EndsAt.Before(result[j].EndsAt) })
+	return result
+}
+
+// Handler para listar os silêncios ativos
+func listSilencesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, activeSilences())
+}
+
+// Handler para criar um silêncio: {"service"|"group"|"tag": ..., "duration": "2h" ou "ends_at": "...", "comment": ...}
+func createSilenceHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Service  string `json:"service"`
+		Group    string `json:"group"`
+		Tag      string `json:"tag"`
+		Duration string `json:"duration"`
+		EndsAt   string `json:"ends_at"`
+		Comment  string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Service == "" && request.Group == "" && request.Tag == "" {
+		http.Error(w, "Informe service, group ou tag", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	var endsAt time.Time
+	switch {
+	case request.Duration != "":
+		duration, err := parseRange(request.Duration, 0)
+		if err != nil || duration <= 0 {
+			http.Error(w, "Parâmetro duration inválido (ex.: 30m, 2h, 1d)", http.StatusBadRequest)
+			return
+		}
+		endsAt = now.Add(duration)
+	case request.EndsAt != "":
+		var err error
+		if endsAt, err = parseTime(request.EndsAt, time.Time{}); err != nil || !endsAt.After(now) {
+			http.Error(w, "Parâmetro ends_at inválido ou no passado", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Informe duration ou ends_at", http.StatusBadRequest)
+		return
+	}
+
+	s := silence{
+		ID:        generateToken()[:16],
+		Service:   request.Service,
+		Group:     request.Group,
+		Tag:       request.Tag,
+		Comment:   request.Comment,
+		CreatedBy: currentUser(r),
+		StartsAt:  now,
+		EndsAt:    endsAt,
+	}
+	silencesMu.Lock()
+	silences[s.ID] = s
+	silencesMu.Unlock()
+
+	log.Printf(tr("Silêncio %s criado por %s até %s\n"), s.ID, s.CreatedBy, s.EndsAt.Format("2006-01-02 15:04:05"))
+	auditRequest(r, "silence.create", s.ID, nil, s)
+	writeJSON(w, http.StatusCreated, s)
+}
+
+// Handler para encerrar um silêncio antes do prazo
+func deleteSilenceHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	silencesMu.Lock()
+	s, ok := silences[id]
+	delete(silences, id)
+	silencesMu.Unlock()
+
+	if !ok {
+		http.Error(w, "Silêncio não encontrado", http.StatusNotFound)
+		return
+	}
+	log.Printf(tr("Silêncio %s encerrado por %s\n"), id, currentUser(r))
+	auditRequest(r, "silence.delete", id, s, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// Configurações da seção [slack]
+type SlackConfig struct {
+	Enabled    bool
+	WebhookURL string            // Incoming webhook (o canal é o definido no próprio webhook)
+	BotToken   string            // Token de bot (xoxb-...), usado com chat.postMessage
+	Channel    string            // Canal padrão quando o envio é feito com o token de bot
+	Groups     map[string]string // Canal ou URL de webhook por grupo, da seção [slack.groups]
+}
+
+// Função para ler as seções [slack] e [slack.groups] do config.ini
+func loadSlackConfig(cfg *ini.File) SlackConfig {
+	section := cfg.Section("slack")
+	config := SlackConfig{
+		Enabled:    section.Key("enabled").MustBool(false),
+		WebhookURL: section.Key("webhook_url").String(),
+		BotToken:   section.Key("bot_token").String(),
+		Channel:    section.Key("channel").String(),
+		Groups:     map[string]string{},
+	}
+	for _, key := range cfg.Section("slack.groups").Keys() {
+		config.Groups[key.Name()] = key.String()
+	}
+	return config
+}
+
+// Notificação no Slack
+type slackNotifier struct {
+	config SlackConfig
+}
+
+func (n slackNotifier) Name() string { return "slack" }
+
+// Função para definir o destino da mensagem: o grupo pode indicar outro canal
+// (com o token de bot) ou outra URL de incoming webhook
+func (n slackNotifier) destination(service Service) (webhookURL, channel string) {
+	webhookURL, channel = n.config.WebhookURL, n.config.Channel
+	if target, ok := n.config.Groups[service.Group]; ok && service.Group != "" {
+		if strings.HasPrefix(target, "https://") {
+			return target, ""
+		}
+		channel = target
+	}
+	if n.config.BotToken != "" && channel != "" {
+		return "", channel // Com token de bot e canal definido, o envio usa chat.postMessage
+	}
+	return webhookURL, channel
+}
+
+// Função para publicar a mudança de status como um anexo colorido (verde ou vermelho)
+func (n slackNotifier) Notify(change stateChange) error {
+	color := "#2eb886"
+	if change.To == "red" {
+		color = "#e01e5a"
+	}
+	fields := []map[string]interface{}{
+		{"title": tr("Response time"), "value": change.Service.ResponseTime, "short": true},
+		{"title": tr("Address"), "value": change.address(), "short": true},
+	}
+	if change.Service.Group != "" {
+		fields = append(fields, map[string]interface{}{"title": tr("Group"), "value": change.Service.Group, "short": true})
+	}
+	if change.Service.Message != "" {
+		fields = append(fields, map[string]interface{}{"title": tr("Message"), "value": change.Service.Message})
+	}
+	payload := map[string]interface{}{
+		"text": change.title(),
+		"attachments": []map[string]interface{}{{
+			"color":    color,
+			"fallback": change.title(),
+			"title":    change.title(),
+			"text":     change.durationText(),
+			"fields":   fields,
+			"ts":       change.Time.Unix(),
+		}},
+	}
+
+	webhookURL, channel := n.destination(change.Service)
+	if webhookURL != "" {
+		_, err := postJSON(webhookURL, nil, payload)
+		return err
+	}
+	if n.config.BotToken == "" || channel == "" {
+		return fmt.Errorf("informe webhook_url ou bot_token e channel na seção [slack]")
+	}
+
+	payload["channel"] = channel
+	body, err := postJSON(slackPostMessageURL, map[string]string{"Authorization": "Bearer " + n.config.BotToken}, payload)
+	if err != nil {
+		return err
+	}
+	// A API do Slack responde 200 mesmo em caso de erro, informando-o no corpo
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &result) == nil && !result.OK {
+		return fmt.Errorf("chat.postMessage: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Disponibilidade dos serviços de um grupo no relatório de SLA
+type slaGroupReport struct {
+	Group    string
+	Services []slaReport
+	Total    slaReport // Uptime ponderado pelo tempo monitorado, quedas e tempo fora do ar somados
+}
+
+// Função para interpretar o período do relatório: month (mês anterior), week (semana anterior, de segunda
+// a domingo) ou uma duração terminando agora (ex.: 30d)
+func parseReportRange(value string, now time.Time) (time.Time, time.Time, string, error) {
+	switch value {
+	case "", "month":
+		to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		from := to.AddDate(0, -1, 0)
+		return from, to, from.Format("January 2006"), nil
+	case "week":
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to := today.AddDate(0, 0, -(int(today.Weekday())+6)%7) // Segunda-feira desta semana
+		from := to.AddDate(0, 0, -7)
+		return from, to, "Week of " + from.Format("2006-01-02"), nil
+	}
+	window, err := parseRange(value, 0)
+	if err != nil {
+		return time.Time{}, time.Time{}, "", err
+	}
+	return now.Add(-window), now, "Last " + value, nil
+}
+
+// Função para calcular a disponibilidade de cada serviço no período, agrupada por grupo
+func buildSLAGroupReports(services []Service, groups []string, from, to time.Time) []slaGroupReport {
+	index := map[string]*slaGroupReport{}
+	names := []string{}
+	for _, service := range services {
+		if len(groups) > 0 && !slices.Contains(groups, service.Group) {
+			continue
+		}
+		group, ok := index[service.Group]
+		if !ok {
+			group = &slaGroupReport{Group: service.Group, Total: slaReport{Service: "Total", From: from, To: to}}
+			index[service.Group] = group
+			names = append(names, service.Group)
+		}
+		report := computeSLA(service, from, to)
+		group.Services = append(group.Services, report)
+		group.Total.Outages += report.Outages
+		group.Total.DowntimeSeconds += report.DowntimeSeconds
+		group.Total.MonitoredSeconds += report.MonitoredSeconds
+	}
+	sort.Strings(names)
+
+	result := []slaGroupReport{}
+	for _, name := range names {
+		group := index[name]
+		group.Total.UptimePercent = 100
+		if group.Total.MonitoredSeconds > 0 {
+			group.Total.UptimePercent = (group.Total.MonitoredSeconds - group.Total.DowntimeSeconds) / group.Total.MonitoredSeconds * 100
+		}
+		if group.Group == "" {
+			group.Group = "No group"
+		}
+		result = append(result, *group)
+	}
+	return result
+}
+
+// Dados do relatório de SLA usados pelo HTML e pelo PDF
+type slaDocument struct {
+	Title     string
+	Period    string
+	From      time.Time
+	To        time.Time
+	Generated time.Time
+	Groups    []slaGroupReport
+}
+
+// Função para formatar uma linha do relatório: uptime, quedas, tempo fora do ar e MTTR
+func slaCells(report slaReport) []string {
+	mttr := "-"
+	if report.MTTRSeconds > 0 {
+		mttr = formatDuration(time.Duration(report.MTTRSeconds * float64(time.Second)))
+	}
+	uptime := "no data"
+	if report.MonitoredSeconds > 0 {
+		uptime = fmt.Sprintf("%.3f%%", report.UptimePercent)
+	}
+	return []string{report.Service, uptime, fmt.Sprint(report.Outages), formatDuration(time.Duration(report.DowntimeSeconds * float64(time.Second))), mttr}
+}
+
+var slaColumns = []string{"Service", "Uptime", "Outages", "Downtime", "MTTR"}
+
+var slaHTMLTemplate = template.Must(template.New("sla").Funcs(template.FuncMap{"cells": slaCells}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - {{.Period}}</title>
+<style>
+body { font-family: Arial, sans-serif; color: #333; margin: 40px; }
+h1 { margin-bottom: 0; }
+.period { color: #777; margin-top: 4px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 24px; }
+th, td { padding: 6px 10px; border-bottom: 1px solid #ddd; text-align: left; }
+td + td, th + th { text-align: right; }
+tr.total td { font-weight: bold; border-top: 2px solid #999; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="period">{{.Period}}: {{.From.Format "2006-01-02 15:04"}} to {{.To.Format "2006-01-02 15:04 MST"}}</p>
+{{range .Groups}}
+<h2>{{.Group}}</h2>
+<table>
+<tr><th>Service</th><th>Uptime</th><th>Outages</th><th>Downtime</th><th>MTTR</th></tr>
+{{range .Services}}<tr>{{range cells .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}<tr class="total">{{range cells .Total}}<td>{{.}}</td>{{end}}</tr>
+</table>
+{{else}}
+<p>No services.</p>
+{{end}}
+<p class="period">Generated at {{.Generated.Format "2006-01-02 15:04 MST"}}</p>
+</body>
+</html>
+`))
+
+// Handler para o relatório de disponibilidade por grupo em HTML ou PDF (para anexar a contratos)
+func slaReportHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	now := time.Now()
+	from, to, period, err := parseReportRange(query.Get("range"), now)
+	if err != nil {
+		http.Error(w, "Parâmetro range inválido (use month, week ou uma duração como 30d)", http.StatusBadRequest)
+		return
+	}
+	document := slaDocument{
+		Title:     "Availability report",
+		Period:    period,
+		From:      from,
+		To:        to,
+		Generated: now,
+		Groups:    buildSLAGroupReports(snapshotServices(), query["group"], from, to),
+	}
+	filename := "sla-" + from.Format("2006-01-02")
+
+	switch query.Get("format") {
+	case "", "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err = slaHTMLTemplate.Execute(w, document)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.pdf"`)
+		_, err = w.Write(slaPDF(document))
+	default:
+		http.Error(w, "Parâmetro format inválido (use html ou pdf)", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Println(tr("Erro ao gerar o relatório de SLA:"), err)
+	}
+}
+
+// Função para montar o relatório em PDF (A4, fontes padrão Helvetica, quebra de página automática)
+func slaPDF(document slaDocument) []byte {
+	pdf := &pdfWriter{}
+	pdf.newPage()
+	pdf.text(50, 20, true, document.Title)
+	pdf.y -= 10
+	pdf.text(50, 11, false, fmt.Sprintf("%s: %s to %s", document.Period, document.From.Format("2006-01-02 15:04"), document.To.Format("2006-01-02 15:04 MST")))
+	columns := []float64{50, 290, 360, 420, 490} // Posição x de cada coluna
+
+	row := func(cells []string, bold bool) {
+		if pdf.y < 60 {
+			pdf.newPage()
+		}
+		if name := []rune(cells[0]); len(name) > 45 {
+			cells[0] = string(name[:42]) + "..." // Não invade a coluna do uptime
+		}
+		for i, cell := range cells {
+			pdf.textAt(columns[i], pdf.y, 10, bold, cell)
+		}
+		pdf.y -= 15
+	}
+	for _, group := range document.Groups {
+		pdf.y -= 14
+		if pdf.y < 100 {
+			pdf.newPage()
+		}
+		pdf.text(50, 14, true, group.Group)
+		pdf.y -= 4
+		row(slaColumns, true)
+		for _, service := range group.Services {
+			row(slaCells(service), false)
+		}
+		row(slaCells(group.Total), true)
+	}
+	pdf.y -= 14
+	pdf.text(50, 9, false, "Generated at "+document.Generated.Format("2006-01-02 15:04 MST"))
+	return pdf.bytes()
+}
+
+// Gerador mínimo de PDF: páginas de texto com as fontes padrão Helvetica e Helvetica-Bold (sem dependências)
+type pdfWriter struct {
+	pages []*bytes.Buffer // Conteúdo (content stream) de cada página
+	y     float64         // Posição vertical atual na página, a partir da base
+}
+
+const pdfPageWidth, pdfPageHeight = 595, 842 // A4 em pontos
+
+// Função para iniciar uma nova página
+func (p *pdfWriter) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = pdfPageHeight - 50
+}
+
+// Função para escrever uma linha de texto na posição atual e avançar para a próxima linha
+func (p *pdfWriter) text(x, size float64, bold bool, value string) {
+	if p.y < 50 {
+		p.newPage()
+	}
+	p.textAt(x, p.y, size, bold, value)
+	p.y -= size * 1.4
+}
+
+// Função para escrever um texto em uma posição da página atual
+func (p *pdfWriter) textAt(x, y, size float64, bold bool, value string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.pages[len(p.pages)-1], "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, x, y, pdfString(value))
+}
+
+// Função para converter o texto para WinAnsi (Latin-1) e escapar os caracteres especiais das strings do PDF
+func pdfString(value string) string {
+	var result strings.Builder
+	for _, c := range value {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			result.WriteByte('\\')
+			result.WriteRune(c)
+		case c < 32 || (c > 126 && c < 160) || c > 255:
+			result.WriteByte('?') // Fora do Latin-1: não há glifo na codificação padrão
+		case c > 126:
+			fmt.Fprintf(&result, "\\%03o", c)
+		default:
+			result.WriteRune(c)
+		}
+	}
+	return result.String()
+}
+
+// Função para montar o arquivo PDF com os objetos, a tabela xref e o trailer
+func (p *pdfWriter) bytes() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // Páginas, preenchido abaixo
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	kids := []string{}
+	for _, content := range p.pages {
+		page := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, page+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [sms]; apenas os serviços com a opção sms= na linha do serviço geram SMS
+type SMSConfig struct {
+	Enabled  bool
+	Provider string   // "twilio" ou "http" (gateway HTTP genérico)
+	To       []string // Números padrão, no formato E.164 (ex.: +5511999999999)
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string
+	TwilioAPIURL     string
+
+	HTTPURL     string            // URL do gateway; {to} e {message} são substituídos (com escape)
+	HTTPMethod  string            // GET (parâmetros na URL) ou POST (JSON {"to": ..., "message": ...})
+	HTTPHeaders map[string]string // Cabeçalhos adicionais (chaves header.<nome>)
+}
+
+// Função para ler a seção [sms] do config.ini
+func loadSMSConfig(cfg *ini.File) SMSConfig {
+	section := cfg.Section("sms")
+	config := SMSConfig{
+		Enabled:          section.Key("enabled").MustBool(false),
+		Provider:         section.Key("provider").In("twilio", []string{"twilio", "http"}),
+		To:               section.Key("to").Strings(","),
+		TwilioAccountSID: section.Key("twilio_account_sid").String(),
+		TwilioAuthToken:  section.Key("twilio_auth_token").String(),
+		TwilioFrom:       section.Key("twilio_from").String(),
+		TwilioAPIURL:     strings.TrimSuffix(section.Key("twilio_api_url").MustString("https://api.twilio.com"), "/"),
+		HTTPURL:          section.Key("http_url").String(),
+		HTTPMethod:       strings.ToUpper(section.Key("http_method").MustString("GET")),
+		HTTPHeaders:      map[string]string{},
+	}
+	for _, key := range section.Keys() {
+		if name, ok := strings.CutPrefix(key.Name(), "header."); ok {
+			config.HTTPHeaders[name] = key.String()
+		}
+	}
+	return config
+}
+
+// Notificação por SMS
+type smsNotifier struct {
+	config SMSConfig
+}
+
+func (n smsNotifier) Name() string { return "sms" }
+
+// Função para definir os números do serviço: sms=true usa os números padrão e
+// sms=<número>,<número> usa os números informados; sem a opção, o serviço não gera SMS
+func (n smsNotifier) recipients(service Service) []string {
+	value := service.Options["sms"]
+	switch value {
+	case "", "false", "no", "0":
+		return nil
+	case "true", "yes", "1":
+		return n.config.To
+	}
+	return strings.Split(value, ",")
+}
+
+// Função para enviar o SMS a cada número do serviço
+func (n smsNotifier) Notify(change stateChange) error {
+	to := n.recipients(change.Service)
+	if len(to) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("%s (%s). %s", change.title(), change.Service.ResponseTime, change.durationText())
+
+	var errs []string
+	for _, number := range to {
+		number = strings.TrimSpace(number)
+		var err error
+		if n.config.Provider == "http" {
+			err = n.sendHTTP(number, message)
+		} else {
+			err = n.sendTwilio(number, message)
+		}
+		if err != nil {
+			errs = append(errs, number+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Função para enviar o SMS pela API de mensagens da Twilio
+func (n smsNotifier) sendTwilio(to, message string) error {
+	if n.config.TwilioAccountSID == "" || n.config.TwilioAuthToken == "" || n.config.TwilioFrom == "" {
+		return fmt.Errorf("informe twilio_account_sid, twilio_auth_token e twilio_from na seção [sms]")
+	}
+	form := url.Values{"To": {to}, "From": {n.config.TwilioFrom}, "Body": {message}}
+	endpoint := n.config.TwilioAPIURL + "/2010-04-01/Accounts/" + url.PathEscape(n.config.TwilioAccountSID) + "/Messages.json"
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(n.config.TwilioAccountSID, n.config.TwilioAuthToken)
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("resposta %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Função para enviar o SMS por um gateway HTTP genérico
+func (n smsNotifier) sendHTTP(to, message string) error {
+	if n.config.HTTPURL == "" {
+		return fmt.Errorf("informe http_url na seção [sms]")
+	}
+	endpoint := strings.NewReplacer("{to}", url.QueryEscape(to), "{message}", url.QueryEscape(message)).Replace(n.config.HTTPURL)
+	if n.config.HTTPMethod == "POST" {
+		_, err := postJSON(endpoint, n.config.HTTPHeaders, map[string]string{"to": to, "message": message})
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range n.config.HTTPHeaders {
+		req.Header.Set(name, value)
+	}
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("resposta %s", resp.Status)
+	}
+	return nil
+}
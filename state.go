@@ -0,0 +1,73 @@
+package main
+
+import (
+	"time"
+)
+
+// Função para restaurar o último estado conhecido dos serviços, para que o dashboard não volte a "unknown"
+// e as durações de queda não sejam zeradas: ao recarregar o config.ini, o estado vem da configuração anterior
+// (serviços com a mesma descrição); ao iniciar, do histórico e das quedas recarregados do banco
+func restoreServiceStates(services []Service, previous []Service, config *Config) {
+	index := map[string]Service{}
+	for _, service := range previous {
+		index[service.Description] = service
+	}
+	now := time.Now()
+	for i := range services {
+		if old, ok := index[services[i].Description]; ok {
+			copyServiceState(&services[i], old)
+			continue
+		}
+		restoreServiceFromHistory(&services[i], now)
+		services[i].Uptime = computeUptime(services[i], now)
+		services[i].Latency = computeLatencyPercentiles(services[i], config.Latency, now)
+		services[i].Sparkline = recentLatencies(services[i], config.Sparkline)
+	}
+}
+
+// Função para copiar os campos de estado (não de configuração) de um serviço
+func copyServiceState(service *Service, old Service) {
+	service.Status = old.Status
+	service.ResponseTime = old.ResponseTime
+	service.Message = old.Message
+	service.LatencyMs = old.LatencyMs
+	service.DownSince = old.DownSince
+	service.LastDowntime = old.LastDowntime
+	service.RecoveredAt = old.RecoveredAt
+	service.ChangedAt = old.ChangedAt
+	service.Silenced = old.Silenced
+	service.Uptime = old.Uptime
+	service.Latency = old.Latency
+	service.Sparkline = old.Sparkline
+	service.Acknowledged = old.Acknowledged
+}
+
+// Função para reconstruir o estado de um serviço a partir do último resultado do histórico e das quedas
+func restoreServiceFromHistory(service *Service, now time.Time) {
+	historyMu.Lock()
+	if spans := statusHistory[service.Description]; len(spans) > 0 {
+		service.Status = spans[len(spans)-1].Status
+		start := spans[len(spans)-1].Start
+		service.ChangedAt = &start
+	}
+	if samples := sampleHistory[service.Description]; len(samples) > 0 && service.Status != "paused" {
+		service.LatencyMs = samples[len(samples)-1].LatencyMs
+		service.ResponseTime = formatResponseTime(service.LatencyMs)
+	}
+	historyMu.Unlock()
+
+	outagesMu.Lock()
+	defer outagesMu.Unlock()
+	if o, ok := openOutages[service.Description]; ok && service.Status == "red" {
+		start := o.Start
+		service.DownSince = &start
+	}
+	for i := len(outages) - 1; i >= 0; i-- {
+		if o := outages[i]; o.Service == service.Description && o.End != nil {
+			end := *o.End
+			service.RecoveredAt = &end
+			service.LastDowntime = formatDuration(end.Sub(o.Start))
+			break
+		}
+	}
+}
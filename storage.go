@@ -0,0 +1,427 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [storage]
+type StorageConfig struct {
+	Enabled   bool
+	Driver    string        // sqlite (padrão), postgres ou mysql
+	Path      string        // Arquivo do banco SQLite
+	DSN       string        // Conexão do PostgreSQL ou MySQL
+	Instance  string        // Nome desta instância nos resultados gravados (padrão o nome da máquina)
+	Retention time.Duration // Tempo em que os resultados são mantidos no banco
+	Restore   time.Duration // Período recarregado na memória ao iniciar (histórico, SLA, uptime e relatórios)
+
+	DownsampleAfter      time.Duration // Idade a partir da qual os resultados são agregados (0 desabilita)
+	DownsampleResolution time.Duration // Intervalo de agregação dos resultados antigos
+	Compact              bool          // Compacta o banco uma vez por dia, após a limpeza
+}
+
+// Função para ler a seção [storage] do config.ini
+func loadStorageConfig(cfg *ini.File) StorageConfig {
+	section := cfg.Section("storage")
+	hostname, _ := os.Hostname()
+	config := StorageConfig{
+		Enabled:  section.Key("enabled").MustBool(false),
+		Driver:   section.Key("driver").MustString("sqlite"),
+		Path:     section.Key("path").MustString("history.db"),
+		DSN:      section.Key("dsn").String(),
+		Instance: section.Key("instance").MustString(hostname),
+	}
+	var err error
+	// history_retention (ou o nome antigo retention)
+	retention := section.Key("history_retention").MustString(section.Key("retention").String())
+	if config.Retention, err = parseRange(retention, 90*24*time.Hour); err != nil {
+		log.Println(tr("history_retention inválido na seção [storage], usando 90d"))
+		config.Retention = 90 * 24 * time.Hour
+	}
+	if value := section.Key("downsample_after").String(); value != "" && value != "0" {
+		if config.DownsampleAfter, err = parseRange(value, 0); err != nil {
+			log.Println(tr("downsample_after inválido na seção [storage], agregação desabilitada"))
+		}
+	}
+	if config.DownsampleResolution, err = parseRange(section.Key("downsample_resolution").String(), 5*time.Minute); err != nil {
+		log.Println(tr("downsample_resolution inválido na seção [storage], usando 5m"))
+		config.DownsampleResolution = 5 * time.Minute
+	}
+	config.Compact = section.Key("compact").MustBool(true)
+	if config.Restore, err = parseRange(section.Key("restore").String(), 30*24*time.Hour); err != nil {
+		log.Println(tr("restore inválido na seção [storage], usando 30d"))
+		config.Restore = 30 * 24 * time.Hour
+	}
+	return config
+}
+
+// Resultado de uma verificação gravado no banco
+type checkRecord struct {
+	Service   string
+	Time      time.Time
+	Status    string
+	LatencyMs int64
+}
+
+// Banco onde os resultados das verificações são gravados
+type Storage interface {
+	Name() string
+	Insert(batch []checkRecord) error                                       // Grava um lote de resultados desta instância
+	Load(since time.Time, fn func(record checkRecord)) error                // Percorre os resultados desta instância a partir de since, em ordem
+	Prune(before time.Time) (int64, error)                                  // Apaga os resultados, quedas e anotações desta instância anteriores a before
+	Downsample(from, to time.Time, resolution time.Duration) (int64, error) // Agrega os resultados desta instância em [from, to) por intervalo e status
+	Compact() error                                                         // Recupera o espaço dos registros apagados
+	SaveOutage(o outage) error                                              // Grava a abertura ou o fechamento de uma queda
+	LoadOutages(since time.Time, fn func(o outage)) error                   // Percorre as quedas desta instância iniciadas a partir de since
+	SaveIncident(i incident) error                                          // Grava um incidente (criação ou alteração)
+	DeleteIncident(id string) error                                         // Apaga um incidente
+	LoadIncidents(fn func(i incident)) error                                // Percorre os incidentes desta instância
+	SaveAnnotation(a annotation) error                                      // Grava uma anotação
+	DeleteAnnotation(id string) error                                       // Apaga uma anotação
+	LoadAnnotations(since time.Time, fn func(a annotation)) error           // Percorre as anotações desta instância a partir de since, em ordem
+	SaveAlertState(service string, state alertState) error                  // Grava o estado de alerta de um serviço
+	DeleteAlertState(service string) error                                  // Apaga o estado de alerta de um serviço
+	LoadAlertStates(fn func(service string, state alertState)) error        // Percorre o estado de alerta dos serviços desta instância
+	SaveToken(hash [32]byte, token apiToken) error                          // Grava um token emitido pela API (apenas o hash do valor)
+	DeleteToken(name string) error                                          // Apaga um token emitido pela API
+	LoadTokens(fn func(hash [32]byte, token apiToken)) error                // Percorre os tokens emitidos pela API nesta instância
+	Clear() error                                                           // Apaga todos os dados desta instância, exceto os tokens (usado ao restaurar um backup)
+	Close() error
+}
+
+// Função para abrir o banco configurado em driver=
+func openStorage(config StorageConfig) (Storage, error) {
+	switch config.Driver {
+	case "sqlite":
+		if !sqliteAvailable {
+			return nil, fmt.Errorf("SQLite indisponível: o executável foi compilado sem CGO (CGO_ENABLED=0)")
+		}
+		return openSQLStorage(sqliteDialect, config.Path, config.Instance)
+	case "postgres":
+		return openSQLStorage(postgresDialect, config.DSN, config.Instance)
+	case "mysql":
+		return openSQLStorage(mysqlDialect, config.DSN, config.Instance)
+	}
+	return nil, fmt.Errorf("driver desconhecido %q (use sqlite, postgres ou mysql)", config.Driver)
+}
+
+const storageQueueSize = 10000 // Resultados aguardando gravação; além disso, são descartados
+
+var storage Storage                 // Banco de resultados (nil se a persistência estiver desabilitada)
+var storageQueue chan checkRecord   // Fila de gravação, consumida em lotes para não bloquear o monitoramento
+var storageDropped atomic.Int64     // Resultados descartados por fila cheia desde o último aviso
+var storageTasks chan func()        // Gravações eventuais (quedas), executadas em ordem fora do monitoramento
+var storageFlush chan chan struct{} // Pedidos de gravação imediata da fila, respondidos ao fechar o canal enviado
+
+// Função para abrir o banco, recarregar o histórico recente na memória e iniciar a gravação em segundo plano.
+// Alterações na seção [storage] exigem reiniciar o processo.
+func setupStorage(config StorageConfig) {
+	if !config.Enabled {
+		return
+	}
+	store, err := openStorage(config)
+	if err != nil {
+		log.Printf(tr("Erro ao abrir o banco (%s), persistência desabilitada: %v\n"), config.Driver, err)
+		return
+	}
+
+	restoreHistory(store, time.Now().Add(-config.Restore))
+	restoreOutages(store, time.Now().Add(-config.Restore))
+	restoreIncidents(store)
+	restoreAnnotations(store, time.Now().Add(-config.Restore))
+	restoreAlertStates(store)
+	restoreTokens(store)
+	storage = store
+	storageQueue = make(chan checkRecord, storageQueueSize)
+	storageTasks = make(chan func(), 1000)
+	storageFlush = make(chan chan struct{})
+	go writeChecks(store, storageQueue, storageFlush)
+	go func() {
+		for task := range storageTasks {
+			task()
+		}
+	}()
+	go maintainStorage(store, config)
+	log.Printf(tr("Resultados das verificações gravados em %s (retenção de %s)\n"), store.Name(), formatDuration(config.Retention))
+}
+
+// Função para enfileirar o resultado de uma verificação para gravação, sem bloquear o monitoramento
+func storeCheck(record checkRecord) {
+	if storageQueue == nil {
+		return
+	}
+	select {
+	case storageQueue <- record:
+	default:
+		storageDropped.Add(1)
+	}
+}
+
+// Função para enfileirar a gravação da abertura ou do fechamento de uma queda
+func saveOutage(o outage) {
+	queueStorageTask("Queda do serviço ["+o.Service+"]", func() error { return storage.SaveOutage(o) })
+}
+
+// Função para enfileirar a gravação de um incidente
+func saveIncident(i incident) {
+	queueStorageTask("Incidente "+i.ID, func() error { return storage.SaveIncident(i) })
+}
+
+// Função para enfileirar a exclusão de um incidente
+func deleteIncident(id string) {
+	queueStorageTask("Exclusão do incidente "+id, func() error { return storage.DeleteIncident(id) })
+}
+
+// Função para enfileirar a gravação de uma anotação
+func saveAnnotation(a annotation) {
+	queueStorageTask("Anotação "+a.ID, func() error { return storage.SaveAnnotation(a) })
+}
+
+// Função para enfileirar a exclusão de uma anotação
+func deleteAnnotation(id string) {
+	queueStorageTask("Exclusão da anotação "+id, func() error { return storage.DeleteAnnotation(id) })
+}
+
+// Função para enfileirar a gravação de um token emitido pela API
+func saveToken(hash [32]byte, token apiToken) {
+	queueStorageTask("Token ["+token.Name+"]", func() error { return storage.SaveToken(hash, token) })
+}
+
+// Função para enfileirar a exclusão de um token emitido pela API
+func deleteToken(name string) {
+	queueStorageTask("Exclusão do token ["+name+"]", func() error { return storage.DeleteToken(name) })
+}
+
+// Função para enfileirar a gravação do estado de alerta de um serviço. Apenas o líder grava: no par de alta
+// disponibilidade, o estado do reserva está desatualizado.
+func saveAlertState(service string, state alertState) {
+	if isLeader() {
+		queueStorageTask("Estado de alerta do serviço ["+service+"]", func() error { return storage.SaveAlertState(service, state) })
+	}
+}
+
+// Função para enfileirar a exclusão do estado de alerta de um serviço
+func deleteAlertState(service string) {
+	if isLeader() {
+		queueStorageTask("Estado de alerta do serviço ["+service+"]", func() error { return storage.DeleteAlertState(service) })
+	}
+}
+
+// Função para enfileirar uma gravação eventual, registrando no log se ela falhar
+func queueStorageTask(description string, task func() error) {
+	if storageTasks == nil {
+		return
+	}
+	select {
+	case storageTasks <- func() {
+		if err := task(); err != nil {
+			log.Printf(tr("%s não gravado no banco: %v\n"), description, err)
+		}
+	}:
+	default:
+		log.Printf(tr("%s não gravado no banco: fila de gravação cheia\n"), description)
+	}
+}
+
+// Função para gravar os resultados enfileirados em lotes, uma transação por segundo; um pedido em flush grava
+// imediatamente tudo o que está na fila
+func writeChecks(store Storage, queue chan checkRecord, flush chan chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	batch := []checkRecord{}
+	for {
+		var flushed chan struct{}
+		select {
+		case record := <-queue:
+			batch = append(batch, record)
+			if len(batch) < 500 {
+				continue
+			}
+		case <-ticker.C:
+		case flushed = <-flush:
+			for len(queue) > 0 {
+				batch = append(batch, <-queue)
+			}
+		}
+		if len(batch) > 0 {
+			if err := store.Insert(batch); err != nil {
+				log.Printf(tr("Erro ao gravar %d resultados no banco: %v\n"), len(batch), err)
+			}
+			batch = batch[:0]
+		}
+
+		if dropped := storageDropped.Swap(0); dropped > 0 {
+			log.Printf(tr("%d resultados descartados: fila de gravação cheia\n"), dropped)
+		}
+		if flushed != nil {
+			close(flushed)
+		}
+	}
+}
+
+// Função para gravar os resultados e as gravações eventuais ainda enfileirados e fechar o banco, ao encerrar o
+// processo (depois que as verificações pararam)
+func closeStorage() {
+	if storage == nil {
+		return
+	}
+	flushed := make(chan struct{})
+	storageFlush <- flushed
+	<-flushed
+
+	// As gravações eventuais são executadas em ordem: quando esta roda, as anteriores já terminaram
+	done := make(chan struct{})
+	storageTasks <- func() { close(done) }
+	<-done
+
+	if err := storage.Close(); err != nil {
+		log.Println(tr("Erro ao fechar o banco:"), err)
+	}
+}
+
+// Função para manter o banco, uma vez por hora: apaga os resultados mais antigos que a retenção,
+// agrega os resultados mais antigos que downsample_after e, uma vez por dia, compacta o banco
+func maintainStorage(store Storage, config StorageConfig) {
+	// Na primeira execução, agrega todo o período retido; depois, apenas o que envelheceu desde a anterior
+	var downsampled time.Time
+	for run := 0; ; run++ {
+		for !isLeader() {
+			time.Sleep(time.Minute) // No par de alta disponibilidade, só o líder mantém o banco
+		}
+		now := time.Now()
+		if n, err := store.Prune(now.Add(-config.Retention)); err != nil {
+			log.Println(tr("Erro ao apagar resultados antigos do banco:"), err)
+		} else if n > 0 {
+			log.Printf(tr("%d resultados antigos apagados do banco\n"), n)
+		}
+
+		if config.DownsampleAfter > 0 {
+			// O limite é alinhado ao intervalo de agregação, para que nenhum intervalo seja agregado pela metade
+			to := now.Add(-config.DownsampleAfter).Truncate(config.DownsampleResolution)
+			from := downsampled
+			if from.IsZero() {
+				from = now.Add(-config.Retention).Truncate(config.DownsampleResolution)
+			}
+			if n, err := downsampleRange(store, from, to, config.DownsampleResolution); err != nil {
+				log.Println(tr("Erro ao agregar resultados antigos do banco:"), err)
+			} else {
+				downsampled = to
+				if n > 0 {
+					log.Printf(tr("%d resultados antigos agregados em intervalos de %s\n"), n, formatDuration(config.DownsampleResolution))
+				}
+			}
+		}
+
+		if config.Compact && run%24 == 0 {
+			if err := store.Compact(); err != nil {
+				log.Println(tr("Erro ao compactar o banco:"), err)
+			}
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
+// Função para agregar os resultados de [from, to) um dia por vez, limitando a memória usada. Cada etapa termina
+// em um múltiplo da resolução, para que nenhum intervalo seja dividido entre duas etapas.
+func downsampleRange(store Storage, from, to time.Time, resolution time.Duration) (int64, error) {
+	var total int64
+	for start := from; start.Before(to); {
+		end := start.Add(max(24*time.Hour, resolution)).Truncate(resolution)
+		if end.After(to) {
+			end = to
+		}
+		n, err := store.Downsample(start, end, resolution)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		start = end
+	}
+	return total, nil
+}
+
+// Função para recarregar na memória os resultados gravados a partir de since,
+// reconstruindo o histórico usado pelos endpoints de histórico, SLA e relatórios
+func restoreHistory(store Storage, since time.Time) {
+	count := 0
+	err := store.Load(since, func(record checkRecord) {
+		appendHistory(record.Service, record.Status, record.LatencyMs, record.Time)
+		count++
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar o histórico do banco:"), err)
+	}
+	log.Printf(tr("%d resultados recarregados do banco\n"), count)
+}
+
+// Função para recarregar na memória as quedas iniciadas a partir de since; quedas que estavam em
+// andamento ao encerrar o processo continuam abertas até a próxima verificação do serviço
+func restoreOutages(store Storage, since time.Time) {
+	outagesMu.Lock()
+	defer outagesMu.Unlock()
+	err := store.LoadOutages(since, func(o outage) {
+		appendOutage(&o)
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar as quedas do banco:"), err)
+	}
+}
+
+// Função para recarregar na memória os incidentes gravados
+func restoreIncidents(store Storage) {
+	incidentsMu.Lock()
+	defer incidentsMu.Unlock()
+	err := store.LoadIncidents(func(i incident) {
+		incidents[i.ID] = &i
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar os incidentes do banco:"), err)
+	}
+}
+
+// Função para recarregar na memória o estado de alerta dos serviços (quedas notificadas, escalonamento e
+// reconhecimentos), substituindo o estado atual, para que uma queda em andamento ao reiniciar o processo ou ao
+// assumir a liderança não seja notificada de novo e tenha a recuperação notificada
+func restoreAlertStates(store Storage) {
+	alertMu.Lock()
+	defer alertMu.Unlock()
+	alertStates = map[string]*alertState{}
+	err := store.LoadAlertStates(func(service string, state alertState) {
+		alertStates[service] = &state
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar o estado de alerta do banco:"), err)
+	}
+}
+
+// Função para recarregar na memória os tokens emitidos pela API, substituindo os atuais
+func restoreTokens(store Storage) {
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	issuedTokens = map[[32]byte]apiToken{}
+	tokenHashes = map[string][32]byte{}
+	err := store.LoadTokens(func(hash [32]byte, token apiToken) {
+		issuedTokens[hash] = token
+		tokenHashes[token.Name] = hash
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar os tokens de API do banco:"), err)
+	}
+}
+
+// Função para recarregar na memória as anotações a partir de since
+func restoreAnnotations(store Storage, since time.Time) {
+	annotationsMu.Lock()
+	defer annotationsMu.Unlock()
+	err := store.LoadAnnotations(since, func(a annotation) {
+		insertAnnotation(a)
+	})
+	if err != nil {
+		log.Println(tr("Erro ao recarregar as anotações do banco:"), err)
+	}
+}
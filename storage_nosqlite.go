@@ -0,0 +1,7 @@
+//go:build !cgo
+
+package main
+
+// Compilado sem CGO (ex.: CGO_ENABLED=0 no buildlinux.ps1): a persistência SQLite fica desabilitada
+const sqliteDriver = ""
+const sqliteAvailable = false
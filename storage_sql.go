@@ -0,0 +1,665 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql" // Driver MySQL/MariaDB
+	_ "github.com/lib/pq"              // Driver PostgreSQL
+)
+
+// Diferenças de SQL entre os bancos suportados
+type sqlDialect struct {
+	Name     string
+	Driver   string   // Nome do driver registrado em database/sql
+	Schema   []string // Criação das tabelas
+	Indexes  []string // Índices, criados após a migração da tabela
+	Compact  string   // Comando que recupera o espaço dos registros apagados
+	Now      string   // Horário atual do banco em milissegundos (liderança independente do relógio dos nós)
+	Numbered bool     // Parâmetros no formato $1, $2... (PostgreSQL) em vez de ?
+}
+
+var sqliteDialect = sqlDialect{
+	Name:   "sqlite",
+	Driver: sqliteDriver,
+	Schema: []string{`CREATE TABLE IF NOT EXISTS checks (
+		id INTEGER PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL DEFAULT '',
+		service VARCHAR(255) NOT NULL,
+		checked_at INTEGER NOT NULL,
+		status VARCHAR(16) NOT NULL,
+		latency_ms INTEGER NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS outages (
+		id INTEGER PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		service_group VARCHAR(255) NOT NULL,
+		started_at INTEGER NOT NULL,
+		ended_at INTEGER
+	)`, `CREATE TABLE IF NOT EXISTS incidents (
+		id VARCHAR(32) NOT NULL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		created_at INTEGER NOT NULL,
+		data TEXT NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS annotations (
+		id VARCHAR(32) NOT NULL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		at INTEGER NOT NULL,
+		data TEXT NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS alert_states (
+		instance VARCHAR(255) NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (instance, service)
+	)`, `CREATE TABLE IF NOT EXISTS api_tokens (
+		instance VARCHAR(255) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		hash VARCHAR(64) NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (instance, name)
+	)`, `CREATE TABLE IF NOT EXISTS leader_lease (
+		instance VARCHAR(255) NOT NULL PRIMARY KEY,
+		node VARCHAR(255) NOT NULL,
+		expires_at INTEGER NOT NULL
+	)`},
+	Indexes: []string{
+		"CREATE INDEX IF NOT EXISTS checks_service_time ON checks (service, checked_at)",
+		"CREATE INDEX IF NOT EXISTS checks_instance_time ON checks (instance, checked_at)",
+		"CREATE INDEX IF NOT EXISTS checks_time ON checks (checked_at)",
+		"CREATE INDEX IF NOT EXISTS outages_instance_start ON outages (instance, started_at)",
+		"CREATE INDEX IF NOT EXISTS annotations_instance_time ON annotations (instance, at)",
+	},
+	Compact: "VACUUM",
+	Now:     "CAST((julianday('now') - 2440587.5) * 86400000 AS INTEGER)",
+}
+
+var postgresDialect = sqlDialect{
+	Name:   "postgres",
+	Driver: "postgres",
+	Schema: []string{`CREATE TABLE IF NOT EXISTS checks (
+		id BIGSERIAL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL DEFAULT '',
+		service VARCHAR(255) NOT NULL,
+		checked_at BIGINT NOT NULL,
+		status VARCHAR(16) NOT NULL,
+		latency_ms BIGINT NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS outages (
+		id BIGSERIAL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		service_group VARCHAR(255) NOT NULL,
+		started_at BIGINT NOT NULL,
+		ended_at BIGINT
+	)`, `CREATE TABLE IF NOT EXISTS incidents (
+		id VARCHAR(32) NOT NULL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		created_at BIGINT NOT NULL,
+		data TEXT NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS annotations (
+		id VARCHAR(32) NOT NULL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		at BIGINT NOT NULL,
+		data TEXT NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS alert_states (
+		instance VARCHAR(255) NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (instance, service)
+	)`, `CREATE TABLE IF NOT EXISTS api_tokens (
+		instance VARCHAR(255) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		hash VARCHAR(64) NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (instance, name)
+	)`, `CREATE TABLE IF NOT EXISTS leader_lease (
+		instance VARCHAR(255) NOT NULL PRIMARY KEY,
+		node VARCHAR(255) NOT NULL,
+		expires_at BIGINT NOT NULL
+	)`},
+	Indexes: []string{
+		"CREATE INDEX IF NOT EXISTS checks_service_time ON checks (service, checked_at)",
+		"CREATE INDEX IF NOT EXISTS checks_instance_time ON checks (instance, checked_at)",
+		"CREATE INDEX IF NOT EXISTS checks_time ON checks (checked_at)",
+		"CREATE INDEX IF NOT EXISTS outages_instance_start ON outages (instance, started_at)",
+		"CREATE INDEX IF NOT EXISTS annotations_instance_time ON annotations (instance, at)",
+	},
+	Compact:  "VACUUM ANALYZE checks",
+	Now:      "CAST(EXTRACT(EPOCH FROM NOW()) * 1000 AS BIGINT)",
+	Numbered: true,
+}
+
+// O MySQL não aceita CREATE INDEX IF NOT EXISTS: os índices são criados junto com as tabelas
+var mysqlDialect = sqlDialect{
+	Name:   "mysql",
+	Driver: "mysql",
+	Schema: []string{`CREATE TABLE IF NOT EXISTS checks (
+		id BIGINT AUTO_INCREMENT PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL DEFAULT '',
+		service VARCHAR(255) NOT NULL,
+		checked_at BIGINT NOT NULL,
+		status VARCHAR(16) NOT NULL,
+		latency_ms BIGINT NOT NULL,
+		INDEX checks_service_time (service, checked_at),
+		INDEX checks_instance_time (instance, checked_at),
+		INDEX checks_time (checked_at)
+	)`, `CREATE TABLE IF NOT EXISTS outages (
+		id BIGINT AUTO_INCREMENT PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		service_group VARCHAR(255) NOT NULL,
+		started_at BIGINT NOT NULL,
+		ended_at BIGINT,
+		INDEX outages_instance_start (instance, started_at)
+	)`, `CREATE TABLE IF NOT EXISTS incidents (
+		id VARCHAR(32) NOT NULL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		created_at BIGINT NOT NULL,
+		data MEDIUMTEXT NOT NULL
+	)`, `CREATE TABLE IF NOT EXISTS annotations (
+		id VARCHAR(32) NOT NULL PRIMARY KEY,
+		instance VARCHAR(255) NOT NULL,
+		at BIGINT NOT NULL,
+		data TEXT NOT NULL,
+		INDEX annotations_instance_time (instance, at)
+	)`, `CREATE TABLE IF NOT EXISTS alert_states (
+		instance VARCHAR(255) NOT NULL,
+		service VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (instance, service)
+	)`, `CREATE TABLE IF NOT EXISTS api_tokens (
+		instance VARCHAR(255) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		hash VARCHAR(64) NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (instance, name)
+	)`, `CREATE TABLE IF NOT EXISTS leader_lease (
+		instance VARCHAR(255) NOT NULL PRIMARY KEY,
+		node VARCHAR(255) NOT NULL,
+		expires_at BIGINT NOT NULL
+	)`},
+	Compact: "OPTIMIZE TABLE checks",
+	Now:     "CAST(UNIX_TIMESTAMP(NOW(3)) * 1000 AS SIGNED)",
+}
+
+// Função para adaptar os parâmetros ? da consulta ao formato do banco
+func (d sqlDialect) rebind(query string) string {
+	if !d.Numbered {
+		return query
+	}
+	var result strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&result, "$%d", n)
+			continue
+		}
+		result.WriteRune(c)
+	}
+	return result.String()
+}
+
+// Armazenamento dos resultados em um banco SQL (SQLite, PostgreSQL ou MySQL)
+type sqlStorage struct {
+	db       *sql.DB
+	dialect  sqlDialect
+	label    string
+	instance string
+}
+
+// Função para conectar ao banco, criar as tabelas e migrar bancos de versões anteriores
+func openSQLStorage(dialect sqlDialect, dsn, instance string) (*sqlStorage, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("informe path (sqlite) ou dsn (postgres e mysql) na seção [storage]")
+	}
+	db, err := sql.Open(dialect.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	label := dialect.Name
+	if dialect.Name == "sqlite" {
+		db.SetMaxOpenConns(1) // O SQLite aceita apenas uma escrita por vez
+		label += " (" + dsn + ")"
+	}
+	if err := migrateChecks(db, dialect, instance); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqlStorage{db: db, dialect: dialect, label: label, instance: instance}, nil
+}
+
+// Função para criar as tabelas e incluir a coluna instance em bancos criados antes dela
+// (os resultados já gravados passam a pertencer a esta instância)
+func migrateChecks(db *sql.DB, dialect sqlDialect, instance string) error {
+	for _, statement := range dialect.Schema {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec("SELECT instance FROM checks WHERE 1 = 0"); err != nil {
+		if _, err := db.Exec("ALTER TABLE checks ADD COLUMN instance VARCHAR(255) NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec(dialect.rebind("UPDATE checks SET instance = ? WHERE instance = ''"), instance); err != nil {
+		return err
+	}
+	for _, statement := range dialect.Indexes {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStorage) Name() string { return s.label }
+
+func (s *sqlStorage) Close() error { return s.db.Close() }
+
+// Função para inserir um lote de resultados em uma transação
+func (s *sqlStorage) Insert(batch []checkRecord) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(s.dialect.rebind("INSERT INTO checks (instance, service, checked_at, status, latency_ms) VALUES (?, ?, ?, ?, ?)"))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, record := range batch {
+		if _, err := stmt.Exec(s.instance, record.Service, record.Time.UnixMilli(), record.Status, record.LatencyMs); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Função para percorrer os resultados desta instância gravados a partir de since
+func (s *sqlStorage) Load(since time.Time, fn func(record checkRecord)) error {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT service, checked_at, status, latency_ms FROM checks WHERE instance = ? AND checked_at >= ? ORDER BY checked_at, id"),
+		s.instance, since.UnixMilli())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var record checkRecord
+		var at int64
+		if err := rows.Scan(&record.Service, &at, &record.Status, &record.LatencyMs); err != nil {
+			return err
+		}
+		record.Time = time.UnixMilli(at)
+		fn(record)
+	}
+	return rows.Err()
+}
+
+// Função para apagar os resultados, as quedas encerradas e as anotações desta instância anteriores a before; as
+// demais instâncias que compartilham o banco seguem a própria retenção
+func (s *sqlStorage) Prune(before time.Time) (int64, error) {
+	var total int64
+	for _, query := range []string{
+		"DELETE FROM checks WHERE instance = ? AND checked_at < ?",
+		"DELETE FROM outages WHERE instance = ? AND ended_at < ?",
+		"DELETE FROM annotations WHERE instance = ? AND at < ?",
+	} {
+		result, err := s.db.Exec(s.dialect.rebind(query), s.instance, before.UnixMilli())
+		if err != nil {
+			return total, err
+		}
+		n, _ := result.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
+
+// Função para apagar todos os dados desta instância, em uma transação (os tokens de API, que não fazem parte do
+// backup, são mantidos)
+func (s *sqlStorage) Clear() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range []string{"checks", "outages", "incidents", "annotations", "alert_states"} {
+		if _, err := tx.Exec(s.dialect.rebind("DELETE FROM "+table+" WHERE instance = ?"), s.instance); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Serviço, intervalo (início em milissegundos) e status agregados em um único registro pelo downsampling
+type downsampleKey struct {
+	Service string
+	Start   int64
+	Status  string
+}
+
+type downsampleBucket struct {
+	downsampleKey
+	First   int64 // Primeiro resultado do intervalo, usado como horário do registro agregado
+	Count   int64
+	Latency int64 // Soma dos tempos de resposta
+}
+
+// Função para substituir os resultados desta instância em [from, to) por um registro por serviço,
+// intervalo e status, com a média dos tempos de resposta. Os intervalos são alinhados com time.Truncate, como
+// from e to; intervalos cortados pelas bordas da janela só têm apagados os resultados de dentro dela.
+// Retorna a quantidade de registros removidos.
+func (s *sqlStorage) Downsample(from, to time.Time, resolution time.Duration) (int64, error) {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT service, checked_at, status, latency_ms FROM checks WHERE instance = ? AND checked_at >= ? AND checked_at < ? ORDER BY service, checked_at"),
+		s.instance, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	step := resolution.Milliseconds()
+	buckets := map[downsampleKey]*downsampleBucket{}
+	order := []*downsampleBucket{}
+	for rows.Next() {
+		var service, status string
+		var at, latency int64
+		if err := rows.Scan(&service, &at, &status, &latency); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		// Mesmo alinhamento (time.Truncate) da janela calculada em maintainStorage
+		key := downsampleKey{Service: service, Start: time.UnixMilli(at).Truncate(resolution).UnixMilli(), Status: status}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &downsampleBucket{downsampleKey: key, First: at}
+			buckets[key] = bucket
+			order = append(order, bucket)
+		}
+		bucket.Count++
+		bucket.Latency += latency
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	var removed int64
+	for _, bucket := range order {
+		if bucket.Count < 2 {
+			continue // Já agregado (ou um único resultado no intervalo)
+		}
+		if _, err := tx.Exec(s.dialect.rebind("DELETE FROM checks WHERE instance = ? AND service = ? AND status = ? AND checked_at >= ? AND checked_at < ?"),
+			s.instance, bucket.Service, bucket.Status, max(bucket.Start, from.UnixMilli()), min(bucket.Start+step, to.UnixMilli())); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if _, err := tx.Exec(s.dialect.rebind("INSERT INTO checks (instance, service, checked_at, status, latency_ms) VALUES (?, ?, ?, ?, ?)"),
+			s.instance, bucket.Service, bucket.First, bucket.Status, bucket.Latency/bucket.Count); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		removed += bucket.Count - 1
+	}
+	return removed, tx.Commit()
+}
+
+// Função para recuperar o espaço dos registros apagados pela limpeza e pelo downsampling
+func (s *sqlStorage) Compact() error {
+	_, err := s.db.Exec(s.dialect.Compact)
+	return err
+}
+
+// Função para gravar uma queda: atualiza o fim do registro existente ou, se não houver, cria o registro
+func (s *sqlStorage) SaveOutage(o outage) error {
+	var ended interface{}
+	if o.End != nil {
+		ended = o.End.UnixMilli()
+	}
+	result, err := s.db.Exec(s.dialect.rebind("UPDATE outages SET ended_at = ? WHERE instance = ? AND service = ? AND started_at = ?"),
+		ended, s.instance, o.Service, o.Start.UnixMilli())
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+	_, err = s.db.Exec(s.dialect.rebind("INSERT INTO outages (instance, service, service_group, started_at, ended_at) VALUES (?, ?, ?, ?, ?)"),
+		s.instance, o.Service, o.Group, o.Start.UnixMilli(), ended)
+	return err
+}
+
+// Função para percorrer as quedas desta instância iniciadas a partir de since
+func (s *sqlStorage) LoadOutages(since time.Time, fn func(o outage)) error {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT service, service_group, started_at, ended_at FROM outages WHERE instance = ? AND started_at >= ? ORDER BY started_at, id"),
+		s.instance, since.UnixMilli())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var o outage
+		var started int64
+		var ended sql.NullInt64
+		if err := rows.Scan(&o.Service, &o.Group, &started, &ended); err != nil {
+			return err
+		}
+		o.Start = time.UnixMilli(started)
+		if ended.Valid {
+			end := time.UnixMilli(ended.Int64)
+			o.End = &end
+			o.DurationSeconds = end.Sub(o.Start).Seconds()
+		}
+		fn(o)
+	}
+	return rows.Err()
+}
+
+// Função para gravar um incidente (em JSON): atualiza o registro existente ou, se não houver, cria o registro
+func (s *sqlStorage) SaveIncident(i incident) error {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(s.dialect.rebind("UPDATE incidents SET data = ? WHERE id = ?"), string(data), i.ID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+	_, err = s.db.Exec(s.dialect.rebind("INSERT INTO incidents (id, instance, created_at, data) VALUES (?, ?, ?, ?)"),
+		i.ID, s.instance, i.CreatedAt.UnixMilli(), string(data))
+	return err
+}
+
+// Função para apagar um incidente desta instância
+func (s *sqlStorage) DeleteIncident(id string) error {
+	_, err := s.db.Exec(s.dialect.rebind("DELETE FROM incidents WHERE instance = ? AND id = ?"), s.instance, id)
+	return err
+}
+
+// Função para percorrer os incidentes desta instância, em ordem de criação
+func (s *sqlStorage) LoadIncidents(fn func(i incident)) error {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT data FROM incidents WHERE instance = ? ORDER BY created_at"), s.instance)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var i incident
+		if err := json.Unmarshal([]byte(data), &i); err != nil {
+			return err
+		}
+		fn(i)
+	}
+	return rows.Err()
+}
+
+// Função para gravar uma anotação (em JSON)
+func (s *sqlStorage) SaveAnnotation(a annotation) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.dialect.rebind("INSERT INTO annotations (id, instance, at, data) VALUES (?, ?, ?, ?)"),
+		a.ID, s.instance, a.Time.UnixMilli(), string(data))
+	return err
+}
+
+// Função para apagar uma anotação desta instância
+func (s *sqlStorage) DeleteAnnotation(id string) error {
+	_, err := s.db.Exec(s.dialect.rebind("DELETE FROM annotations WHERE instance = ? AND id = ?"), s.instance, id)
+	return err
+}
+
+// Função para percorrer as anotações desta instância a partir de since, em ordem de horário
+func (s *sqlStorage) LoadAnnotations(since time.Time, fn func(a annotation)) error {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT data FROM annotations WHERE instance = ? AND at >= ? ORDER BY at"), s.instance, since.UnixMilli())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var a annotation
+		if err := json.Unmarshal([]byte(data), &a); err != nil {
+			return err
+		}
+		fn(a)
+	}
+	return rows.Err()
+}
+
+// Função para gravar o estado de alerta de um serviço (em JSON): atualiza o registro existente ou, se não
+// houver, cria o registro
+func (s *sqlStorage) SaveAlertState(service string, state alertState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(s.dialect.rebind("UPDATE alert_states SET data = ? WHERE instance = ? AND service = ?"), string(data), s.instance, service)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+	_, err = s.db.Exec(s.dialect.rebind("INSERT INTO alert_states (instance, service, data) VALUES (?, ?, ?)"), s.instance, service, string(data))
+	return err
+}
+
+// Função para apagar o estado de alerta de um serviço
+func (s *sqlStorage) DeleteAlertState(service string) error {
+	_, err := s.db.Exec(s.dialect.rebind("DELETE FROM alert_states WHERE instance = ? AND service = ?"), s.instance, service)
+	return err
+}
+
+// Função para percorrer o estado de alerta dos serviços desta instância
+func (s *sqlStorage) LoadAlertStates(fn func(service string, state alertState)) error {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT service, data FROM alert_states WHERE instance = ?"), s.instance)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var service, data string
+		if err := rows.Scan(&service, &data); err != nil {
+			return err
+		}
+		var state alertState
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return err
+		}
+		fn(service, state)
+	}
+	return rows.Err()
+}
+
+// Função para gravar um token emitido pela API: apenas o hash SHA-256 do valor, com o nome, o escopo e os grupos
+// (em JSON)
+func (s *sqlStorage) SaveToken(hash [32]byte, token apiToken) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(s.dialect.rebind("INSERT INTO api_tokens (instance, name, hash, data) VALUES (?, ?, ?, ?)"),
+		s.instance, token.Name, hex.EncodeToString(hash[:]), string(data))
+	return err
+}
+
+// Função para apagar um token emitido pela API
+func (s *sqlStorage) DeleteToken(name string) error {
+	_, err := s.db.Exec(s.dialect.rebind("DELETE FROM api_tokens WHERE instance = ? AND name = ?"), s.instance, name)
+	return err
+}
+
+// Função para percorrer os tokens emitidos pela API nesta instância
+func (s *sqlStorage) LoadTokens(fn func(hash [32]byte, token apiToken)) error {
+	rows, err := s.db.Query(s.dialect.rebind("SELECT hash, data FROM api_tokens WHERE instance = ?"), s.instance)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var encoded, data string
+		if err := rows.Scan(&encoded, &data); err != nil {
+			return err
+		}
+		var token apiToken
+		if err := json.Unmarshal([]byte(data), &token); err != nil {
+			return err
+		}
+		var hash [32]byte
+		decoded, err := hex.DecodeString(encoded)
+		if err != nil || len(decoded) != len(hash) {
+			return fmt.Errorf("hash inválido no token [%s]", token.Name)
+		}
+		copy(hash[:], decoded)
+		fn(hash, token)
+	}
+	return rows.Err()
+}
+
+// Função para adquirir ou renovar por ttl a liderança desta instância no par de alta disponibilidade: o
+// registro passa ao nó informado se já for dele ou se estiver expirado (o UPDATE condicional é atômico no
+// banco). Retorna o nó que detém a liderança.
+func (s *sqlStorage) AcquireLease(node string, ttl time.Duration) (string, error) {
+	// A validade é calculada pelo relógio do banco, comum aos dois nós, e não pelo relógio de cada máquina
+	now := s.dialect.Now
+	result, err := s.db.Exec(s.dialect.rebind("UPDATE leader_lease SET node = ?, expires_at = "+now+" + ? WHERE instance = ? AND (node = ? OR expires_at < "+now+")"),
+		node, ttl.Milliseconds(), s.instance, node)
+	if err != nil {
+		return "", err
+	}
+	var insertErr error
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		// Primeiro nó a iniciar; se o outro nó criou o registro ao mesmo tempo, a chave primária recusa este
+		_, insertErr = s.db.Exec(s.dialect.rebind("INSERT INTO leader_lease (instance, node, expires_at) VALUES (?, ?, "+now+" + ?)"),
+			s.instance, node, ttl.Milliseconds())
+	}
+	var leader string
+	err = s.db.QueryRow(s.dialect.rebind("SELECT node FROM leader_lease WHERE instance = ?"), s.instance).Scan(&leader)
+	if err != nil && insertErr != nil {
+		return "", insertErr // Sem registro de liderança: o erro relevante é o da criação
+	}
+	return leader, err
+}
+
+func (s *sqlStorage) ReleaseLease(node string) error {
+	_, err := s.db.Exec(s.dialect.rebind("UPDATE leader_lease SET expires_at = 0 WHERE instance = ? AND node = ?"), s.instance, node)
+	return err
+}
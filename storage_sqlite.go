@@ -0,0 +1,8 @@
+//go:build cgo
+
+package main
+
+import _ "github.com/mattn/go-sqlite3" // Driver SQLite (exige CGO)
+
+const sqliteDriver = "sqlite3"
+const sqliteAvailable = true
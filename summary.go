@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Resumo compacto de um serviço, para clientes móveis e complicações de smartwatch
+type serviceSummary struct {
+	ID     int        `json:"id"`
+	Name   string     `json:"name"`
+	Status string     `json:"status"`          // green, red, paused ou unknown
+	Since  *time.Time `json:"since,omitempty"` // Última mudança de status
+}
+
+// Resposta de /api/summary
+type summaryResponse struct {
+	Status    string           `json:"status"` // Cor do estado agregado (green, yellow ou red, como no favicon)
+	Up        int              `json:"up"`
+	Total     int              `json:"total"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Services  []serviceSummary `json:"services"`
+}
+
+// Função para obter os campos do JSON de um serviço, indexados pelo nome em minúsculas
+func serviceFieldNames() map[string]string {
+	names := map[string]string{}
+	kind := reflect.TypeOf(Service{})
+	for i := 0; i < kind.NumField(); i++ {
+		name, _, _ := strings.Cut(kind.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[strings.ToLower(name)] = name
+		}
+	}
+	return names
+}
+
+// Função para ler o parâmetro fields (lista separada por vírgulas, sem diferenciar maiúsculas) com os campos
+// do JSON dos serviços a serem enviados (nil = todos)
+func parseFields(r *http.Request) ([]string, error) {
+	requested := splitList(r.URL.Query().Get("fields"))
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	names := serviceFieldNames()
+	fields := []string{}
+	for _, field := range requested {
+		name, ok := names[strings.ToLower(field)]
+		if !ok {
+			return nil, fmt.Errorf("campo desconhecido em fields: %s", field)
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// Função para reduzir os serviços aos campos selecionados (campos vazios continuam omitidos)
+func selectFields(services []Service, fields []string) ([]map[string]json.RawMessage, error) {
+	result := make([]map[string]json.RawMessage, 0, len(services))
+	for _, service := range services {
+		data, err := json.Marshal(service)
+		if err != nil {
+			return nil, err
+		}
+		all := map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		selected := map[string]json.RawMessage{}
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				selected[field] = value
+			}
+		}
+		result = append(result, selected)
+	}
+	return result, nil
+}
+
+// Handler para o resumo compacto dos serviços: apenas nome, status e horário da última mudança, com o estado
+// agregado. Aceita os mesmos filtros do WebSocket (view, group, tag e service).
+func summaryHandler(w http.ResponseWriter, r *http.Request) {
+	filter := queryFilter(r)
+	services := []Service{}
+	for _, service := range snapshotServices() {
+		if filter.matches(service) {
+			services = append(services, service)
+		}
+	}
+	sortServices(services, getConfig().UI.Sort)
+
+	response := summaryResponse{Status: aggregateOf(services).Color, Total: len(services), UpdatedAt: time.Now(), Services: []serviceSummary{}}
+	for _, service := range services {
+		if service.Status == "green" {
+			response.Up++
+		}
+		response.Services = append(response.Services, serviceSummary{ID: service.ID, Name: service.Description, Status: service.Status, Since: service.ChangedAt})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [teams]
+type TeamsConfig struct {
+	Enabled     bool
+	WebhookURLs []string            // Webhooks padrão (Incoming Webhook ou fluxo do Workflows)
+	Groups      map[string][]string // Webhooks por grupo de serviços, da seção [teams.groups]
+}
+
+// Função para ler as seções [teams] e [teams.groups] do config.ini
+func loadTeamsConfig(cfg *ini.File) TeamsConfig {
+	section := cfg.Section("teams")
+	config := TeamsConfig{
+		Enabled:     section.Key("enabled").MustBool(false),
+		WebhookURLs: section.Key("webhook_urls").Strings(","),
+		Groups:      map[string][]string{},
+	}
+	for _, key := range cfg.Section("teams.groups").Keys() {
+		config.Groups[key.Name()] = key.Strings(",")
+	}
+	return config
+}
+
+// Notificação no Microsoft Teams
+type teamsNotifier struct {
+	config TeamsConfig
+}
+
+func (n teamsNotifier) Name() string { return "teams" }
+
+// Função para montar o Adaptive Card da mudança de status
+func teamsCard(change stateChange) map[string]interface{} {
+	color := "Good"
+	if change.To == "red" {
+		color = "Attention"
+	}
+	facts := []map[string]string{
+		{"title": tr("Response time"), "value": change.Service.ResponseTime},
+		{"title": tr("Address"), "value": change.address()},
+	}
+	if change.Service.Group != "" {
+		facts = append(facts, map[string]string{"title": tr("Group"), "value": change.Service.Group})
+	}
+	if change.Service.Message != "" {
+		facts = append(facts, map[string]string{"title": tr("Message"), "value": change.Service.Message})
+	}
+	facts = append(facts, map[string]string{"title": tr("Time"), "value": change.Time.Format("2006-01-02 15:04:05 MST")})
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"msteams": map[string]string{"width": "Full"},
+		"body": []map[string]interface{}{
+			{"type": "TextBlock", "text": change.title(), "weight": "Bolder", "size": "Medium", "color": color, "wrap": true},
+			{"type": "TextBlock", "text": change.durationText(), "isSubtle": true, "spacing": "None"},
+			{"type": "FactSet", "facts": facts},
+		},
+	}
+	if url := dashboardURL(); url != "" {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": tr("Open dashboard"), "url": url}}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
+
+// Função para publicar o Adaptive Card nos webhooks do grupo do serviço (ou nos webhooks padrão)
+func (n teamsNotifier) Notify(change stateChange) error {
+	webhookURLs := n.config.WebhookURLs
+	if urls, ok := n.config.Groups[change.Service.Group]; ok && change.Service.Group != "" {
+		webhookURLs = urls
+	}
+	if len(webhookURLs) == 0 {
+		return fmt.Errorf("informe webhook_urls na seção [teams]")
+	}
+
+	payload := teamsCard(change)
+	var errs []string
+	for _, url := range webhookURLs {
+		if _, err := postJSON(url, nil, payload); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
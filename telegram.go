@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [telegram]
+type TelegramConfig struct {
+	Enabled  bool
+	BotToken string
+	ChatIDs  []string            // Chats padrão (usuários, grupos ou canais)
+	APIURL   string              // Permite usar um servidor próprio da Bot API
+	Groups   map[string][]string // Chats por grupo de serviços, da seção [telegram.groups]
+}
+
+// Função para ler as seções [telegram] e [telegram.groups] do config.ini
+func loadTelegramConfig(cfg *ini.File) TelegramConfig {
+	section := cfg.Section("telegram")
+	config := TelegramConfig{
+		Enabled:  section.Key("enabled").MustBool(false),
+		BotToken: section.Key("bot_token").String(),
+		ChatIDs:  section.Key("chat_ids").Strings(","),
+		APIURL:   strings.TrimSuffix(section.Key("api_url").MustString("https://api.telegram.org"), "/"),
+		Groups:   map[string][]string{},
+	}
+	for _, key := range cfg.Section("telegram.groups").Keys() {
+		config.Groups[key.Name()] = key.Strings(",")
+	}
+	return config
+}
+
+// Notificação pelo bot do Telegram
+type telegramNotifier struct {
+	config TelegramConfig
+}
+
+func (n telegramNotifier) Name() string { return "telegram" }
+
+// Função para enviar a mudança de status aos chats do grupo do serviço (ou aos chats padrão)
+func (n telegramNotifier) Notify(change stateChange) error {
+	chatIDs := n.config.ChatIDs
+	if ids, ok := n.config.Groups[change.Service.Group]; ok && change.Service.Group != "" {
+		chatIDs = ids
+	}
+	if n.config.BotToken == "" || len(chatIDs) == 0 {
+		return fmt.Errorf("informe bot_token e chat_ids na seção [telegram]")
+	}
+
+	icon := "✅"
+	if change.To == "red" {
+		icon = "🔴"
+	}
+	text := &strings.Builder{}
+	fmt.Fprintf(text, "%s <b>%s</b>\n", icon, html.EscapeString(change.title()))
+	if change.Service.Group != "" {
+		fmt.Fprintf(text, "%s: %s\n", tr("Group"), html.EscapeString(change.Service.Group))
+	}
+	fmt.Fprintf(text, "%s: <code>%s</code>\n", tr("Address"), html.EscapeString(change.address()))
+	fmt.Fprintf(text, "%s: %s\n", tr("Response time"), html.EscapeString(change.Service.ResponseTime))
+	fmt.Fprintf(text, "%s", change.durationText())
+	if change.Service.Message != "" {
+		fmt.Fprintf(text, "\n%s", html.EscapeString(change.Service.Message))
+	}
+
+	url := n.config.APIURL + "/bot" + n.config.BotToken + "/sendMessage"
+	var errs []string
+	for _, chatID := range chatIDs {
+		body, err := postJSON(url, nil, map[string]interface{}{
+			"chat_id":    strings.TrimSpace(chatID),
+			"text":       text.String(),
+			"parse_mode": "HTML",
+		})
+		var result struct {
+			OK          bool   `json:"ok"`
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(body, &result) == nil && !result.OK {
+			err = fmt.Errorf("%s", result.Description)
+		}
+		if err != nil {
+			// A URL da Bot API contém o token, que não deve aparecer no log
+			errs = append(errs, fmt.Sprintf("chat %s: %s", chatID, strings.ReplaceAll(err.Error(), n.config.BotToken, "***")))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
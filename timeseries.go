@@ -0,0 +1,102 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+const maxTimeseriesPoints = 2000 // Limite de pontos por consulta (range / step)
+
+// Ponto da série de um gráfico: um por step, inclusive os intervalos sem verificações
+type timeseriesPoint struct {
+	Time         time.Time `json:"time"`
+	Status       string    `json:"status"`                   // red se houve falha no intervalo; sem verificações, o status do histórico ou nodata
+	AvgLatencyMs *int64    `json:"avg_latency_ms"`           // null sem verificações no intervalo
+	MaxLatencyMs *int64    `json:"max_latency_ms"`           // null sem verificações no intervalo
+	Checks       int       `json:"checks"`                   // Verificações no intervalo
+	Failures     int       `json:"failures"`                 // Verificações com falha no intervalo
+	Uptime       *float64  `json:"uptime_percent,omitempty"` // Percentual de verificações bem-sucedidas
+}
+
+// Função para montar a série do serviço em [to-window, to), alinhada ao step, com um ponto por intervalo
+func buildTimeseries(description string, window, step time.Duration, to time.Time) []timeseriesPoint {
+	end := to.Truncate(step).Add(step) // Inclui o intervalo em andamento
+	start := end.Add(-window).Truncate(step)
+
+	points := []timeseriesPoint{}
+	for at := start; at.Before(end); at = at.Add(step) {
+		points = append(points, timeseriesPoint{Time: at, Status: "nodata"})
+	}
+	for _, bucket := range downsampleHistory(description, start, end, step) {
+		i := int(bucket.Time.Sub(start) / step)
+		if i < 0 || i >= len(points) {
+			continue
+		}
+		avg, peak := bucket.AvgLatencyMs, bucket.MaxLatencyMs
+		uptime := float64(bucket.Checks-bucket.Failures) / float64(bucket.Checks) * 100
+		points[i] = timeseriesPoint{
+			Time:         points[i].Time,
+			Status:       bucket.Status,
+			AvgLatencyMs: &avg,
+			MaxLatencyMs: &peak,
+			Checks:       bucket.Checks,
+			Failures:     bucket.Failures,
+			Uptime:       &uptime,
+		}
+	}
+
+	// Intervalos sem verificações assumem o status do histórico (ex.: paused)
+	for _, span := range historySpans(description, start, end) {
+		for i := range points {
+			if points[i].Checks == 0 && span.Start.Before(points[i].Time.Add(step)) && !span.End.Before(points[i].Time) {
+				points[i].Status = span.Status
+			}
+		}
+	}
+	return points
+}
+
+// Handler para a série de tempo de resposta e status de um serviço, pronta para gráficos
+// (range padrão 6h e step padrão 1m)
+func timeseriesHandler(w http.ResponseWriter, r *http.Request) {
+	service, ok := lookupService(r)
+	if !ok {
+		http.Error(w, "Serviço não encontrado", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	rangeParam, stepParam := query.Get("range"), query.Get("step")
+	window, err := parseRange(rangeParam, 6*time.Hour)
+	if err != nil {
+		http.Error(w, "Parâmetro range inválido", http.StatusBadRequest)
+		return
+	}
+	step, err := parseRange(stepParam, time.Minute)
+	if err != nil {
+		http.Error(w, "Parâmetro step inválido", http.StatusBadRequest)
+		return
+	}
+	if window/step > maxTimeseriesPoints {
+		http.Error(w, "Muitos pontos: aumente step ou reduza range", http.StatusBadRequest)
+		return
+	}
+
+	if rangeParam == "" {
+		rangeParam = "6h"
+	}
+	if stepParam == "" {
+		stepParam = "1m"
+	}
+
+	now := time.Now()
+	points := buildTimeseries(service.Description, window, step, now)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":          service.ID,
+		"service":     service.Description,
+		"range":       rangeParam,
+		"step":        stepParam,
+		"points":      points,
+		"annotations": serviceAnnotations(service, points[0].Time, now),
+	})
+}
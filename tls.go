@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [tls]
+type TLSConfig struct {
+	CertFile     string // Certificado (PEM, com a cadeia intermediária)
+	KeyFile      string // Chave privada (PEM)
+	RedirectPort string // Porta HTTP que redireciona para HTTPS (vazio desabilita)
+
+	ACME          bool     // Obtém e renova o certificado automaticamente via ACME
+	ACMEDomains   []string // Domínios do certificado
+	ACMEEmail     string   // Contato da conta ACME (avisos de vencimento)
+	ACMEDirectory string   // URL do diretório ACME (padrão: Let's Encrypt)
+	ACMECacheDir  string   // Diretório onde a conta e os certificados são guardados
+	ACMEChallenge string   // "http-01" (exige a porta 80) ou "dns-01"
+	ACMEDNSHook   string   // Script que cria/remove o registro TXT do desafio dns-01
+}
+
+// Função para ler a seção [tls] do config.ini
+func loadTLSConfig(cfg *ini.File) TLSConfig {
+	section := cfg.Section("tls")
+	config := TLSConfig{
+		CertFile:      section.Key("cert_file").String(),
+		KeyFile:       section.Key("key_file").String(),
+		RedirectPort:  section.Key("redirect_port").String(),
+		ACME:          section.Key("acme").MustBool(false),
+		ACMEDomains:   section.Key("acme_domains").Strings(","),
+		ACMEEmail:     section.Key("acme_email").String(),
+		ACMEDirectory: section.Key("acme_directory").MustString(acme.LetsEncryptURL),
+		ACMECacheDir:  section.Key("acme_cache_dir").MustString("certs"),
+		ACMEChallenge: section.Key("acme_challenge").MustString("http-01"),
+		ACMEDNSHook:   section.Key("acme_dns_hook").String(),
+	}
+	if config.ACME && config.ACMEChallenge == "http-01" && config.RedirectPort == "" {
+		config.RedirectPort = "80" // O desafio HTTP-01 é sempre validado na porta 80
+	}
+	return config
+}
+
+// Certificado carregado dos arquivos, recarregado quando os arquivos são alterados (ex.: renovação)
+type certLoader struct {
+	mu       sync.Mutex
+	certFile string
+	keyFile  string
+	modTime  time.Time
+	cert     *tls.Certificate
+}
+
+// Função para obter o certificado atual, relendo os arquivos se tiverem sido modificados
+func (l *certLoader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	certInfo, err := os.Stat(l.certFile)
+	if err != nil {
+		return l.cachedOrError(err)
+	}
+	keyInfo, err := os.Stat(l.keyFile)
+	if err != nil {
+		return l.cachedOrError(err)
+	}
+	modTime := certInfo.ModTime()
+	if keyInfo.ModTime().After(modTime) {
+		modTime = keyInfo.ModTime()
+	}
+	if l.cert != nil && !modTime.After(l.modTime) {
+		return l.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+	if err != nil {
+		log.Println(tr("Erro ao carregar o certificado TLS:"), err)
+		return l.cachedOrError(err)
+	}
+	if l.cert != nil {
+		log.Println(tr("Certificado TLS recarregado de"), l.certFile)
+	}
+	l.cert, l.modTime = &cert, modTime
+	return l.cert, nil
+}
+
+// Função auxiliar que mantém o último certificado válido enquanto os arquivos estão sendo trocados
+func (l *certLoader) cachedOrError(err error) (*tls.Certificate, error) {
+	if l.cert != nil {
+		return l.cert, nil
+	}
+	return nil, err
+}
+
+// Handler que redireciona as requisições HTTP para a porta HTTPS
+func httpsRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if serverPort != "443" {
+		host = net.JoinHostPort(host, serverPort)
+	}
+	http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+}
+
+// Função para iniciar o servidor principal em HTTP ou, com certificado configurado ou ACME, em HTTPS
+func listenAndServe(config TLSConfig, handler http.Handler) error {
+	addr := ":" + serverPort
+	if config.CertFile == "" && config.KeyFile == "" && !config.ACME {
+		log.Printf(tr("Servidor iniciado na porta :%s\n"), serverPort)
+		return registerServer(&http.Server{Addr: addr, Handler: handler}).ListenAndServe()
+	}
+
+	var tlsConfig *tls.Config
+	var redirect http.Handler = http.HandlerFunc(httpsRedirectHandler)
+	if config.ACME {
+		var err error
+		if tlsConfig, redirect, err = acmeTLSConfig(config); err != nil {
+			return fmt.Errorf("ACME: %w", err)
+		}
+	} else {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return fmt.Errorf("informe cert_file e key_file na seção [tls]")
+		}
+		loader := &certLoader{certFile: config.CertFile, keyFile: config.KeyFile}
+		if _, err := loader.getCertificate(nil); err != nil {
+			return fmt.Errorf("certificado TLS: %w", err)
+		}
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: loader.getCertificate}
+	}
+	server := registerServer(&http.Server{Addr: addr, Handler: handler, TLSConfig: tlsConfig})
+
+	if config.RedirectPort != "" {
+		redirectServer := registerServer(&http.Server{Addr: ":" + config.RedirectPort, Handler: redirect})
+		go func() {
+			log.Printf(tr("Redirecionamento HTTP → HTTPS na porta :%s\n"), config.RedirectPort)
+			if err := redirectServer.ListenAndServe(); err != nil && !serverClosed(err) {
+				log.Println(tr("Erro no redirecionamento HTTP:"), err)
+			}
+		}()
+	}
+
+	log.Printf(tr("Servidor HTTPS iniciado na porta :%s\n"), serverPort)
+	return server.ListenAndServeTLS("", "")
+}
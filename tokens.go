@@ -0,0 +1,197 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"gopkg.in/ini.v1"
+)
+
+// Escopos dos tokens de API
+const (
+	scopeRead  = "read"  // Papel viewer: apenas consultas (GET, WebSocket, GraphQL e Grafana)
+	scopeWrite = "write" // Papel operator: também pausar/retomar
+	scopeAdmin = "admin" // Papel admin: também emitir e revogar tokens
+)
+
+// Token de API usado por automações (Authorization: Bearer <token>)
+type apiToken struct {
+	Name    string     `json:"name"`
+	Scope   string     `json:"scope"`
+	Source  string     `json:"source"`            // "config" ou "api"
+	Created *time.Time `json:"created,omitempty"` // Apenas para tokens emitidos pela API
+	Groups  []string   `json:"groups,omitempty"`  // Grupos visíveis no WebSocket, no SSE e na API de status (vazio = todos)
+}
+
+var tokensMu sync.Mutex                    // Mutex para proteger os tokens emitidos pela API
+var issuedTokens = map[[32]byte]apiToken{} // Tokens emitidos pela API, indexados pelo hash SHA-256 (gravados no banco da seção [storage])
+var tokenHashes = map[string][32]byte{}    // Hash do token emitido pela API, indexado pelo nome
+
+// Função para ler a seção [tokens] do config.ini (nome=<token> scope=read|write|admin groups=Grupo1,Grupo2)
+func loadTokens(cfg *ini.File) map[[32]byte]apiToken {
+	tokens := map[[32]byte]apiToken{}
+	for _, key := range cfg.Section("tokens").Keys() {
+		fields := strings.Fields(key.Value())
+		if len(fields) == 0 {
+			log.Printf(tr("Token [%s] ignorado: valor não informado\n"), key.Name())
+			continue
+		}
+		token := apiToken{Name: key.Name(), Scope: scopeRead, Source: "config"}
+		for _, option := range fields[1:] {
+			if scope, ok := strings.CutPrefix(option, "scope="); ok {
+				token.Scope = scope
+			}
+			if groups, ok := strings.CutPrefix(option, "groups="); ok {
+				token.Groups = splitList(groups)
+			}
+		}
+		if !validScope(token.Scope) {
+			log.Printf(tr("Token [%s] ignorado: escopo inválido %q\n"), key.Name(), token.Scope)
+			continue
+		}
+		tokens[sha256.Sum256([]byte(fields[0]))] = token
+	}
+	return tokens
+}
+
+// Função para verificar se o escopo informado existe
+func validScope(scope string) bool {
+	return scope == scopeRead || scope == scopeWrite || scope == scopeAdmin
+}
+
+// Função para localizar o token informado na requisição
+func lookupToken(config AuthConfig, value string) (apiToken, bool) {
+	hash := sha256.Sum256([]byte(value))
+	if token, ok := config.Tokens[hash]; ok {
+		return token, true
+	}
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+	token, ok := issuedTokens[hash]
+	return token, ok
+}
+
+// Função para extrair o bearer token do cabeçalho Authorization (ou de ?access_token= no WebSocket, no SSE e no
+// widget incorporável, já que o navegador não permite cabeçalhos no handshake, no EventSource nem no iframe). No WebSocket, o token também
+// pode ser enviado como subprotocolo, sem aparecer na URL: new WebSocket(url, ["bearer", token]).
+func bearerToken(r *http.Request) (string, bool) {
+	if value, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(value), true
+	}
+	if r.URL.Path == "/ws" {
+		if protocols := websocket.Subprotocols(r); len(protocols) == 2 && protocols[0] == "bearer" {
+			return protocols[1], true
+		}
+	}
+	if (r.URL.Path == "/ws" || r.URL.Path == "/events" || r.URL.Path == "/embed") && r.URL.Query().Has("access_token") {
+		return r.URL.Query().Get("access_token"), true
+	}
+	return "", false
+}
+
+// Função para gerar um novo token aleatório
+func generateToken() string {
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Handler para listar os tokens (sem os valores)
+func listTokensHandler(w http.ResponseWriter, r *http.Request) {
+	tokens := []apiToken{}
+	for _, token := range getConfig().Auth.Tokens {
+		tokens = append(tokens, token)
+	}
+	tokensMu.Lock()
+	for _, token := range issuedTokens {
+		tokens = append(tokens, token)
+	}
+	tokensMu.Unlock()
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Name < tokens[j].Name })
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// Handler para emitir um token; o valor só é exibido nesta resposta
+func createTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name   string   `json:"name"`
+		Scope  string   `json:"scope"`
+		Groups []string `json:"groups"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+	if request.Scope == "" {
+		request.Scope = scopeRead
+	}
+	if request.Name == "" || !validScope(request.Scope) {
+		http.Error(w, "Informe name e scope (read, write ou admin)", http.StatusBadRequest)
+		return
+	}
+	for _, token := range getConfig().Auth.Tokens {
+		if token.Name == request.Name {
+			http.Error(w, "Já existe um token com esse nome", http.StatusConflict)
+			return
+		}
+	}
+
+	value := generateToken()
+	hash := sha256.Sum256([]byte(value))
+	created := time.Now()
+	token := apiToken{Name: request.Name, Scope: request.Scope, Source: "api", Created: &created, Groups: request.Groups}
+
+	tokensMu.Lock()
+	if _, exists := tokenHashes[request.Name]; exists {
+		tokensMu.Unlock()
+		http.Error(w, "Já existe um token com esse nome", http.StatusConflict)
+		return
+	}
+	issuedTokens[hash] = token
+	tokenHashes[request.Name] = hash
+	tokensMu.Unlock()
+
+	log.Printf(tr("Token [%s] (%s) emitido por %s\n"), token.Name, token.Scope, currentUser(r))
+	if storage != nil {
+		saveToken(hash, token)
+	} else {
+		log.Printf(tr("Token [%s] mantido apenas na memória: sem a persistência da seção [storage], será perdido ao reiniciar\n"), token.Name)
+	}
+	auditRequest(r, "token.create", token.Name, nil, map[string]interface{}{"scope": token.Scope, "groups": token.Groups})
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"name":   token.Name,
+		"scope":  token.Scope,
+		"groups": token.Groups,
+		"token":  value,
+	})
+}
+
+// Handler para revogar um token emitido pela API
+func deleteTokenHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	tokensMu.Lock()
+	hash, ok := tokenHashes[name]
+	if ok {
+		delete(issuedTokens, hash)
+		delete(tokenHashes, name)
+	}
+	tokensMu.Unlock()
+
+	if !ok {
+		http.Error(w, "Token não encontrado (tokens do config.ini só podem ser removidos no arquivo)", http.StatusNotFound)
+		return
+	}
+	log.Printf(tr("Token [%s] revogado por %s\n"), name, currentUser(r))
+	deleteToken(name)
+	auditRequest(r, "token.revoke", name, nil, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
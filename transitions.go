@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+var transitionsMu sync.Mutex                                // Mutex para proteger os assinantes
+var transitionSubscribers []func(statusTransition, Service) // Consumidores das mudanças de status, na ordem de inscrição
+
+// Função para inscrever um consumidor das mudanças de status (WebSocket, SSE, TSDB). Os consumidores são
+// chamados em sequência pelo monitor e não devem bloquear.
+func subscribeTransitions(consumer func(statusTransition, Service)) {
+	transitionsMu.Lock()
+	defer transitionsMu.Unlock()
+	transitionSubscribers = append(transitionSubscribers, consumer)
+}
+
+// Função para publicar a mudança de status de um serviço a todos os consumidores. O status inicial
+// (unknown, antes da primeira verificação) não gera evento.
+func publishTransition(service Service, from, to, reason string, at time.Time) {
+	if from == to || from == "unknown" || from == "" {
+		return
+	}
+	t := statusTransition{ServiceID: service.ID, Service: service.Description, Group: service.Group, From: from, To: to, Time: at, Reason: reason}
+	log.Printf(tr("Serviço [%s] mudou de %s para %s: %s\n"), t.Service, from, to, reason)
+
+	transitionsMu.Lock()
+	consumers := transitionSubscribers
+	transitionsMu.Unlock()
+	for _, consumer := range consumers {
+		consumer(t, service)
+	}
+}
+
+// Função para inscrever os consumidores internos das mudanças de status
+func setupTransitions() {
+	subscribeTransitions(hub.transition)
+	subscribeTransitions(exportTransition)
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"gopkg.in/ini.v1"
+)
+
+// Configurações da seção [tsdb]
+type TSDBConfig struct {
+	Enabled       bool
+	Format        string // influx (line protocol) ou remote_write (Prometheus remote-write)
+	URL           string // Ex.: http://influx:8086/api/v2/write?org=empresa&bucket=monitor ou http://victoria:8428/api/v1/write
+	Token         string // Token do InfluxDB 2 (cabeçalho Authorization: Token ...)
+	Username      string // Autenticação básica (remote-write e InfluxDB 1)
+	Password      string
+	FlushInterval time.Duration // Intervalo entre os envios
+	Prefix        string        // Prefixo das métricas e measurements
+}
+
+// Função para ler a seção [tsdb] do config.ini
+func loadTSDBConfig(cfg *ini.File) TSDBConfig {
+	section := cfg.Section("tsdb")
+	config := TSDBConfig{
+		Enabled:  section.Key("enabled").MustBool(false),
+		Format:   section.Key("format").MustString("influx"),
+		URL:      section.Key("url").String(),
+		Token:    section.Key("token").String(),
+		Username: section.Key("username").String(),
+		Password: section.Key("password").String(),
+		Prefix:   section.Key("prefix").MustString("web_check"),
+	}
+	var err error
+	if config.FlushInterval, err = parseRange(section.Key("flush_interval").String(), 10*time.Second); err != nil {
+		log.Println(tr("flush_interval inválido na seção [tsdb], usando 10s"))
+		config.FlushInterval = 10 * time.Second
+	}
+	return config
+}
+
+// Ponto enviado ao banco de séries temporais
+type tsdbPoint struct {
+	Service string
+	Group   string
+	Time    time.Time
+	Status  string
+	Latency int64
+	From    string // Status anterior, apenas em mudanças de status
+}
+
+const maxTSDBBuffer = 50000 // Pontos mantidos enquanto o banco estiver indisponível; além disso, os mais antigos são descartados
+
+var tsdbMu sync.Mutex      // Mutex para proteger o buffer
+var tsdbBuffer []tsdbPoint // Pontos aguardando envio
+
+// Função para enfileirar o resultado de uma verificação
+func exportCheck(service Service, status string, latency int64, at time.Time) {
+	if status == "green" || status == "red" {
+		bufferTSDB(tsdbPoint{Service: service.Description, Group: service.Group, Time: at, Status: status, Latency: latency})
+	}
+}
+
+// Função para enfileirar uma mudança de status recebida do barramento de eventos
+func exportTransition(t statusTransition, service Service) {
+	bufferTSDB(tsdbPoint{Service: t.Service, Group: t.Group, Time: t.Time, Status: t.To, From: t.From})
+}
+
+// Função para incluir um ponto no buffer, descartando os mais antigos além do limite
+func bufferTSDB(point tsdbPoint) {
+	if !getConfig().TSDB.Enabled {
+		return
+	}
+	tsdbMu.Lock()
+	defer tsdbMu.Unlock()
+	tsdbBuffer = append(tsdbBuffer, point)
+	if len(tsdbBuffer) > maxTSDBBuffer {
+		tsdbBuffer = tsdbBuffer[len(tsdbBuffer)-maxTSDBBuffer:]
+	}
+}
+
+// Função para enviar os pontos acumulados periodicamente; em caso de erro, os pontos são mantidos
+// para a próxima tentativa. As alterações da seção [tsdb] valem sem reiniciar o processo.
+func runTSDBExporter() {
+	for {
+		config := getConfig().TSDB
+		time.Sleep(config.FlushInterval)
+		flushTSDB(config)
+	}
+}
+
+// Função para enviar os pontos acumulados (também ao encerrar o processo); em caso de erro, os pontos voltam
+// ao buffer
+func flushTSDB(config TSDBConfig) {
+	if !config.Enabled {
+		return
+	}
+
+	tsdbMu.Lock()
+	points := tsdbBuffer
+	tsdbBuffer = nil
+	tsdbMu.Unlock()
+	if len(points) == 0 {
+		return
+	}
+
+	if err := writeTSDB(config, points); err != nil {
+		log.Printf(tr("Erro ao enviar %d pontos ao banco de séries temporais: %v\n"), len(points), err)
+		tsdbMu.Lock()
+		tsdbBuffer = append(points, tsdbBuffer...)
+		if len(tsdbBuffer) > maxTSDBBuffer {
+			tsdbBuffer = tsdbBuffer[len(tsdbBuffer)-maxTSDBBuffer:]
+		}
+		tsdbMu.Unlock()
+	}
+}
+
+// Função para enviar os pontos no formato configurado
+func writeTSDB(config TSDBConfig, points []tsdbPoint) error {
+	if config.URL == "" {
+		return fmt.Errorf("informe url na seção [tsdb]")
+	}
+	headers := map[string]string{}
+	if config.Token != "" {
+		headers["Authorization"] = "Token " + config.Token
+	} else if config.Username != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(config.Username+":"+config.Password))
+	}
+
+	switch config.Format {
+	case "influx":
+		headers["Content-Type"] = "text/plain; charset=utf-8"
+		_, err := postBody(config.URL, headers, influxLines(config.Prefix, points))
+		return err
+	case "remote_write":
+		headers["Content-Type"] = "application/x-protobuf"
+		headers["Content-Encoding"] = "snappy"
+		headers["X-Prometheus-Remote-Write-Version"] = "0.1.0"
+		_, err := postBody(config.URL, headers, snappy.Encode(nil, remoteWriteRequest(config.Prefix, points)))
+		return err
+	}
+	return fmt.Errorf("format desconhecido %q (use influx ou remote_write)", config.Format)
+}
+
+// Função para escapar tags no line protocol do InfluxDB
+var influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+
+// Função para montar os pontos no line protocol do InfluxDB (precisão em nanossegundos):
+// <prefixo> (tempo de resposta e disponibilidade) e <prefixo>_status_change (mudanças de status)
+func influxLines(prefix string, points []tsdbPoint) []byte {
+	var lines strings.Builder
+	for _, p := range points {
+		tags := "service=" + influxTagEscaper.Replace(p.Service)
+		if p.Group != "" {
+			tags += ",group=" + influxTagEscaper.Replace(p.Group)
+		}
+		if p.From != "" {
+			fmt.Fprintf(&lines, "%s_status_change,%s from=%q,to=%q %d\n", prefix, tags, p.From, p.Status, p.Time.UnixNano())
+			continue
+		}
+		up := 0
+		if p.Status == "green" {
+			up = 1
+		}
+		fmt.Fprintf(&lines, "%s,%s latency_ms=%di,up=%di %d\n", prefix, tags, p.Latency, up, p.Time.UnixNano())
+	}
+	return []byte(lines.String())
+}
+
+// Série temporal do remote-write: labels ordenados e amostras em ordem de tempo
+type remoteSeries struct {
+	labels  [][2]string
+	samples [][2]float64 // Valor e timestamp em milissegundos
+}
+
+// Função para montar o WriteRequest do Prometheus remote-write (protobuf, sem compressão) com as séries
+// <prefixo>_latency_ms, <prefixo>_up e <prefixo>_status_change (valor 1 a cada mudança, com os labels from e to)
+func remoteWriteRequest(prefix string, points []tsdbPoint) []byte {
+	series := map[string]*remoteSeries{}
+	keys := []string{}
+	add := func(name string, labels map[string]string, value float64, at time.Time) {
+		labels["__name__"] = name
+		pairs := [][2]string{}
+		for k, v := range labels {
+			if v != "" {
+				pairs = append(pairs, [2]string{k, v})
+			}
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+		key := fmt.Sprint(pairs)
+		s, ok := series[key]
+		if !ok {
+			s = &remoteSeries{labels: pairs}
+			series[key] = s
+			keys = append(keys, key)
+		}
+		s.samples = append(s.samples, [2]float64{value, float64(at.UnixMilli())})
+	}
+
+	for _, p := range points {
+		if p.From != "" {
+			add(prefix+"_status_change", map[string]string{"service": p.Service, "group": p.Group, "from": p.From, "to": p.Status}, 1, p.Time)
+			continue
+		}
+		up := 0.0
+		if p.Status == "green" {
+			up = 1
+		}
+		add(prefix+"_latency_ms", map[string]string{"service": p.Service, "group": p.Group}, float64(p.Latency), p.Time)
+		add(prefix+"_up", map[string]string{"service": p.Service, "group": p.Group}, up, p.Time)
+	}
+
+	request := []byte{}
+	for _, key := range keys {
+		s := series[key]
+		ts := []byte{}
+		for _, label := range s.labels {
+			l := protoString(nil, 1, label[0])
+			l = protoString(l, 2, label[1])
+			ts = protoBytes(ts, 1, l)
+		}
+		for _, sample := range s.samples {
+			v := binary.AppendUvarint(nil, 1<<3|1) // Campo 1 (value), tipo fixed64
+			v = binary.LittleEndian.AppendUint64(v, math.Float64bits(sample[0]))
+			v = binary.AppendUvarint(v, 2<<3|0) // Campo 2 (timestamp), tipo varint
+			v = binary.AppendUvarint(v, uint64(int64(sample[1])))
+			ts = protoBytes(ts, 2, v)
+		}
+		request = protoBytes(request, 1, ts)
+	}
+	return request
+}
+
+// Função para acrescentar um campo protobuf do tipo bytes (length-delimited)
+func protoBytes(buffer []byte, field int, value []byte) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(field)<<3|2)
+	buffer = binary.AppendUvarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}
+
+// Função para acrescentar um campo protobuf do tipo string
+func protoString(buffer []byte, field int, value string) []byte {
+	return protoBytes(buffer, field, []byte(value))
+}
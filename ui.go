@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configuração da aparência do dashboard (seção [ui]), entregue ao front-end por /api/ui-config
+type UIConfig struct {
+	Theme       string  `json:"theme"`                  // auto (segue o sistema), light ou dark
+	AccentColor string  `json:"accent_color,omitempty"` // Cor do título e dos destaques
+	UpColor     string  `json:"up_color,omitempty"`     // Cor dos serviços online
+	DownColor   string  `json:"down_color,omitempty"`   // Cor dos serviços offline
+	PausedColor string  `json:"paused_color,omitempty"` // Cor dos serviços pausados
+	FontScale   float64 `json:"font_scale"`             // Multiplicador do tamanho das fontes (ex.: 1.5 em TVs)
+	Sort        string  `json:"sort"`                   // Ordem dos serviços: config, name, group, status ou manual
+
+	StatusFavicon bool `json:"status_favicon"` // Favicon do dashboard na cor do estado agregado (/favicon.svg)
+}
+
+// Cores aceitas: hexadecimais (#4caf50) ou nomes (orange)
+var colorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]+)$`)
+
+// Função para ler a seção [ui] do config.ini
+func loadUIConfig(cfg *ini.File) UIConfig {
+	section := cfg.Section("ui")
+	config := UIConfig{
+		Theme:     section.Key("theme").In("auto", []string{"auto", "light", "dark"}),
+		FontScale: section.Key("font_scale").MustFloat64(1),
+		Sort:      section.Key("sort").In("config", sortModes),
+
+		StatusFavicon: section.Key("status_favicon").MustBool(true),
+	}
+	if config.FontScale < 0.5 || config.FontScale > 3 {
+		log.Println(tr("font_scale inválido na seção [ui] (use 0.5 a 3), usando 1"))
+		config.FontScale = 1
+	}
+	for key, field := range map[string]*string{"accent_color": &config.AccentColor, "up_color": &config.UpColor, "down_color": &config.DownColor, "paused_color": &config.PausedColor} {
+		value := section.Key(key).String()
+		if value != "" && !colorPattern.MatchString(value) {
+			log.Printf(tr("%s inválido na seção [ui] (use #rrggbb ou o nome da cor), usando a cor padrão\n"), key)
+			continue
+		}
+		*field = value
+	}
+	return config
+}
+
+// Resposta de /api/ui-config: a aparência e os textos do cabeçalho, que identificam a instância
+type uiConfigResponse struct {
+	UIConfig
+	Title         string  `json:"title"`
+	Subtitle      string  `json:"subtitle,omitempty"`
+	LogoText      string  `json:"logo_text,omitempty"`
+	RefreshHint   string  `json:"refresh_hint,omitempty"`
+	CheckInterval float64 `json:"check_interval_seconds"`
+	PushInterval  float64 `json:"push_interval_seconds"`
+}
+
+// Função para montar o aviso do intervalo de atualização exibido no dashboard
+func refreshHint(config *Config) string {
+	switch config.Branding.Refresh {
+	case "none":
+		return ""
+	case "auto", "":
+		return fmt.Sprintf(tr("Checked every %s · full refresh every %s"), formatDuration(config.Interval), formatDuration(config.PushInterval))
+	}
+	return config.Branding.Refresh
+}
+
+// Handler para a configuração de aparência e os textos do cabeçalho do dashboard
+func uiConfigHandler(w http.ResponseWriter, r *http.Request) {
+	config := getConfig()
+	writeJSON(w, http.StatusOK, uiConfigResponse{
+		UIConfig:      config.UI,
+		Title:         config.Branding.Title,
+		Subtitle:      config.Branding.Subtitle,
+		LogoText:      config.Branding.LogoText,
+		RefreshHint:   refreshHint(config),
+		CheckInterval: config.Interval.Seconds(),
+		PushInterval:  config.PushInterval.Seconds(),
+	})
+}
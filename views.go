@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Visão nomeada do dashboard (seção [view.<nome>]), exibida em /view/<nome> com apenas os serviços selecionados,
+// para que cada time ou TV acompanhe só o que lhe interessa
+type ViewConfig struct {
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`              // Título da página (vazio = o title da seção [branding])
+	Groups   []string `json:"groups,omitempty"`   // Grupos exibidos, na ordem em que as seções aparecem na visão
+	Tags     []string `json:"tags,omitempty"`     // Tags da opção tags= dos serviços
+	Services []string `json:"services,omitempty"` // Serviços incluídos pelo nome, além dos selecionados por grupo e tag
+	Sort     string   `json:"sort,omitempty"`     // Ordem dos serviços (como o sort da seção [ui]; vazio = o da seção [ui])
+}
+
+// Função para ler as seções [view.<nome>] do config.ini
+func loadViewConfigs(cfg *ini.File) []ViewConfig {
+	views := []ViewConfig{}
+	for _, section := range cfg.Sections() {
+		name, ok := strings.CutPrefix(section.Name(), "view.")
+		if !ok || name == "" {
+			continue
+		}
+		if strings.Contains(name, "/") {
+			log.Printf(tr("Nome de visão inválido [%s], ignorada\n"), section.Name())
+			continue
+		}
+		views = append(views, ViewConfig{
+			Name:     name,
+			Title:    section.Key("title").String(),
+			Groups:   splitList(section.Key("groups").String()),
+			Tags:     splitList(section.Key("tags").String()),
+			Services: splitList(section.Key("services").String()),
+			Sort:     section.Key("sort").In("", sortModes),
+		})
+	}
+	return views
+}
+
+// Função para localizar uma visão pelo nome
+func findView(name string) (ViewConfig, bool) {
+	for _, view := range getConfig().Views {
+		if view.Name == name {
+			return view, true
+		}
+	}
+	return ViewConfig{}, false
+}
+
+// Função para obter a assinatura com os serviços da visão (uma visão removida do config.ini não exibe nenhum serviço)
+func viewFilter(name string) *wsFilter {
+	view, ok := findView(name)
+	if !ok {
+		return &wsFilter{Services: []string{""}}
+	}
+	return &wsFilter{Groups: view.Groups, Tags: view.Tags, Services: view.Services}
+}
+
+// Handler para o dashboard de uma visão
+func viewHandler(w http.ResponseWriter, r *http.Request) {
+	view, ok := findView(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	page := brandingPage(getConfig().Branding)
+	page.View = &view
+	if view.Title != "" {
+		page.Title = view.Title
+	}
+	renderDashboard(w, page)
+}
+
+// Handler para listar as visões configuradas
+func listViewsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, getConfig().Views)
+}
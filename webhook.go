@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// Configurações de um webhook genérico, da seção [webhook.<nome>]
+type WebhookConfig struct {
+	Name            string
+	Enabled         bool
+	URL             string
+	Headers         map[string]string  // Cabeçalhos adicionais (chaves header.<nome>)
+	Secret          string             // Chave da assinatura HMAC-SHA256 do corpo (vazio desabilita)
+	SignatureHeader string             // Cabeçalho com a assinatura, no formato sha256=<hex>
+	Template        *template.Template // Template do corpo; sem template, envia o payload padrão
+}
+
+// Funções disponíveis nos templates dos webhooks
+var webhookTemplateFuncs = template.FuncMap{
+	// json serializa o valor, permitindo inserir textos com aspas e quebras de linha com segurança
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// Função para ler as seções [webhook.<nome>] do config.ini
+func loadWebhookConfigs(cfg *ini.File) []WebhookConfig {
+	webhooks := []WebhookConfig{}
+	for _, section := range cfg.Section("webhook").ChildSections() {
+		config := WebhookConfig{
+			Name:            strings.TrimPrefix(section.Name(), "webhook."),
+			Enabled:         section.Key("enabled").MustBool(true),
+			URL:             section.Key("url").String(),
+			Headers:         map[string]string{},
+			Secret:          section.Key("secret").String(),
+			SignatureHeader: section.Key("signature_header").MustString("X-Signature-256"),
+		}
+		for _, key := range section.Keys() {
+			if name, ok := strings.CutPrefix(key.Name(), "header."); ok {
+				config.Headers[name] = key.String()
+			}
+		}
+
+		text := section.Key("template").String()
+		if file := section.Key("template_file").String(); file != "" {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				log.Printf(tr("Webhook [%s] desabilitado, erro ao ler template_file: %v\n"), config.Name, err)
+				continue
+			}
+			text = string(data)
+		}
+		if text != "" {
+			tmpl, err := template.New(config.Name).Funcs(webhookTemplateFuncs).Parse(text)
+			if err != nil {
+				log.Printf(tr("Webhook [%s] desabilitado, template inválido: %v\n"), config.Name, err)
+				continue
+			}
+			config.Template = tmpl
+		}
+		webhooks = append(webhooks, config)
+	}
+	return webhooks
+}
+
+// Dados da mudança de status enviados no payload padrão e disponíveis nos templates
+type webhookPayload struct {
+	Event           string    `json:"event"`            // "down" ou "up"
+	Repeat          bool      `json:"repeat,omitempty"` // Repetição de uma queda ainda não reconhecida
+	Service         string    `json:"service"`
+	Group           string    `json:"group,omitempty"`
+	Address         string    `json:"address"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	ResponseTime    string    `json:"response_time"`
+	ResponseTimeMs  int64     `json:"response_time_ms"`
+	DurationSeconds int64     `json:"duration_seconds"` // Tempo no status anterior (na recuperação, o tempo fora do ar)
+	FirstFailure    time.Time `json:"first_failure"`    // Primeira falha da queda
+	Message         string    `json:"message,omitempty"`
+	Time            time.Time `json:"time"`
+	DashboardURL    string    `json:"dashboard_url,omitempty"`
+}
+
+// Notificação por webhook genérico
+type webhookNotifier struct {
+	config WebhookConfig
+}
+
+func (n webhookNotifier) Name() string { return "webhook " + n.config.Name }
+
+// Função para montar os dados da mudança de status usados no payload e nos templates
+func newWebhookPayload(change stateChange) webhookPayload {
+	payload := webhookPayload{
+		Event:           "up",
+		Repeat:          change.Repeat,
+		Service:         change.Service.Description,
+		Group:           change.Service.Group,
+		Address:         change.address(),
+		From:            change.From,
+		To:              change.To,
+		ResponseTime:    change.Service.ResponseTime,
+		ResponseTimeMs:  change.Service.LatencyMs,
+		DurationSeconds: int64(change.Duration.Seconds()),
+		FirstFailure:    change.FirstFailure,
+		Message:         change.Service.Message,
+		Time:            change.Time,
+		DashboardURL:    dashboardURL(),
+	}
+	if change.To == "red" {
+		payload.Event = "down"
+	}
+	return payload
+}
+
+// Função para enviar o payload (padrão ou gerado pelo template), assinado quando há secret
+func (n webhookNotifier) Notify(change stateChange) error {
+	if n.config.URL == "" {
+		return fmt.Errorf("informe url na seção [webhook.%s]", n.config.Name)
+	}
+	payload := newWebhookPayload(change)
+
+	var body []byte
+	if n.config.Template != nil {
+		buffer := &bytes.Buffer{}
+		if err := n.config.Template.Execute(buffer, payload); err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+		if !json.Valid(buffer.Bytes()) {
+			return fmt.Errorf("o template não gerou um JSON válido: %s", buffer.String())
+		}
+		body = buffer.Bytes()
+	} else {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	headers := map[string]string{}
+	for name, value := range n.config.Headers {
+		headers[name] = value
+	}
+	if n.config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.config.Secret))
+		mac.Write(body)
+		headers[n.config.SignatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	_, err := postBody(n.config.URL, headers, body)
+	return err
+}